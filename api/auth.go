@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+)
+
+const (
+	authorizationHeader = "Authorization"
+	claimsKey           = "claims"
+	accessTokenKey      = "accessToken"
+	adminGroup          = "admin"
+)
+
+var (
+	errMissingToken = errors.New("missing bearer token")
+	errNotAccess    = errors.New("bearer token is not an access token")
+	errForbidden    = errors.New("insufficient permissions")
+)
+
+// authenticate requires a Cognito access token issued to the selected app
+// client and exposes its claims to the handlers that follow.
+func (s *Server) authenticate(ctx *gin.Context) {
+	tokenString, ok := strings.CutPrefix(ctx.GetHeader(authorizationHeader), "Bearer ")
+	if !ok || tokenString == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(errMissingToken))
+		return
+	}
+
+	t, err := s.validateToken(ctx, appClient(ctx).ClientID, tokenString)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to validate access token", "error", err)
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	// ID tokens pass validateToken too, through aud, but they name the user
+	// differently and Cognito won't accept them in place of an access token.
+	claims, _ := t.Claims.(jwt.MapClaims)
+	if claims["token_use"] != "access" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(errNotAccess))
+		return
+	}
+	ctx.Set(claimsKey, claims)
+	ctx.Set(accessTokenKey, t.Raw)
+	if username, ok := claims["username"].(string); ok {
+		ctx.Set(usernameKey, username)
+	}
+	ctx.Next()
+}
+
+// requireGroup lets the request through only if the authenticated user is a
+// member of the given Cognito group.
+func (s *Server) requireGroup(group string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		groups, _ := tokenClaims(ctx)["cognito:groups"].([]interface{})
+		for _, g := range groups {
+			if g == group {
+				ctx.Next()
+				return
+			}
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(errForbidden))
+	}
+}
+
+func tokenClaims(ctx *gin.Context) jwt.MapClaims {
+	claims, _ := ctx.Get(claimsKey)
+	c, _ := claims.(jwt.MapClaims)
+	return c
+}
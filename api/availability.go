@@ -0,0 +1,116 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+)
+
+const (
+	availabilityCacheTTL  = 30 * time.Second
+	availabilityCacheSize = 10000
+)
+
+var errAvailabilityUnknown = errors.New("failed to check username availability")
+
+type availabilityEntry struct {
+	available bool
+	expiresAt time.Time
+}
+
+// availabilityCache remembers recent lookups so a player typing a name does
+// not turn into one AdminGetUser call per keystroke.
+type availabilityCache struct {
+	mu      sync.Mutex
+	entries map[string]availabilityEntry
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+func newAvailabilityCache(ttl time.Duration) *availabilityCache {
+	return &availabilityCache{
+		entries: make(map[string]availabilityEntry),
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+func (c *availabilityCache) get(key string) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expiresAt) {
+		return false, false
+	}
+	return e.available, true
+}
+
+func (c *availabilityCache) set(key string, available bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= availabilityCacheSize {
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= availabilityCacheSize {
+			c.entries = make(map[string]availabilityEntry)
+		}
+	}
+	c.entries[key] = availabilityEntry{available: available, expiresAt: now.Add(c.ttl)}
+}
+
+type availabilityRequest struct {
+	Username string `form:"username" binding:"required,alphanum"`
+}
+
+type availabilityResponse struct {
+	Username  string `json:"username"`
+	Available bool   `json:"available"`
+}
+
+// checkAvailability tells the player whether a username can still be taken.
+// Names that break the policy are reported as validation errors; otherwise
+// the only thing disclosed is whether the name is free.
+func (s *Server) checkAvailability(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	var req availabilityRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to bind request", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if violations := s.usernames.Validate("username", req.Username); len(violations) > 0 {
+		ctx.JSON(http.StatusBadRequest, validationErrorResponse(violations))
+		return
+	}
+
+	key := normalizeUsername(req.Username)
+	available, ok := s.availability.get(key)
+	if !ok {
+		exists, err := s.cognitoAuthService.UserExists(ctx, s.config.Cognito.UserPoolID, req.Username)
+		if err != nil {
+			logger.Error("Failed to check username availability", "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(errAvailabilityUnknown))
+			return
+		}
+		available = !exists
+		s.availability.set(key, available)
+	}
+
+	ctx.Header("Cache-Control", "private, max-age=30")
+	ctx.JSON(http.StatusOK, successResponse(availabilityResponse{
+		Username:  req.Username,
+		Available: available,
+	}))
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/username"
+)
+
+func TestServer_checkAvailability(t *testing.T) {
+	tests := []struct {
+		name          string
+		usernames     []string
+		buildStubs    func(authSvc *caws.MockCognitoAuthService)
+		wantStatus    int
+		wantAvailable bool
+		wantCodes     []string
+	}{
+		{
+			name:      "Available",
+			usernames: []string{"test"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().UserExists(mock.Anything, "us-east-1_example", "test").Return(false, nil).Once()
+			},
+			wantStatus:    http.StatusOK,
+			wantAvailable: true,
+		},
+		{
+			name:      "Taken",
+			usernames: []string{"test"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().UserExists(mock.Anything, "us-east-1_example", "test").Return(true, nil).Once()
+			},
+			wantStatus:    http.StatusOK,
+			wantAvailable: false,
+		},
+		{
+			name:      "Cached",
+			usernames: []string{"test", "Test", "TEST"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().UserExists(mock.Anything, "us-east-1_example", "test").Return(true, nil).Once()
+			},
+			wantStatus:    http.StatusOK,
+			wantAvailable: false,
+		},
+		{
+			name:          "Reserved",
+			usernames:     []string{"admin"},
+			buildStubs:    func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus:    http.StatusBadRequest,
+			wantAvailable: false,
+			wantCodes:     []string{username.CodeReserved},
+		},
+		{
+			name:          "Missing Username",
+			usernames:     []string{""},
+			buildStubs:    func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus:    http.StatusBadRequest,
+			wantAvailable: false,
+		},
+		{
+			name:      "Lookup Failed",
+			usernames: []string{"test", "test"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().UserExists(mock.Anything, "us-east-1_example", "test").Return(false, errors.New("server is busy")).Twice()
+			},
+			wantStatus:    http.StatusInternalServerError,
+			wantAvailable: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			tt.buildStubs(cognitoAuthService)
+
+			testServer := newTestServer(t, cognitoAuthService)
+
+			for _, name := range tt.usernames {
+				request, err := http.NewRequest(http.MethodGet, "/v1/users/availability?username="+name, nil)
+				require.NoError(t, err)
+
+				recorder := httptest.NewRecorder()
+				testServer.engine.ServeHTTP(recorder, request)
+				require.Equal(t, tt.wantStatus, recorder.Code)
+
+				var resp struct {
+					Data   availabilityResponse `json:"data"`
+					Errors []struct {
+						Code string `json:"code"`
+					} `json:"errors"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantAvailable, resp.Data.Available)
+
+				var codes []string
+				for _, e := range resp.Errors {
+					codes = append(codes, e.Code)
+				}
+				assert.Equal(t, tt.wantCodes, codes)
+			}
+		})
+	}
+}
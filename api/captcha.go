@@ -0,0 +1,51 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/whatisusername/toon-tank-user-service/internal/captcha"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+)
+
+// captchaLoginFailures is how many recent failed logins an account may have
+// before further attempts must carry a CAPTCHA token.
+const captchaLoginFailures = 3
+
+const (
+	reasonCaptchaRequired    = "CaptchaRequired"
+	reasonCaptchaFailed      = "CaptchaFailed"
+	reasonCaptchaUnavailable = "CaptchaUnavailable"
+)
+
+var (
+	errCaptchaRequired    = errors.New("captcha token is required")
+	errCaptchaUnavailable = errors.New("captcha verification is unavailable, please try again later")
+)
+
+// verifyCaptcha checks token with the configured provider and responds on
+// failure, returning the metric reason. An empty reason means the token was
+// accepted. Provider outages fail closed, otherwise bots could simply wait
+// for one.
+func (s *Server) verifyCaptcha(ctx *gin.Context, token string) string {
+	logger := logging.FromContext(ctx)
+
+	if token == "" {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errCaptchaRequired))
+		return reasonCaptchaRequired
+	}
+
+	err := s.captcha.Verify(ctx, token, sourceIP(ctx))
+	switch {
+	case errors.Is(err, captcha.ErrVerificationFailed):
+		logger.Warn("Captcha rejected", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(captcha.ErrVerificationFailed))
+		return reasonCaptchaFailed
+	case err != nil:
+		logger.Error("Failed to verify captcha", "error", err)
+		ctx.JSON(http.StatusServiceUnavailable, errorResponse(errCaptchaUnavailable))
+		return reasonCaptchaUnavailable
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/captcha"
+	"github.com/whatisusername/toon-tank-user-service/internal/lockout"
+)
+
+type unavailableVerifier struct{}
+
+func (unavailableVerifier) Verify(context.Context, string, string) error {
+	return errors.New("connection refused")
+}
+
+func TestServer_createUserCaptcha(t *testing.T) {
+	tests := []struct {
+		name       string
+		verifier   captcha.CaptchaVerifier
+		body       string
+		buildStubs func(authSvc *caws.MockCognitoAuthService)
+		wantStatus int
+	}{
+		{
+			name:     "OK",
+			verifier: captcha.FakeVerifier{Token: "fake_captcha_token"},
+			body:     `{"username":"test","email":"test@example.com","password":"test123456A","captchaToken":"fake_captcha_token"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+					Return(nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "Missing Token",
+			verifier:   captcha.FakeVerifier{Token: "fake_captcha_token"},
+			body:       `{"username":"test","email":"test@example.com","password":"test123456A"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Rejected Token",
+			verifier:   captcha.FakeVerifier{Token: "fake_captcha_token"},
+			body:       `{"username":"test","email":"test@example.com","password":"test123456A","captchaToken":"bot"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Provider Unavailable",
+			verifier:   unavailableVerifier{},
+			body:       `{"username":"test","email":"test@example.com","password":"test123456A","captchaToken":"fake_captcha_token"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			tt.buildStubs(cognitoAuthService)
+
+			testServer := newTestServer(t, cognitoAuthService, WithCaptchaVerifier(tt.verifier))
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/users", strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			testServer.engine.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+		})
+	}
+}
+
+func TestServer_loginUserCaptcha(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int
+		body       string
+		buildStubs func(authSvc *caws.MockCognitoAuthService)
+		wantStatus int
+	}{
+		{
+			name:     "Not Required Below Threshold",
+			failures: captchaLoginFailures - 1,
+			body:     `{"username":"test","password":"wrong"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "wrong").
+					Return(nil, &smithy.GenericAPIError{Code: "NotAuthorizedException"}).Once()
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Required At Threshold",
+			failures:   captchaLoginFailures,
+			body:       `{"username":"test","password":"wrong"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "Accepted At Threshold",
+			failures: captchaLoginFailures,
+			body:     `{"username":"test","password":"wrong","captchaToken":"fake_captcha_token"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "wrong").
+					Return(nil, &smithy.GenericAPIError{Code: "NotAuthorizedException"}).Once()
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			tt.buildStubs(cognitoAuthService)
+
+			tracker := lockout.NewMemoryTracker(lockout.DefaultPolicy())
+			for i := 0; i < tt.failures; i++ {
+				_, err := tracker.RecordFailure(context.Background(), "test")
+				require.NoError(t, err)
+			}
+
+			testServer := newTestServer(t, cognitoAuthService,
+				WithLockoutTracker(tracker),
+				WithCaptchaVerifier(captcha.FakeVerifier{Token: "fake_captcha_token"}),
+			)
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/users/login", strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			testServer.engine.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+		})
+	}
+}
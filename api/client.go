@@ -0,0 +1,93 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	cconfig "github.com/whatisusername/toon-tank-user-service/internal/config"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+)
+
+const (
+	clientPlatformHeader = "X-Client-Platform"
+	clientPlatformParam  = "platform"
+	appClientKey         = "appClient"
+	clientPlatformKey    = "clientPlatform"
+)
+
+// selectClient resolves the Cognito app client for the request, preferring the
+// platform path prefix over the X-Client-Platform header.
+func (s *Server) selectClient(ctx *gin.Context) {
+	platform := ctx.Param(clientPlatformParam)
+	if platform == "" {
+		platform = ctx.GetHeader(clientPlatformHeader)
+	}
+
+	client, err := s.config.Cognito.Client(platform)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to select app client", "platform", platform, "error", err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	ctx.Set(appClientKey, client)
+	ctx.Set(clientPlatformKey, platform)
+	ctx.Next()
+}
+
+func appClient(ctx *gin.Context) cconfig.ClientConfig {
+	return ctx.MustGet(appClientKey).(cconfig.ClientConfig)
+}
+
+// validateToken verifies the token signature against the user pool and checks
+// that it was issued to clientID.
+func (s *Server) validateToken(ctx *gin.Context, clientID, tokenString string) (*jwt.Token, error) {
+	start := time.Now()
+	reason := reasonInvalidToken
+	defer func() { s.recordOutcome(ctx, metricTokenValidation, start, reason) }()
+
+	t, err := s.cognitoAuthService.ValidateToken(ctx, s.config.Cognito.UserPoolID, tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = verifyTokenClient(t, clientID); err != nil {
+		reason = reasonClientMismatch
+		return nil, err
+	}
+
+	reason = ""
+	return t, nil
+}
+
+// verifyTokenClient rejects tokens that were issued to a different app client
+// than the one selected for the request. Access tokens carry the client in
+// client_id, ID tokens in aud.
+func verifyTokenClient(t *jwt.Token, clientID string) error {
+	claims, ok := t.Claims.(jwt.MapClaims)
+	if !ok {
+		return fmt.Errorf("unexpected claims type: %T", t.Claims)
+	}
+
+	if tokenClientID, ok := claims["client_id"].(string); ok {
+		if tokenClientID != clientID {
+			return errors.New("token was issued to a different client")
+		}
+		return nil
+	}
+
+	aud, err := claims.GetAudience()
+	if err != nil {
+		return fmt.Errorf("failed to read token audience: %w", err)
+	}
+	for _, a := range aud {
+		if a == clientID {
+			return nil
+		}
+	}
+	return errors.New("token was issued to a different client")
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+	"github.com/whatisusername/toon-tank-user-service/internal/profile"
+	"github.com/whatisusername/toon-tank-user-service/internal/validation"
+)
+
+const (
+	// displayNameCooldown is how long a player must wait between renames, so
+	// that a name cannot be used to troll and dropped right away.
+	displayNameCooldown = 30 * 24 * time.Hour
+
+	codeDisplayNameTaken = "DisplayNameTaken"
+)
+
+type putDisplayNameRequest struct {
+	DisplayName string `json:"displayName" binding:"required"`
+}
+
+type nameChangeResponse struct {
+	Name       string    `json:"name"`
+	ReplacedAt time.Time `json:"replacedAt"`
+}
+
+// putDisplayName renames the caller. The name follows the same rules as
+// usernames and is unique regardless of case.
+func (s *Server) putDisplayName(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	playerID, ok := s.playerID(ctx)
+	if !ok {
+		return
+	}
+
+	var req putDisplayNameRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if violations := s.usernames.Validate("displayName", req.DisplayName); len(violations) > 0 {
+		ctx.JSON(http.StatusBadRequest, validationErrorResponse(violations))
+		return
+	}
+
+	p, err := s.profiles.Rename(ctx, playerID, req.DisplayName, displayNameCooldown)
+
+	var cooldownErr *profile.CooldownError
+	switch {
+	case errors.Is(err, profile.ErrNameTaken):
+		ctx.JSON(http.StatusConflict, validationErrorResponse([]validation.Violation{
+			{Field: "displayName", Code: codeDisplayNameTaken, Message: "is already taken"},
+		}))
+		return
+	case errors.As(err, &cooldownErr):
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(time.Until(cooldownErr.Until).Seconds()))))
+		ctx.JSON(http.StatusTooManyRequests, errorResponse(err))
+		return
+	case errors.Is(err, profile.ErrVersionConflict):
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return
+	case err != nil:
+		logger.Error("Failed to rename player", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	logger.Info("Changed display name", "displayName", p.DisplayName)
+	ctx.JSON(http.StatusOK, successResponse(newProfileResponse(p)))
+}
+
+// listDisplayNames lets moderators see every name a player has used.
+func (s *Server) listDisplayNames(ctx *gin.Context) {
+	var req playerIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	history, err := s.profiles.NameHistory(ctx, req.ID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get display name history", "playerId", req.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	resp := make([]nameChangeResponse, len(history))
+	for i, c := range history {
+		resp[i] = nameChangeResponse{Name: c.Name, ReplacedAt: c.ReplacedAt}
+	}
+	ctx.JSON(http.StatusOK, successResponse(resp))
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/profile"
+	"github.com/whatisusername/toon-tank-user-service/internal/username"
+)
+
+func TestServer_putDisplayName(t *testing.T) {
+	tests := []struct {
+		name          string
+		setup         func(t *testing.T, profiles *profile.MemoryRepository)
+		body          gin.H
+		wantStatus    int
+		wantCodes     []string
+		wantName      string
+		wantRetryHint bool
+	}{
+		{
+			name:       "First Name",
+			body:       gin.H{"displayName": "Commander"},
+			wantStatus: http.StatusOK,
+			wantName:   "Commander",
+		},
+		{
+			name: "Taken",
+			setup: func(t *testing.T, profiles *profile.MemoryRepository) {
+				_, err := profiles.Rename(context.Background(), "other_sub", "commander", displayNameCooldown)
+				require.NoError(t, err)
+			},
+			body:       gin.H{"displayName": "Commander"},
+			wantStatus: http.StatusConflict,
+			wantCodes:  []string{codeDisplayNameTaken},
+		},
+		{
+			name:       "Invalid",
+			body:       gin.H{"displayName": "admin"},
+			wantStatus: http.StatusBadRequest,
+			wantCodes:  []string{username.CodeReserved},
+		},
+		{
+			name:       "Missing",
+			body:       gin.H{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Cooldown",
+			setup: func(t *testing.T, profiles *profile.MemoryRepository) {
+				_, err := profiles.Rename(context.Background(), "fake_sub", "Commander", displayNameCooldown)
+				require.NoError(t, err)
+			},
+			body:          gin.H{"displayName": "Captain"},
+			wantStatus:    http.StatusTooManyRequests,
+			wantRetryHint: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			mockTokenValidation(cognitoAuthService, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test", "sub": "fake_sub"})
+
+			profiles := profile.NewMemoryRepository()
+			if tt.setup != nil {
+				tt.setup(t, profiles)
+			}
+
+			var body bytes.Buffer
+			require.NoError(t, json.NewEncoder(&body).Encode(tt.body))
+			request, err := http.NewRequest(http.MethodPut, "/v1/me/display-name", &body)
+			require.NoError(t, err)
+			request.Header.Set(authorizationHeader, "Bearer fake_access_token")
+
+			testServer := newTestServer(t, cognitoAuthService, WithProfileRepository(profiles))
+			recorder := httptest.NewRecorder()
+
+			testServer.engine.ServeHTTP(recorder, request)
+			require.Equal(t, tt.wantStatus, recorder.Code, recorder.Body.String())
+
+			if tt.wantCodes != nil {
+				assertViolationCodes(t, recorder, tt.wantCodes)
+			}
+			if tt.wantRetryHint {
+				retryAfter, err := strconv.Atoi(recorder.Header().Get("Retry-After"))
+				require.NoError(t, err)
+				assert.InDelta(t, displayNameCooldown.Seconds(), float64(retryAfter), 5)
+			}
+			if tt.wantName != "" {
+				p, err := profiles.Get(context.Background(), "fake_sub")
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantName, p.DisplayName)
+			}
+		})
+	}
+}
+
+func TestServer_listDisplayNames(t *testing.T) {
+	cognitoAuthService := caws.NewMockCognitoAuthService(t)
+	mockTokenValidation(cognitoAuthService, "us-east-1_example", "fake_admin_token", jwt.MapClaims{
+		"token_use":      "access",
+		"client_id":      "fake_client_id",
+		"username":       "admin",
+		"cognito:groups": []interface{}{"admin"},
+	})
+
+	profiles := profile.NewMemoryRepository()
+	_, err := profiles.Rename(context.Background(), "player-1", "Commander", 0)
+	require.NoError(t, err)
+	_, err = profiles.Rename(context.Background(), "player-1", "Captain", 0)
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodGet, "/v1/admin/players/player-1/display-names", nil)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeader, "Bearer fake_admin_token")
+
+	testServer := newTestServer(t, cognitoAuthService, WithProfileRepository(profiles))
+	recorder := httptest.NewRecorder()
+
+	testServer.engine.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var resp struct {
+		Data []nameChangeResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "Commander", resp.Data[0].Name)
+	assert.WithinDuration(t, time.Now(), resp.Data[0].ReplacedAt, time.Minute)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+)
+
+// eventHold is how long a held event waits to be settled before the outbox
+// drainer checks for itself whether its change was made. It is the longest a
+// Lambda function can run.
+const eventHold = 15 * time.Minute
+
+// heldEventPublisher is implemented by publishers backed by an outbox, which
+// can store an event before making a change that can't share its transaction.
+type heldEventPublisher interface {
+	Hold(ctx context.Context, e cevents.Event, hold time.Duration) error
+	Commit(ctx context.Context, id string) error
+	Abandon(ctx context.Context, id string, cause error) error
+}
+
+// publishEvent tells other services about a change that has already been
+// made. The change can't be undone at this point, so a failure to publish is
+// logged rather than returned to the caller.
+func (s *Server) publishEvent(ctx *gin.Context, eventType string, version int, data interface{}) {
+	logger := logging.FromContext(ctx)
+
+	e, err := cevents.New(eventType, version, ctx.GetString(requestIDKey), data)
+	if err != nil {
+		logger.Error("Failed to build event", "type", eventType, "error", err)
+		return
+	}
+
+	if err = s.events.Publish(ctx, e); err != nil {
+		logger.Error("Failed to publish event", "type", eventType, "eventId", e.ID, "error", err)
+	}
+}
+
+// holdEvent stores an event before the change it describes is made somewhere
+// the outbox can't share a transaction with, such as Cognito, so the event
+// isn't lost if the process dies in between. The returned function settles it
+// once the change's outcome is known: nil releases the event and any other
+// error drops it. Publishers without an outbox just publish on success.
+func (s *Server) holdEvent(ctx *gin.Context, eventType string, version int, data interface{}) (func(error), error) {
+	logger := logging.FromContext(ctx)
+
+	e, err := cevents.New(eventType, version, ctx.GetString(requestIDKey), data)
+	if err != nil {
+		return nil, err
+	}
+
+	held, ok := s.events.(heldEventPublisher)
+	if !ok {
+		return func(changeErr error) {
+			if changeErr != nil {
+				return
+			}
+			if err := s.events.Publish(ctx, e); err != nil {
+				logger.Error("Failed to publish event", "type", eventType, "eventId", e.ID, "error", err)
+			}
+		}, nil
+	}
+
+	if err = held.Hold(ctx, e, eventHold); err != nil {
+		return nil, err
+	}
+	return func(changeErr error) {
+		// A failure here leaves the event held; the drainer settles it
+		// once the hold runs out.
+		if changeErr != nil {
+			if err := held.Abandon(ctx, e.ID, changeErr); err != nil {
+				logger.Error("Failed to abandon event", "type", eventType, "eventId", e.ID, "error", err)
+			}
+			return
+		}
+		if err := held.Commit(ctx, e.ID); err != nil {
+			logger.Error("Failed to commit event", "type", eventType, "eventId", e.ID, "error", err)
+		}
+	}, nil
+}
@@ -0,0 +1,236 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/outbox"
+)
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(context.Context, ...cevents.Event) error {
+	return errors.New("event bus unavailable")
+}
+
+func TestServer_events(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		url        string
+		token      string
+		body       string
+		buildStubs func(authSvc *caws.MockCognitoAuthService)
+		wantStatus int
+		wantType   string
+		wantData   string
+	}{
+		{
+			name:   "Signed Up",
+			method: http.MethodPost,
+			url:    "/v1/users",
+			body:   `{"username":"test","email":"Test@Example.com","password":"test123456A"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "Test@example.com").
+					Return(nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+			wantType:   cevents.TypeUserSignedUp,
+			wantData:   `{"username":"test","email":"Test@example.com","clientId":"fake_client_id"}`,
+		},
+		{
+			name:   "Logged In",
+			method: http.MethodPost,
+			url:    "/v1/platforms/pc/users/login",
+			body:   `{"username":"test","password":"test123456A"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_pc_client_id", "fake_pc_client_secret", "test", "test123456A").
+					Return(&caws.CognitoToken{IdToken: "fake_id_token", AccessToken: "fake_access_token"}, nil).Once()
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_pc_client_id", "username": "test"})
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_id_token", jwt.MapClaims{"aud": "fake_pc_client_id", "cognito:username": "test"})
+				authSvc.EXPECT().ParseUserInfo(mock.AnythingOfType("*jwt.Token")).
+					Return(&caws.CognitoUserInfo{Username: "test", Email: "test@example.com"}, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+			wantType:   cevents.TypeUserLoggedIn,
+			wantData:   `{"username":"test","clientId":"fake_pc_client_id"}`,
+		},
+		{
+			name:   "Deleted",
+			method: http.MethodDelete,
+			url:    "/v1/me",
+			token:  "fake_access_token",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test"})
+				authSvc.EXPECT().DeleteUser(mock.Anything, "fake_access_token").Return(nil).Once()
+			},
+			wantStatus: http.StatusNoContent,
+			wantType:   cevents.TypeUserDeleted,
+			wantData:   `{"username":"test"}`,
+		},
+		{
+			name:   "Nothing On Failure",
+			method: http.MethodPost,
+			url:    "/v1/users",
+			body:   `{"username":"test","email":"test@example.com","password":"test123456A"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+					Return(errors.New("server is busy")).Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			tt.buildStubs(cognitoAuthService)
+
+			publisher := cevents.NewMemoryPublisher()
+			testServer := newTestServer(t, cognitoAuthService, WithEventPublisher(publisher))
+
+			request, err := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			if tt.token != "" {
+				request.Header.Set(authorizationHeader, "Bearer "+tt.token)
+			}
+
+			recorder := httptest.NewRecorder()
+			testServer.engine.ServeHTTP(recorder, request)
+			require.Equal(t, tt.wantStatus, recorder.Code)
+
+			published := publisher.Events()
+			if tt.wantType == "" {
+				assert.Empty(t, published)
+				return
+			}
+			require.Len(t, published, 1)
+			assert.Equal(t, tt.wantType, published[0].Type)
+			assert.Equal(t, 1, published[0].Version)
+			assert.Equal(t, cevents.Source, published[0].Source)
+			assert.Equal(t, recorder.Header().Get(requestIDHeader), published[0].CorrelationID)
+			assert.JSONEq(t, tt.wantData, string(published[0].Data))
+		})
+	}
+}
+
+func TestServer_eventsPublishFailure(t *testing.T) {
+	cognitoAuthService := caws.NewMockCognitoAuthService(t)
+	cognitoAuthService.EXPECT().
+		SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+		Return(nil).Once()
+
+	testServer := newTestServer(t, cognitoAuthService, WithEventPublisher(failingPublisher{}))
+
+	request, err := http.NewRequest(http.MethodPost, "/v1/users", strings.NewReader(`{"username":"test","email":"test@example.com","password":"test123456A"}`))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	testServer.engine.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusCreated, recorder.Code, "a publish failure must not fail the request")
+}
+
+// holdingStore remembers which events were held, so a test can look them up.
+type holdingStore struct {
+	*outbox.MemoryStore
+	held    []cevents.Event
+	holdErr error
+}
+
+func (s *holdingStore) Hold(ctx context.Context, e cevents.Event, hold time.Duration) error {
+	if s.holdErr != nil {
+		return s.holdErr
+	}
+	s.held = append(s.held, e)
+	return s.MemoryStore.Hold(ctx, e, hold)
+}
+
+func TestServer_eventsHeld(t *testing.T) {
+	tests := []struct {
+		name       string
+		holdErr    error
+		buildStubs func(authSvc *caws.MockCognitoAuthService)
+		wantStatus int
+		wantRecord outbox.Status
+	}{
+		{
+			name: "Committed After Sign-Up",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+					Return(nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+			wantRecord: outbox.StatusPending,
+		},
+		{
+			name: "Dropped When Sign-Up Fails",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+					Return(errors.New("server is busy")).Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantRecord: outbox.StatusDead,
+		},
+		{
+			// The user is created and the process dies before the event is
+			// committed; the held record is left for the drainer to check.
+			name: "Crash Between Sign-Up And Commit",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+					Run(func(context.Context, string, string, string, string, string) { panic("process killed") }).
+					Return(nil).Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantRecord: outbox.StatusHeld,
+		},
+		{
+			name:       "Not Signed Up Without Hold",
+			holdErr:    errors.New("outbox unavailable"),
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			tt.buildStubs(cognitoAuthService)
+
+			store := &holdingStore{MemoryStore: outbox.NewMemoryStore(), holdErr: tt.holdErr}
+			testServer := newTestServer(t, cognitoAuthService, WithEventPublisher(outbox.NewPublisher(store)))
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/users", strings.NewReader(`{"username":"test","email":"test@example.com","password":"test123456A"}`))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			testServer.engine.ServeHTTP(recorder, request)
+			require.Equal(t, tt.wantStatus, recorder.Code)
+
+			if tt.holdErr != nil {
+				assert.Empty(t, store.held)
+				return
+			}
+			require.Len(t, store.held, 1)
+			assert.Equal(t, cevents.TypeUserSignedUp, store.held[0].Type)
+
+			r, ok := store.Get(store.held[0].ID)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantRecord, r.Status)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/whatisusername/toon-tank-user-service/internal/friends"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+	"github.com/whatisusername/toon-tank-user-service/internal/profile"
+)
+
+type listFriendsRequest struct {
+	State  string `form:"state" binding:"omitempty,oneof=friends incoming outgoing blocked"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor"`
+}
+
+type sendFriendRequestRequest struct {
+	PlayerID string `json:"playerId" binding:"required,max=64"`
+}
+
+type friendResponse struct {
+	PlayerID string        `json:"playerId"`
+	State    string        `json:"state"`
+	Since    time.Time     `json:"since"`
+	Player   *publicPlayer `json:"player,omitempty"`
+}
+
+type listFriendsResponse struct {
+	Items  []friendResponse `json:"items"`
+	Cursor string           `json:"cursor,omitempty"`
+}
+
+type sendFriendRequestResponse struct {
+	State string `json:"state"`
+}
+
+// listFriends lists one page of friends, requests or blocked players, with
+// the public profile of each where there is one.
+func (s *Server) listFriends(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	playerID, ok := s.playerID(ctx)
+	if !ok {
+		return
+	}
+
+	var req listFriendsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.State == "" {
+		req.State = "friends"
+	}
+
+	page, err := s.friends.List(ctx, playerID, friends.State(strings.ToUpper(req.State)), req.Limit, req.Cursor)
+	if errors.Is(err, friends.ErrInvalidCursor) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to list friends", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ids := make([]string, len(page.Edges))
+	for i, e := range page.Edges {
+		ids[i] = e.OtherID
+	}
+	players := make(map[string]*profile.Profile, len(ids))
+	if len(ids) > 0 {
+		profiles, err := s.profiles.BatchGet(ctx, ids)
+		if err != nil {
+			// Names are a nicety; the list is still useful with IDs only.
+			logger.Error("Failed to get friend profiles", "error", err)
+		}
+		for i := range profiles {
+			players[profiles[i].PlayerID] = &profiles[i]
+		}
+	}
+
+	resp := listFriendsResponse{Items: make([]friendResponse, len(page.Edges)), Cursor: page.Cursor}
+	for i, e := range page.Edges {
+		resp.Items[i] = friendResponse{PlayerID: e.OtherID, State: strings.ToLower(string(e.State)), Since: e.Since}
+		if p, ok := players[e.OtherID]; ok {
+			pub := newPublicPlayer(p)
+			resp.Items[i].Player = &pub
+		}
+	}
+	ctx.JSON(http.StatusOK, successResponse(resp))
+}
+
+// sendFriendRequest asks another player to be friends. Only players with a
+// profile can be found in game, so only they can be asked. If they had
+// already asked the caller, the two become friends at once.
+func (s *Server) sendFriendRequest(ctx *gin.Context) {
+	playerID, ok := s.playerID(ctx)
+	if !ok {
+		return
+	}
+
+	var req sendFriendRequestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := s.profiles.Get(ctx, req.PlayerID); err != nil {
+		s.friendError(ctx, err)
+		return
+	}
+
+	state, err := s.friends.SendRequest(ctx, playerID, req.PlayerID)
+	if err != nil {
+		s.friendError(ctx, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if state == friends.StateFriends {
+		status = http.StatusOK
+	}
+	ctx.JSON(status, successResponse(sendFriendRequestResponse{State: strings.ToLower(string(state))}))
+}
+
+func (s *Server) acceptFriendRequest(ctx *gin.Context) {
+	s.changeFriendship(ctx, s.friends.Accept)
+}
+
+func (s *Server) declineFriendRequest(ctx *gin.Context) {
+	s.changeFriendship(ctx, s.friends.Decline)
+}
+
+func (s *Server) cancelFriendRequest(ctx *gin.Context) {
+	s.changeFriendship(ctx, s.friends.Cancel)
+}
+
+func (s *Server) removeFriend(ctx *gin.Context) {
+	s.changeFriendship(ctx, s.friends.Remove)
+}
+
+func (s *Server) blockPlayer(ctx *gin.Context) {
+	s.changeFriendship(ctx, s.friends.Block)
+}
+
+func (s *Server) unblockPlayer(ctx *gin.Context) {
+	s.changeFriendship(ctx, s.friends.Unblock)
+}
+
+// changeFriendship applies change between the caller and the player in the
+// path. The service announces the change itself.
+func (s *Server) changeFriendship(ctx *gin.Context, change func(ctx context.Context, player, other string) error) {
+	playerID, ok := s.playerID(ctx)
+	if !ok {
+		return
+	}
+
+	var req playerIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := change(ctx, playerID, req.ID); err != nil {
+		s.friendError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (s *Server) friendError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, friends.ErrSelf):
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	case errors.Is(err, friends.ErrBlocked):
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+	case errors.Is(err, profile.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, errorResponse(errPlayerNotFound))
+	case errors.Is(err, friends.ErrNoRequest), errors.Is(err, friends.ErrNotFriends), errors.Is(err, friends.ErrNotBlocked):
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+	case errors.Is(err, friends.ErrAlreadyFriends), errors.Is(err, friends.ErrRequestExists), errors.Is(err, friends.ErrConflict):
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+	default:
+		logging.FromContext(ctx).Error("Failed to change friendship", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/friends"
+)
+
+func TestServer_friends(t *testing.T) {
+	type call struct {
+		player     string
+		method     string
+		url        string
+		body       string
+		wantStatus int
+		wantEvent  string
+	}
+	tests := []struct {
+		name  string
+		calls []call
+		want  friends.Pair
+	}{
+		{
+			name: "Request And Accept",
+			calls: []call{
+				{player: "player-1", method: http.MethodPost, url: "/v1/me/friends/requests", body: `{"playerId":"player-2"}`, wantStatus: http.StatusCreated, wantEvent: cevents.TypeFriendRequested},
+				{player: "player-1", method: http.MethodPost, url: "/v1/me/friends/requests", body: `{"playerId":"player-2"}`, wantStatus: http.StatusConflict},
+				{player: "player-2", method: http.MethodPost, url: "/v1/me/friends/requests/player-1/accept", wantStatus: http.StatusNoContent, wantEvent: cevents.TypeFriendAdded},
+			},
+			want: friends.Pair{Mine: friends.StateFriends, Theirs: friends.StateFriends},
+		},
+		{
+			name: "Crossed Requests",
+			calls: []call{
+				{player: "player-2", method: http.MethodPost, url: "/v1/me/friends/requests", body: `{"playerId":"player-1"}`, wantStatus: http.StatusCreated, wantEvent: cevents.TypeFriendRequested},
+				{player: "player-1", method: http.MethodPost, url: "/v1/me/friends/requests", body: `{"playerId":"player-2"}`, wantStatus: http.StatusOK, wantEvent: cevents.TypeFriendAdded},
+			},
+			want: friends.Pair{Mine: friends.StateFriends, Theirs: friends.StateFriends},
+		},
+		{
+			name: "Decline",
+			calls: []call{
+				{player: "player-1", method: http.MethodPost, url: "/v1/me/friends/requests", body: `{"playerId":"player-2"}`, wantStatus: http.StatusCreated, wantEvent: cevents.TypeFriendRequested},
+				{player: "player-2", method: http.MethodPost, url: "/v1/me/friends/requests/player-1/decline", wantStatus: http.StatusNoContent, wantEvent: cevents.TypeFriendRequestDeclined},
+				{player: "player-2", method: http.MethodPost, url: "/v1/me/friends/requests/player-1/decline", wantStatus: http.StatusNotFound},
+			},
+		},
+		{
+			name: "Cancel",
+			calls: []call{
+				{player: "player-1", method: http.MethodPost, url: "/v1/me/friends/requests", body: `{"playerId":"player-2"}`, wantStatus: http.StatusCreated, wantEvent: cevents.TypeFriendRequested},
+				{player: "player-1", method: http.MethodDelete, url: "/v1/me/friends/requests/player-2", wantStatus: http.StatusNoContent, wantEvent: cevents.TypeFriendRequestCancelled},
+			},
+		},
+		{
+			name: "Remove",
+			calls: []call{
+				{player: "player-1", method: http.MethodDelete, url: "/v1/me/friends/player-2", wantStatus: http.StatusNotFound},
+				{player: "player-1", method: http.MethodPost, url: "/v1/me/friends/requests", body: `{"playerId":"player-2"}`, wantStatus: http.StatusCreated, wantEvent: cevents.TypeFriendRequested},
+				{player: "player-2", method: http.MethodPost, url: "/v1/me/friends/requests/player-1/accept", wantStatus: http.StatusNoContent, wantEvent: cevents.TypeFriendAdded},
+				{player: "player-1", method: http.MethodDelete, url: "/v1/me/friends/player-2", wantStatus: http.StatusNoContent, wantEvent: cevents.TypeFriendRemoved},
+			},
+		},
+		{
+			name: "Block",
+			calls: []call{
+				{player: "player-2", method: http.MethodPut, url: "/v1/me/friends/blocked/player-1", wantStatus: http.StatusNoContent, wantEvent: cevents.TypePlayerBlocked},
+				{player: "player-2", method: http.MethodPut, url: "/v1/me/friends/blocked/player-1", wantStatus: http.StatusNoContent},
+				{player: "player-1", method: http.MethodPost, url: "/v1/me/friends/requests", body: `{"playerId":"player-2"}`, wantStatus: http.StatusForbidden},
+				{player: "player-2", method: http.MethodDelete, url: "/v1/me/friends/blocked/player-1", wantStatus: http.StatusNoContent, wantEvent: cevents.TypePlayerUnblocked},
+				{player: "player-1", method: http.MethodPost, url: "/v1/me/friends/requests", body: `{"playerId":"player-2"}`, wantStatus: http.StatusCreated, wantEvent: cevents.TypeFriendRequested},
+			},
+			want: friends.Pair{Mine: friends.StateOutgoing, Theirs: friends.StateIncoming},
+		},
+		{
+			name: "Unknown Player",
+			calls: []call{
+				{player: "player-1", method: http.MethodPost, url: "/v1/me/friends/requests", body: `{"playerId":"missing"}`, wantStatus: http.StatusNotFound},
+			},
+		},
+		{
+			name: "Self",
+			calls: []call{
+				{player: "player-1", method: http.MethodPost, url: "/v1/me/friends/requests", body: `{"playerId":"player-1"}`, wantStatus: http.StatusBadRequest},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := cevents.NewMemoryPublisher()
+			repo := friends.NewMemoryRepository(publisher)
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			testServer := newTestServer(t, cognitoAuthService,
+				WithProfileRepository(newTestProfiles(t, "player-1", "player-2")),
+				WithFriendsRepository(repo),
+				WithEventPublisher(publisher),
+			)
+
+			for i, c := range tt.calls {
+				mockTokenValidation(cognitoAuthService, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": c.player, "sub": c.player})
+
+				request, err := http.NewRequest(c.method, c.url, strings.NewReader(c.body))
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeader, "Bearer fake_access_token")
+				recorder := httptest.NewRecorder()
+
+				before := len(publisher.Events())
+				testServer.engine.ServeHTTP(recorder, request)
+				require.Equal(t, c.wantStatus, recorder.Code, "call %d: %s", i, recorder.Body.String())
+
+				published := publisher.Events()[before:]
+				if c.wantEvent == "" {
+					assert.Empty(t, published, "call %d", i)
+					continue
+				}
+				require.Len(t, published, 1, "call %d", i)
+				assert.Equal(t, c.wantEvent, published[0].Type)
+				assert.Equal(t, recorder.Header().Get(requestIDHeader), published[0].CorrelationID)
+				assert.Contains(t, string(published[0].Data), `"playerId":"`+c.player+`"`)
+			}
+
+			got, err := repo.Get(context.Background(), "player-1", "player-2")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServer_listFriends(t *testing.T) {
+	repo := friends.NewMemoryRepository(cevents.NoopPublisher{})
+	service := friends.NewService(repo)
+	for _, other := range []string{"player-2", "player-3", "player-4"} {
+		_, err := service.SendRequest(context.Background(), other, "player-1")
+		require.NoError(t, err)
+		require.NoError(t, service.Accept(context.Background(), "player-1", other))
+	}
+	_, err := service.SendRequest(context.Background(), "player-5", "player-1")
+	require.NoError(t, err)
+
+	cognitoAuthService := caws.NewMockCognitoAuthService(t)
+	testServer := newTestServer(t, cognitoAuthService,
+		WithProfileRepository(newTestProfiles(t, "player-2", "player-3")),
+		WithFriendsRepository(repo),
+	)
+
+	list := func(query string) (int, listFriendsResponse) {
+		mockTokenValidation(cognitoAuthService, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "player-1", "sub": "player-1"})
+
+		request, err := http.NewRequest(http.MethodGet, "/v1/me/friends"+query, nil)
+		require.NoError(t, err)
+		request.Header.Set(authorizationHeader, "Bearer fake_access_token")
+		recorder := httptest.NewRecorder()
+		testServer.engine.ServeHTTP(recorder, request)
+
+		var resp struct {
+			Data listFriendsResponse `json:"data"`
+		}
+		if recorder.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		}
+		return recorder.Code, resp.Data
+	}
+
+	status, page := list("?limit=2")
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, "player-2", page.Items[0].PlayerID)
+	assert.Equal(t, "friends", page.Items[0].State)
+	require.NotNil(t, page.Items[0].Player)
+	assert.Equal(t, "Name player-2", page.Items[0].Player.DisplayName)
+	require.NotEmpty(t, page.Cursor)
+
+	status, page = list("?limit=2&cursor=" + page.Cursor)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "player-4", page.Items[0].PlayerID)
+	assert.Nil(t, page.Items[0].Player, "players without a profile are listed by ID only")
+	assert.Empty(t, page.Cursor)
+
+	status, page = list("?state=incoming")
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "player-5", page.Items[0].PlayerID)
+
+	status, _ = list("?state=enemies")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = list("?cursor=not-a-cursor!")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
@@ -0,0 +1,301 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/guest"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+)
+
+const (
+	metricGuestSignUp = "GuestSignUp"
+	metricUpgrade     = "AccountUpgrade"
+
+	codeAliasExistsException = "AliasExistsException"
+
+	reasonNotGuest        = "NotGuest"
+	reasonAlreadyUpgraded = "AlreadyUpgraded"
+)
+
+var (
+	errAccountExists   = errors.New("username or email is already in use")
+	errAlreadyUpgraded = errors.New("account has already been upgraded")
+)
+
+type createGuestRequest struct {
+	CaptchaToken string `json:"captchaToken"`
+}
+
+// createGuestResponse carries the only copy of the guest's credentials. The
+// client keeps GuestID and Secret on the device and signs in with them through
+// the regular login until the account is upgraded.
+type createGuestResponse struct {
+	PlayerID    string `json:"playerId"`
+	GuestID     string `json:"guestId"`
+	Secret      string `json:"secret"`
+	AccessToken string `json:"token"`
+}
+
+// createGuest creates an anonymous account and signs it in, so a new player
+// can start playing before choosing a username.
+func (s *Server) createGuest(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	start := time.Now()
+	reason := reasonInternalError
+	defer func() { s.recordOutcome(ctx, metricGuestSignUp, start, reason) }()
+
+	// The body is optional; it only carries a CAPTCHA token.
+	var req createGuestRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			logger.Error("Failed to bind request", "error", err)
+			reason = reasonInvalidRequest
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	if s.captcha != nil {
+		if reason = s.verifyCaptcha(ctx, req.CaptchaToken); reason != "" {
+			return
+		}
+	}
+
+	creds, err := guest.NewCredentials()
+	if err != nil {
+		logger.Error("Failed to generate guest credentials", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.Set(usernameKey, creds.Username)
+
+	pool := s.config.Cognito.UserPoolID
+	if err = s.userAdmin.AdminCreateUser(ctx, pool, caws.NewCognitoUser{Username: creds.Username, SuppressMessage: true}); err != nil {
+		logger.Error("Failed to create guest", "error", err)
+		reason = errorReason(err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	accessToken, err := s.signInGuest(ctx, creds)
+	if err != nil {
+		logger.Error("Failed to set up guest", "error", err)
+		reason = errorReason(err)
+		// Nobody else knows the credentials, so the account is useless now.
+		if deleteErr := s.userAdmin.AdminDeleteUser(ctx, pool, creds.Username); deleteErr != nil {
+			logger.Error("Failed to delete guest", "error", deleteErr)
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	claims, _ := accessToken.Claims.(jwt.MapClaims)
+	playerID, _ := claims["sub"].(string)
+
+	s.publishEvent(ctx, cevents.TypeGuestCreated, 1, cevents.GuestCreated{
+		Username: creds.Username,
+		PlayerID: playerID,
+		ClientID: appClient(ctx).ClientID,
+	})
+
+	reason = ""
+	ctx.JSON(http.StatusCreated, successResponse(createGuestResponse{
+		PlayerID:    playerID,
+		GuestID:     creds.Username,
+		Secret:      creds.Secret,
+		AccessToken: accessToken.Raw,
+	}))
+}
+
+// signInGuest confirms a freshly created guest, marks it as a guest and signs
+// it in.
+func (s *Server) signInGuest(ctx *gin.Context, creds guest.Credentials) (*jwt.Token, error) {
+	pool := s.config.Cognito.UserPoolID
+	if err := s.userAdmin.AdminSetUserPassword(ctx, pool, creds.Username, creds.Secret, true); err != nil {
+		return nil, err
+	}
+	if err := s.userAdmin.AdminAddUserToGroup(ctx, pool, creds.Username, guest.Group); err != nil {
+		return nil, err
+	}
+
+	client := appClient(ctx)
+	cgToken, err := s.cognitoAuthService.Login(ctx, client.ClientID, client.ClientSecrets, creds.Username, creds.Secret)
+	if err != nil {
+		return nil, err
+	}
+	return s.validateToken(ctx, client.ClientID, cgToken.AccessToken)
+}
+
+type upgradeGuestRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type upgradeGuestResponse struct {
+	PlayerID string `json:"playerId"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// upgradeGuest turns the calling guest into a full account. Cognito usernames
+// can't change, so the chosen username becomes the preferred_username alias,
+// which the user pool must be configured to sign in with. The player ID stays
+// the same, and with it everything stored against it. The caller's tokens still
+// name the guest group until they are refreshed, so whether the account is
+// still a guest is checked against the user pool rather than the token.
+func (s *Server) upgradeGuest(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	start := time.Now()
+	reason := reasonInternalError
+	defer func() { s.recordOutcome(ctx, metricUpgrade, start, reason) }()
+
+	playerID, ok := s.playerID(ctx)
+	if !ok {
+		reason = reasonInvalidToken
+		return
+	}
+
+	var req upgradeGuestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", "error", err)
+		reason = reasonInvalidRequest
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	violations := s.usernames.Validate("username", req.Username)
+
+	emailAddress, emailViolations := s.emails.Validate("email", req.Email)
+	violations = append(violations, emailViolations...)
+
+	passwordViolations, err := s.passwords.Validate(ctx, "password", req.Password)
+	if err != nil {
+		logger.Error("Failed to validate password", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	violations = append(violations, passwordViolations...)
+
+	if len(violations) > 0 {
+		reason = reasonValidationFailed
+		ctx.JSON(http.StatusBadRequest, validationErrorResponse(violations))
+		return
+	}
+
+	pool := s.config.Cognito.UserPoolID
+	guestUsername := ctx.GetString(usernameKey)
+	user, err := s.userAdmin.AdminGetUser(ctx, pool, guestUsername)
+	if err != nil {
+		logger.Error("Failed to get guest", "error", err)
+		reason = errorReason(err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if !guest.IsUsername(user.Username) {
+		reason = reasonNotGuest
+		ctx.JSON(http.StatusForbidden, errorResponse(errForbidden))
+		return
+	}
+	if user.Attributes[guest.UpgradedAttribute] != "" {
+		// Leaving the group is the only step after the marker. It is safe to
+		// repeat; nothing else may change.
+		s.leaveGuests(ctx, guestUsername)
+		reason = reasonAlreadyUpgraded
+		ctx.JSON(http.StatusConflict, errorResponse(errAlreadyUpgraded))
+		return
+	}
+
+	// The attributes go first: they are the step that can be refused, and until
+	// the password changes the device secret still works, so a failed upgrade
+	// leaves a usable guest that can try again.
+	err = s.userAdmin.AdminUpdateUserAttributes(ctx, pool, guestUsername, map[string]string{
+		"preferred_username": req.Username,
+		"email":              emailAddress,
+	})
+	if err != nil {
+		logger.Error("Failed to update guest attributes", "error", err)
+		reason = errorReason(err)
+		if reason == codeAliasExistsException {
+			ctx.JSON(http.StatusConflict, errorResponse(errAccountExists))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err = s.userAdmin.AdminSetUserPassword(ctx, pool, guestUsername, req.Password, true); err != nil {
+		logger.Error("Failed to set password", "error", err)
+		reason = errorReason(err)
+		// The username would stay taken by an account that can't use it, so
+		// it goes again for the guest to be able to retry.
+		if undoErr := s.userAdmin.AdminDeleteUserAttributes(ctx, pool, guestUsername, "preferred_username", "email"); undoErr != nil {
+			logger.Error("Failed to undo guest attributes", "error", undoErr)
+		}
+		if reason == codeInvalidPasswordException {
+			ctx.JSON(http.StatusBadRequest, validationErrorResponse(cognitoPasswordViolations("password")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// The marker goes last, so the cleaner never takes an upgrade that is still
+	// in progress for a finished one. Without it the upgrade can be retried:
+	// the caller's tokens still work, and every step before it is repeatable.
+	err = s.userAdmin.AdminUpdateUserAttributes(ctx, pool, guestUsername, map[string]string{
+		guest.UpgradedAttribute: guest.FormatTime(time.Now()),
+	})
+	if err != nil {
+		logger.Error("Failed to mark guest as upgraded", "error", err)
+		reason = errorReason(err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// The account is upgraded from here on; membership of the group only
+	// matters to the cleaner, which leaves upgraded accounts alone.
+	s.leaveGuests(ctx, guestUsername)
+
+	s.publishEvent(ctx, cevents.TypeUserUpgraded, 1, cevents.UserUpgraded{
+		Username:          guestUsername,
+		PreferredUsername: req.Username,
+		PlayerID:          playerID,
+		Email:             emailAddress,
+	})
+
+	reason = ""
+	ctx.JSON(http.StatusOK, successResponse(upgradeGuestResponse{
+		PlayerID: playerID,
+		Username: req.Username,
+		Email:    emailAddress,
+	}))
+}
+
+// leaveGuests takes an upgraded account out of the guest group. Removal is
+// idempotent, so a failure is logged and left for the next upgrade attempt or
+// the cleaner to finish.
+func (s *Server) leaveGuests(ctx *gin.Context, username string) {
+	if err := s.userAdmin.AdminRemoveUserFromGroup(ctx, s.config.Cognito.UserPoolID, username, guest.Group); err != nil {
+		logging.FromContext(ctx).Error("Failed to remove user from guests", "error", err)
+	}
+}
+
+// markGuestSeen records a guest sign-in, which the cleaner measures a guest's
+// age from. A failure is logged; the sign-in itself has already succeeded.
+func (s *Server) markGuestSeen(ctx *gin.Context, username string) {
+	err := s.userAdmin.AdminUpdateUserAttributes(ctx, s.config.Cognito.UserPoolID, username, map[string]string{
+		guest.LastSeenAttribute: guest.FormatTime(time.Now()),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to record guest sign-in", "error", err)
+	}
+}
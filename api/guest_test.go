@@ -0,0 +1,322 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	cconfig "github.com/whatisusername/toon-tank-user-service/internal/config"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/friends"
+	"github.com/whatisusername/toon-tank-user-service/internal/guest"
+	"github.com/whatisusername/toon-tank-user-service/internal/profile"
+	"github.com/whatisusername/toon-tank-user-service/internal/username"
+)
+
+func TestServer_createGuest(t *testing.T) {
+	isGuest := mock.MatchedBy(guest.IsUsername)
+
+	tests := []struct {
+		name       string
+		buildStubs func(authSvc *caws.MockCognitoAuthService, admin *caws.MockCognitoUserAdmin)
+		wantStatus int
+	}{
+		{
+			name: "OK",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService, admin *caws.MockCognitoUserAdmin) {
+				var secret string
+				admin.EXPECT().AdminCreateUser(mock.Anything, "us-east-1_example", mock.MatchedBy(func(u caws.NewCognitoUser) bool {
+					return guest.IsUsername(u.Username) && u.SuppressMessage
+				})).Return(nil).Once()
+				admin.EXPECT().AdminSetUserPassword(mock.Anything, "us-east-1_example", isGuest, mock.Anything, true).
+					Run(func(_ context.Context, _, _, password string, _ bool) { secret = password }).
+					Return(nil).Once()
+				admin.EXPECT().AdminAddUserToGroup(mock.Anything, "us-east-1_example", isGuest, guest.Group).Return(nil).Once()
+				authSvc.EXPECT().Login(mock.Anything, "fake_client_id", "fake_client_secret", isGuest, mock.Anything).
+					RunAndReturn(func(_ context.Context, _, _, _, password string) (*caws.CognitoToken, error) {
+						assert.Equal(t, secret, password)
+						return &caws.CognitoToken{AccessToken: "fake_access_token"}, nil
+					}).Once()
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "sub": "fake_sub"})
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "Create Failed",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService, admin *caws.MockCognitoUserAdmin) {
+				admin.EXPECT().AdminCreateUser(mock.Anything, "us-east-1_example", mock.Anything).
+					Return(errors.New("server is busy")).Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "Login Failed",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService, admin *caws.MockCognitoUserAdmin) {
+				admin.EXPECT().AdminCreateUser(mock.Anything, "us-east-1_example", mock.Anything).Return(nil).Once()
+				admin.EXPECT().AdminSetUserPassword(mock.Anything, "us-east-1_example", isGuest, mock.Anything, true).Return(nil).Once()
+				admin.EXPECT().AdminAddUserToGroup(mock.Anything, "us-east-1_example", isGuest, guest.Group).Return(nil).Once()
+				authSvc.EXPECT().Login(mock.Anything, "fake_client_id", "fake_client_secret", isGuest, mock.Anything).
+					Return(nil, &smithy.GenericAPIError{Code: "TooManyRequestsException"}).Once()
+				admin.EXPECT().AdminDeleteUser(mock.Anything, "us-east-1_example", isGuest).Return(nil).Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			admin := caws.NewMockCognitoUserAdmin(t)
+			tt.buildStubs(cognitoAuthService, admin)
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/users/guest", nil)
+			require.NoError(t, err)
+
+			testServer := newTestServer(t, cognitoAuthService, WithCognitoUserAdmin(admin), WithGuestAccounts())
+			recorder := httptest.NewRecorder()
+
+			testServer.engine.ServeHTTP(recorder, request)
+			require.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+
+			var resp struct {
+				Data createGuestResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+			assert.Equal(t, "fake_sub", resp.Data.PlayerID)
+			assert.True(t, guest.IsUsername(resp.Data.GuestID))
+			assert.NotEmpty(t, resp.Data.Secret)
+			assert.Equal(t, "fake_access_token", resp.Data.AccessToken)
+		})
+	}
+}
+
+func TestServer_createGuest_disabled(t *testing.T) {
+	request, err := http.NewRequest(http.MethodPost, "/v1/users/guest", nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	newTestServer(t, caws.NewMockCognitoAuthService(t), WithCognitoUserAdmin(caws.NewMockCognitoUserAdmin(t))).engine.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestServer_upgradeGuest(t *testing.T) {
+	const guestUsername = "guest0123456789abcdef0123456789abcdef"
+	guestClaims := jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "sub": "fake_sub", "username": guestUsername, "cognito:groups": []interface{}{guest.Group}}
+	body := gin.H{"username": "commander", "email": "test@example.com", "password": "test123456A"}
+	upgradedMarker := mock.MatchedBy(func(attributes map[string]string) bool {
+		return len(attributes) == 1 && attributes[guest.UpgradedAttribute] != ""
+	})
+	getGuest := func(admin *caws.MockCognitoUserAdmin, attributes map[string]string) {
+		admin.EXPECT().AdminGetUser(mock.Anything, "us-east-1_example", guestUsername).
+			Return(caws.CognitoUser{Username: guestUsername, Attributes: attributes}, nil).Once()
+	}
+
+	tests := []struct {
+		name       string
+		claims     jwt.MapClaims
+		body       gin.H
+		buildStubs func(admin *caws.MockCognitoUserAdmin)
+		wantStatus int
+		wantCodes  []string
+	}{
+		{
+			name:   "OK",
+			claims: guestClaims,
+			body:   body,
+			buildStubs: func(admin *caws.MockCognitoUserAdmin) {
+				getGuest(admin, map[string]string{"sub": "fake_sub"})
+				admin.EXPECT().AdminUpdateUserAttributes(mock.Anything, "us-east-1_example", guestUsername, map[string]string{
+					"preferred_username": "commander",
+					"email":              "test@example.com",
+				}).Return(nil).Once()
+				admin.EXPECT().AdminSetUserPassword(mock.Anything, "us-east-1_example", guestUsername, "test123456A", true).Return(nil).Once()
+				admin.EXPECT().AdminUpdateUserAttributes(mock.Anything, "us-east-1_example", guestUsername, upgradedMarker).Return(nil).Once()
+				admin.EXPECT().AdminRemoveUserFromGroup(mock.Anything, "us-east-1_example", guestUsername, guest.Group).Return(nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			// An upgrade that stopped before its marker is finished by a retry.
+			name:   "Unfinished Upgrade",
+			claims: guestClaims,
+			body:   body,
+			buildStubs: func(admin *caws.MockCognitoUserAdmin) {
+				getGuest(admin, map[string]string{"sub": "fake_sub", "preferred_username": "commander", "email": "test@example.com"})
+				admin.EXPECT().AdminUpdateUserAttributes(mock.Anything, "us-east-1_example", guestUsername, map[string]string{
+					"preferred_username": "commander",
+					"email":              "test@example.com",
+				}).Return(nil).Once()
+				admin.EXPECT().AdminSetUserPassword(mock.Anything, "us-east-1_example", guestUsername, "test123456A", true).Return(nil).Once()
+				admin.EXPECT().AdminUpdateUserAttributes(mock.Anything, "us-east-1_example", guestUsername, upgradedMarker).Return(nil).Once()
+				admin.EXPECT().AdminRemoveUserFromGroup(mock.Anything, "us-east-1_example", guestUsername, guest.Group).Return(nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "Marking Upgraded Fails",
+			claims: guestClaims,
+			body:   body,
+			buildStubs: func(admin *caws.MockCognitoUserAdmin) {
+				getGuest(admin, map[string]string{"sub": "fake_sub"})
+				admin.EXPECT().AdminUpdateUserAttributes(mock.Anything, "us-east-1_example", guestUsername, mock.Anything).Return(nil).Once()
+				admin.EXPECT().AdminSetUserPassword(mock.Anything, "us-east-1_example", guestUsername, "test123456A", true).Return(nil).Once()
+				admin.EXPECT().AdminUpdateUserAttributes(mock.Anything, "us-east-1_example", guestUsername, upgradedMarker).
+					Return(errors.New("throttled")).Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:   "Leaving Group Fails",
+			claims: guestClaims,
+			body:   body,
+			buildStubs: func(admin *caws.MockCognitoUserAdmin) {
+				getGuest(admin, map[string]string{"sub": "fake_sub"})
+				admin.EXPECT().AdminUpdateUserAttributes(mock.Anything, "us-east-1_example", guestUsername, mock.Anything).Return(nil).Twice()
+				admin.EXPECT().AdminSetUserPassword(mock.Anything, "us-east-1_example", guestUsername, "test123456A", true).Return(nil).Once()
+				admin.EXPECT().AdminRemoveUserFromGroup(mock.Anything, "us-east-1_example", guestUsername, guest.Group).
+					Return(errors.New("throttled")).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			// A token issued before the upgrade still names the guest group.
+			name:   "Already Upgraded",
+			claims: guestClaims,
+			body:   body,
+			buildStubs: func(admin *caws.MockCognitoUserAdmin) {
+				getGuest(admin, map[string]string{"sub": "fake_sub", "preferred_username": "owner", "email": "owner@example.com", guest.UpgradedAttribute: "1709296200"})
+				admin.EXPECT().AdminRemoveUserFromGroup(mock.Anything, "us-east-1_example", guestUsername, guest.Group).Return(nil).Once()
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:   "Not A Guest User",
+			claims: jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "sub": "fake_sub", "username": "test", "cognito:groups": []interface{}{guest.Group}},
+			body:   body,
+			buildStubs: func(admin *caws.MockCognitoUserAdmin) {
+				admin.EXPECT().AdminGetUser(mock.Anything, "us-east-1_example", "test").
+					Return(caws.CognitoUser{Username: "test"}, nil).Once()
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Not A Guest",
+			claims:     jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "sub": "fake_sub", "username": "test"},
+			body:       body,
+			buildStubs: func(admin *caws.MockCognitoUserAdmin) {},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Invalid Username",
+			claims:     guestClaims,
+			body:       gin.H{"username": "admin", "email": "test@example.com", "password": "test123456A"},
+			buildStubs: func(admin *caws.MockCognitoUserAdmin) {},
+			wantStatus: http.StatusBadRequest,
+			wantCodes:  []string{username.CodeReserved},
+		},
+		{
+			name:   "Username Taken",
+			claims: guestClaims,
+			body:   body,
+			buildStubs: func(admin *caws.MockCognitoUserAdmin) {
+				getGuest(admin, map[string]string{"sub": "fake_sub"})
+				admin.EXPECT().AdminUpdateUserAttributes(mock.Anything, "us-east-1_example", guestUsername, mock.Anything).
+					Return(&smithy.GenericAPIError{Code: "AliasExistsException"}).Once()
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:   "Password Rejected",
+			claims: guestClaims,
+			body:   body,
+			buildStubs: func(admin *caws.MockCognitoUserAdmin) {
+				getGuest(admin, map[string]string{"sub": "fake_sub"})
+				admin.EXPECT().AdminUpdateUserAttributes(mock.Anything, "us-east-1_example", guestUsername, mock.Anything).Return(nil).Once()
+				admin.EXPECT().AdminSetUserPassword(mock.Anything, "us-east-1_example", guestUsername, "test123456A", true).
+					Return(&smithy.GenericAPIError{Code: "InvalidPasswordException"}).Once()
+				admin.EXPECT().AdminDeleteUserAttributes(mock.Anything, "us-east-1_example", guestUsername, "preferred_username", "email").
+					Return(nil).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+			wantCodes:  []string{codePasswordRejected},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			mockTokenValidation(cognitoAuthService, "us-east-1_example", "fake_access_token", tt.claims)
+			admin := caws.NewMockCognitoUserAdmin(t)
+			tt.buildStubs(admin)
+
+			data, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/me/upgrade", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Authorization", "Bearer fake_access_token")
+
+			testServer := newTestServer(t, cognitoAuthService, WithCognitoUserAdmin(admin), WithGuestAccounts())
+			recorder := httptest.NewRecorder()
+
+			testServer.engine.ServeHTTP(recorder, request)
+			require.Equal(t, tt.wantStatus, recorder.Code)
+			if len(tt.wantCodes) > 0 {
+				assertViolationCodes(t, recorder, tt.wantCodes)
+			}
+		})
+	}
+}
+
+func TestServer_loginGuest(t *testing.T) {
+	const guestUsername = "guest0123456789abcdef0123456789abcdef"
+
+	for _, recordErr := range []error{nil, errors.New("throttled")} {
+		cognitoAuthService := caws.NewMockCognitoAuthService(t)
+		cognitoAuthService.EXPECT().Login(mock.Anything, "fake_client_id", "fake_client_secret", guestUsername, "fake_secret").
+			Return(&caws.CognitoToken{IdToken: "fake_id_token", AccessToken: "fake_access_token"}, nil).Once()
+		mockTokenValidation(cognitoAuthService, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": guestUsername})
+		mockTokenValidation(cognitoAuthService, "us-east-1_example", "fake_id_token", jwt.MapClaims{"aud": "fake_client_id", "cognito:username": guestUsername})
+		cognitoAuthService.EXPECT().ParseUserInfo(mock.AnythingOfType("*jwt.Token")).
+			Return(&caws.CognitoUserInfo{Username: guestUsername}, nil).Once()
+
+		// A guest's age is measured from its last sign-in, so one that keeps
+		// playing is never cleaned up. Failing to record it doesn't fail the
+		// sign-in.
+		admin := caws.NewMockCognitoUserAdmin(t)
+		admin.EXPECT().AdminUpdateUserAttributes(mock.Anything, "us-east-1_example", guestUsername, mock.MatchedBy(func(attributes map[string]string) bool {
+			_, err := guest.ParseTime(attributes[guest.LastSeenAttribute])
+			return len(attributes) == 1 && err == nil
+		})).Return(recordErr).Once()
+
+		data, err := json.Marshal(gin.H{"username": guestUsername, "password": "fake_secret"})
+		require.NoError(t, err)
+		request, err := http.NewRequest(http.MethodPost, "/v1/users/login", bytes.NewReader(data))
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+
+		newTestServer(t, cognitoAuthService, WithCognitoUserAdmin(admin), WithGuestAccounts()).engine.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+}
+
+func TestNewServer_guestAccountsNeedUserAdmin(t *testing.T) {
+	_, err := NewServer(&cconfig.Config{}, caws.NewMockCognitoAuthService(t),
+		WithProfileRepository(profile.NewMemoryRepository()),
+		WithFriendsRepository(friends.NewMemoryRepository(cevents.NoopPublisher{})),
+		WithGuestAccounts(),
+	)
+	assert.Error(t, err)
+}
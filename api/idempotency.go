@@ -0,0 +1,121 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/whatisusername/toon-tank-user-service/internal/idempotency"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	replayContentType        = "application/json; charset=utf-8"
+)
+
+var (
+	errIdempotencyKeyTooLong = errors.New("idempotency key is too long")
+	errIdempotencyKeyReused  = errors.New("idempotency key was already used with a different request")
+	errIdempotencyInProgress = errors.New("a request with this idempotency key is still being processed")
+)
+
+// bodyRecorder keeps a copy of the response body as it is written.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotent lets clients retry a request with the same Idempotency-Key
+// header and get the original response back instead of repeating its side
+// effects. Reusing a key for a different request is rejected with 422.
+// Server errors are not remembered, so those can be retried for real. Store
+// failures let the request through without protection.
+func (s *Server) idempotent(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(idempotencyKeyHeader)
+		if s.idempotency == nil || key == "" {
+			ctx.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errIdempotencyKeyTooLong))
+			return
+		}
+
+		logger := logging.FromContext(ctx)
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		storeKey := scope + ":" + appClient(ctx).ClientID + ":" + key
+		requestHash := hashRequest(ctx.Request.Method, ctx.FullPath(), body)
+
+		claim, existing, err := s.idempotency.Start(ctx, storeKey, requestHash)
+		if err != nil {
+			logger.Error("Failed to claim idempotency key", "error", err)
+			ctx.Next()
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.RequestHash != requestHash:
+				ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, errorResponse(errIdempotencyKeyReused))
+			case existing.State != idempotency.StateCompleted:
+				ctx.Header("Retry-After", "1")
+				ctx.AbortWithStatusJSON(http.StatusConflict, errorResponse(errIdempotencyInProgress))
+			default:
+				logger.Info("Replaying idempotent response", "status", existing.StatusCode)
+				ctx.Header(idempotentReplayedHeader, "true")
+				ctx.Data(existing.StatusCode, replayContentType, existing.Body)
+				ctx.Abort()
+			}
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+
+		ctx.Next()
+
+		if status := recorder.Status(); status >= http.StatusInternalServerError {
+			err = s.idempotency.Release(ctx, storeKey, claim)
+		} else {
+			err = s.idempotency.Complete(ctx, storeKey, claim, status, recorder.body.Bytes())
+		}
+		if err != nil {
+			logger.Error("Failed to record idempotent response", "error", err)
+		}
+	}
+}
+
+func hashRequest(method, route string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(route))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/idempotency"
+)
+
+func TestServer_idempotent(t *testing.T) {
+	const body = `{"username":"test","email":"test@example.com","password":"test123456A"}`
+
+	type call struct {
+		key          string
+		body         string
+		wantStatus   int
+		wantReplayed bool
+	}
+	tests := []struct {
+		name       string
+		setupStore func(store idempotency.Store)
+		buildStubs func(authSvc *caws.MockCognitoAuthService)
+		calls      []call
+	}{
+		{
+			name: "Replayed",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+					Return(nil).Once()
+			},
+			calls: []call{
+				{key: "k1", body: body, wantStatus: http.StatusCreated},
+				{key: "k1", body: body, wantStatus: http.StatusCreated, wantReplayed: true},
+			},
+		},
+		{
+			name: "Different Body",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+					Return(nil).Once()
+			},
+			calls: []call{
+				{key: "k1", body: body, wantStatus: http.StatusCreated},
+				{key: "k1", body: strings.Replace(body, "test@", "other@", 1), wantStatus: http.StatusUnprocessableEntity},
+			},
+		},
+		{
+			name: "Validation Errors Replayed",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.AssertNotCalled(t, "SignUp")
+			},
+			calls: []call{
+				{key: "k1", body: strings.Replace(body, "test123456A", "weak", 1), wantStatus: http.StatusBadRequest},
+				{key: "k1", body: strings.Replace(body, "test123456A", "weak", 1), wantStatus: http.StatusBadRequest, wantReplayed: true},
+			},
+		},
+		{
+			name: "Server Error Not Remembered",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+					Return(errors.New("server is busy")).Once()
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+					Return(nil).Once()
+			},
+			calls: []call{
+				{key: "k1", body: body, wantStatus: http.StatusInternalServerError},
+				{key: "k1", body: body, wantStatus: http.StatusCreated},
+			},
+		},
+		{
+			name: "In Progress",
+			setupStore: func(store idempotency.Store) {
+				_, _, err := store.Start(context.Background(), "signup:fake_client_id:k1", hashRequest(http.MethodPost, "/v1/users", []byte(body)))
+				require.NoError(t, err)
+			},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.AssertNotCalled(t, "SignUp")
+			},
+			calls: []call{
+				{key: "k1", body: body, wantStatus: http.StatusConflict},
+			},
+		},
+		{
+			name: "Without Key",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+					Return(nil).Twice()
+			},
+			calls: []call{
+				{body: body, wantStatus: http.StatusCreated},
+				{body: body, wantStatus: http.StatusCreated},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			tt.buildStubs(cognitoAuthService)
+
+			store := idempotency.NewMemoryStore(time.Minute, time.Hour)
+			if tt.setupStore != nil {
+				tt.setupStore(store)
+			}
+
+			testServer := newTestServer(t, cognitoAuthService, WithIdempotencyStore(store))
+
+			var first string
+			for i, c := range tt.calls {
+				request, err := http.NewRequest(http.MethodPost, "/v1/users", strings.NewReader(c.body))
+				require.NoError(t, err)
+				if c.key != "" {
+					request.Header.Set(idempotencyKeyHeader, c.key)
+				}
+
+				recorder := httptest.NewRecorder()
+				testServer.engine.ServeHTTP(recorder, request)
+				assert.Equal(t, c.wantStatus, recorder.Code, "call %d", i)
+
+				if c.wantReplayed {
+					assert.Equal(t, "true", recorder.Header().Get(idempotentReplayedHeader))
+					assert.Equal(t, first, recorder.Body.String(), "replayed body differs")
+				} else {
+					assert.Empty(t, recorder.Header().Get(idempotentReplayedHeader))
+				}
+				if i == 0 {
+					first = recorder.Body.String()
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/friends"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+	"github.com/whatisusername/toon-tank-user-service/internal/profile"
+)
+
+var (
+	errNotFederated      = errors.New("token does not belong to a provider sign-in")
+	errAlreadyLinked     = errors.New("identity already signs in to this account")
+	errIdentityNotLinked = errors.New("identity is not linked to this account")
+	errLastLoginMethod   = errors.New("the last way to sign in can't be removed")
+	errPrimaryIdentity   = errors.New("the identity the account was created with can't be removed")
+	errSourceHasProgress = errors.New("the provider's own account has a profile or friends that linking would discard; set discardProgress to link anyway")
+	errSourceDeleted     = errors.New("the provider's own account was deleted but linking failed; sign in with the provider again to retry")
+)
+
+type identityResponse struct {
+	Provider string    `json:"provider"`
+	UserID   string    `json:"userId"`
+	LinkedAt time.Time `json:"linkedAt"`
+}
+
+type meResponse struct {
+	PlayerID          string             `json:"playerId"`
+	Username          string             `json:"username"`
+	PreferredUsername string             `json:"preferredUsername,omitempty"`
+	Email             string             `json:"email,omitempty"`
+	HasPassword       bool               `json:"hasPassword"`
+	Identities        []identityResponse `json:"identities"`
+}
+
+// getMe describes the caller's account and every way it can be signed in to.
+func (s *Server) getMe(ctx *gin.Context) {
+	user, identities, ok := s.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	resp := meResponse{
+		PlayerID:          user.Attributes["sub"],
+		Username:          user.Username,
+		PreferredUsername: user.Attributes["preferred_username"],
+		Email:             user.Attributes["email"],
+		HasPassword:       user.Status != caws.UserStatusExternalProvider,
+		Identities:        make([]identityResponse, len(identities)),
+	}
+	for i, identity := range identities {
+		resp.Identities[i] = identityResponse{
+			Provider: identity.ProviderName,
+			UserID:   identity.UserID,
+			LinkedAt: time.UnixMilli(identity.DateCreated).UTC(),
+		}
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(resp))
+}
+
+// linkIdentityRequest proves the caller owns the provider account: Token is
+// the access token from signing in with it through the hosted UI.
+// DiscardProgress confirms that the provider's own player may be thrown away
+// even though it has a profile or friends.
+type linkIdentityRequest struct {
+	Token           string `json:"token" binding:"required"`
+	DiscardProgress bool   `json:"discardProgress"`
+}
+
+// linkIdentity lets a provider account sign in to the caller's account. Signing
+// in with the provider created a separate user for it, which Cognito won't link
+// while it exists, so that user is deleted first. Anything stored against its
+// player ID is not carried over: if there is any, the caller has to confirm
+// with DiscardProgress, and it is removed along with the user.
+func (s *Server) linkIdentity(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	var req linkIdentityRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	t, err := s.validateToken(ctx, appClient(ctx).ClientID, req.Token)
+	if err != nil {
+		logger.Warn("Rejected provider token", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	claims, _ := t.Claims.(jwt.MapClaims)
+	sourceUsername, _ := claims["username"].(string)
+
+	username := ctx.GetString(usernameKey)
+	if sourceUsername == username {
+		ctx.JSON(http.StatusConflict, errorResponse(errAlreadyLinked))
+		return
+	}
+
+	pool := s.config.Cognito.UserPoolID
+	source, err := s.userAdmin.AdminGetUser(ctx, pool, sourceUsername)
+	if err != nil {
+		logger.Error("Failed to get provider user", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	identities, err := source.Identities()
+	if err != nil || source.Status != caws.UserStatusExternalProvider || len(identities) != 1 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errNotFederated))
+		return
+	}
+	identity := identities[0]
+
+	sourcePlayerID := source.Attributes["sub"]
+	hasProgress, err := s.hasProgress(ctx, sourcePlayerID)
+	if err != nil {
+		logger.Error("Failed to check provider player", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if hasProgress {
+		if !req.DiscardProgress {
+			ctx.JSON(http.StatusConflict, errorResponse(errSourceHasProgress))
+			return
+		}
+		// The player's data goes before the user, so that nothing is left
+		// behind under a player ID that no longer signs in.
+		if err = s.discardPlayer(ctx, sourcePlayerID); err != nil {
+			logger.Error("Failed to discard provider player", "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	if err = s.userAdmin.AdminDeleteUser(ctx, pool, sourceUsername); err != nil {
+		logger.Error("Failed to delete provider user", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if err = s.userAdmin.AdminLinkProviderForUser(ctx, pool, username, identity); err != nil {
+		// The provider user is gone, so signing in with the provider again
+		// creates a fresh one to retry with.
+		logger.Error("Failed to link identity", "provider", identity.ProviderName, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(errSourceDeleted))
+		return
+	}
+
+	s.publishEvent(ctx, cevents.TypeIdentityLinked, 1, cevents.IdentityLink{
+		Username: username,
+		Provider: identity.ProviderName,
+	})
+
+	logger.Info("Linked identity", "provider", identity.ProviderName)
+	ctx.Status(http.StatusNoContent)
+}
+
+// hasProgress reports whether playerID has a profile or any friends,
+// requests or blocks.
+func (s *Server) hasProgress(ctx context.Context, playerID string) (bool, error) {
+	_, err := s.profiles.Get(ctx, playerID)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, profile.ErrNotFound) {
+		return false, err
+	}
+
+	for _, state := range []friends.State{friends.StateFriends, friends.StateOutgoing, friends.StateIncoming, friends.StateBlocked} {
+		page, err := s.friends.List(ctx, playerID, state, 1, "")
+		if err != nil {
+			return false, err
+		}
+		if len(page.Edges) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// discardPlayer removes what is stored against playerID: its friendships,
+// requests and blocks on both sides, and its profile, which releases its
+// display name.
+func (s *Server) discardPlayer(ctx context.Context, playerID string) error {
+	if err := s.friends.RemovePlayer(ctx, playerID); err != nil {
+		return err
+	}
+	return s.profiles.Delete(ctx, playerID)
+}
+
+type unlinkIdentityRequest struct {
+	Provider string `uri:"provider" binding:"required"`
+}
+
+// unlinkIdentity stops a provider account from signing in to the caller's
+// account, unless that would leave no way to sign in at all.
+func (s *Server) unlinkIdentity(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	var req unlinkIdentityRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, identities, ok := s.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	var identity *caws.CognitoIdentity
+	for i := range identities {
+		if identities[i].ProviderName == req.Provider {
+			identity = &identities[i]
+			break
+		}
+	}
+	if identity == nil {
+		ctx.JSON(http.StatusNotFound, errorResponse(errIdentityNotLinked))
+		return
+	}
+
+	hasPassword := user.Status != caws.UserStatusExternalProvider
+	loginMethods := len(identities)
+	if hasPassword {
+		loginMethods++
+	}
+
+	switch {
+	case loginMethods <= 1:
+		ctx.JSON(http.StatusConflict, errorResponse(errLastLoginMethod))
+		return
+	// A provider user is its primary identity; disabling that one would
+	// disable the whole user rather than unlink it.
+	case !hasPassword && identity.Primary:
+		ctx.JSON(http.StatusConflict, errorResponse(errPrimaryIdentity))
+		return
+	}
+
+	if err := s.userAdmin.AdminDisableProviderForUser(ctx, s.config.Cognito.UserPoolID, *identity); err != nil {
+		logger.Error("Failed to unlink identity", "provider", identity.ProviderName, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.publishEvent(ctx, cevents.TypeIdentityUnlinked, 1, cevents.IdentityLink{
+		Username: user.Username,
+		Provider: identity.ProviderName,
+	})
+
+	logger.Info("Unlinked identity", "provider", identity.ProviderName)
+	ctx.Status(http.StatusNoContent)
+}
+
+// currentUser loads the caller's user and identities, responding on failure.
+func (s *Server) currentUser(ctx *gin.Context) (caws.CognitoUser, []caws.CognitoIdentity, bool) {
+	logger := logging.FromContext(ctx)
+
+	user, err := s.userAdmin.AdminGetUser(ctx, s.config.Cognito.UserPoolID, ctx.GetString(usernameKey))
+	if err != nil {
+		logger.Error("Failed to get user", "error", err)
+		if errorReason(err) == codeUserNotFoundException {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return caws.CognitoUser{}, nil, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return caws.CognitoUser{}, nil, false
+	}
+
+	identities, err := user.Identities()
+	if err != nil {
+		logger.Error("Failed to read identities", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return caws.CognitoUser{}, nil, false
+	}
+	return user, identities, true
+}
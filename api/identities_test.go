@@ -0,0 +1,295 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/friends"
+	"github.com/whatisusername/toon-tank-user-service/internal/profile"
+)
+
+var (
+	testGoogleIdentity  = caws.CognitoIdentity{UserID: "1234", ProviderName: "Google", ProviderType: "Google", DateCreated: 1704067200000}
+	testDiscordIdentity = caws.CognitoIdentity{UserID: "5678", ProviderName: "Discord", ProviderType: "OIDC", DateCreated: 1704067200000}
+)
+
+func newTestCognitoUser(t *testing.T, username, status string, identities ...caws.CognitoIdentity) caws.CognitoUser {
+	t.Helper()
+	user := caws.CognitoUser{
+		Username:   username,
+		Status:     status,
+		Attributes: map[string]string{"sub": "fake_sub", "email": "test@example.com"},
+	}
+	if len(identities) > 0 {
+		raw, err := json.Marshal(identities)
+		require.NoError(t, err)
+		user.Attributes["identities"] = string(raw)
+	}
+	return user
+}
+
+func TestServer_getMe(t *testing.T) {
+	cognitoAuthService := caws.NewMockCognitoAuthService(t)
+	mockTokenValidation(cognitoAuthService, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "sub": "fake_sub", "username": "test"})
+	admin := caws.NewMockCognitoUserAdmin(t)
+	admin.EXPECT().AdminGetUser(mock.Anything, "us-east-1_example", "test").
+		Return(newTestCognitoUser(t, "test", "CONFIRMED", testGoogleIdentity), nil).Once()
+
+	request, err := http.NewRequest(http.MethodGet, "/v1/me", nil)
+	require.NoError(t, err)
+	request.Header.Set("Authorization", "Bearer fake_access_token")
+	recorder := httptest.NewRecorder()
+
+	newTestServer(t, cognitoAuthService, WithCognitoUserAdmin(admin)).engine.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var resp struct {
+		Data meResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, meResponse{
+		PlayerID:    "fake_sub",
+		Username:    "test",
+		Email:       "test@example.com",
+		HasPassword: true,
+		Identities: []identityResponse{
+			{Provider: "Google", UserID: "1234", LinkedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}, resp.Data)
+}
+
+func TestServer_linkIdentity(t *testing.T) {
+	mockProviderUser := func(authSvc *caws.MockCognitoAuthService, admin *caws.MockCognitoUserAdmin) {
+		mockTokenValidation(authSvc, "us-east-1_example", "fake_provider_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "google_1234"})
+		admin.EXPECT().AdminGetUser(mock.Anything, "us-east-1_example", "google_1234").
+			Return(newTestCognitoUser(t, "google_1234", caws.UserStatusExternalProvider, testGoogleIdentity), nil).Once()
+	}
+
+	tests := []struct {
+		name          string
+		body          gin.H
+		sourceProfile bool
+		sourceFriend  bool
+		buildStubs    func(authSvc *caws.MockCognitoAuthService, admin *caws.MockCognitoUserAdmin)
+		wantStatus    int
+		wantMessage   string
+		wantDiscarded bool
+	}{
+		{
+			name: "OK",
+			body: gin.H{"token": "fake_provider_token"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService, admin *caws.MockCognitoUserAdmin) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_provider_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "google_1234"})
+				admin.EXPECT().AdminGetUser(mock.Anything, "us-east-1_example", "google_1234").
+					Return(newTestCognitoUser(t, "google_1234", caws.UserStatusExternalProvider, testGoogleIdentity), nil).Once()
+				admin.EXPECT().AdminDeleteUser(mock.Anything, "us-east-1_example", "google_1234").Return(nil).Once()
+				admin.EXPECT().AdminLinkProviderForUser(mock.Anything, "us-east-1_example", "test", testGoogleIdentity).Return(nil).Once()
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:          "Source Has Profile",
+			body:          gin.H{"token": "fake_provider_token"},
+			sourceProfile: true,
+			buildStubs:    mockProviderUser,
+			wantStatus:    http.StatusConflict,
+			wantMessage:   errSourceHasProgress.Error(),
+		},
+		{
+			name:         "Source Has Friends",
+			body:         gin.H{"token": "fake_provider_token"},
+			sourceFriend: true,
+			buildStubs:   mockProviderUser,
+			wantStatus:   http.StatusConflict,
+			wantMessage:  errSourceHasProgress.Error(),
+		},
+		{
+			name:          "Discard Progress",
+			body:          gin.H{"token": "fake_provider_token", "discardProgress": true},
+			sourceProfile: true,
+			sourceFriend:  true,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService, admin *caws.MockCognitoUserAdmin) {
+				mockProviderUser(authSvc, admin)
+				admin.EXPECT().AdminDeleteUser(mock.Anything, "us-east-1_example", "google_1234").Return(nil).Once()
+				admin.EXPECT().AdminLinkProviderForUser(mock.Anything, "us-east-1_example", "test", testGoogleIdentity).Return(nil).Once()
+			},
+			wantStatus:    http.StatusNoContent,
+			wantDiscarded: true,
+		},
+		{
+			name: "Link Fails After Delete",
+			body: gin.H{"token": "fake_provider_token"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService, admin *caws.MockCognitoUserAdmin) {
+				mockProviderUser(authSvc, admin)
+				admin.EXPECT().AdminDeleteUser(mock.Anything, "us-east-1_example", "google_1234").Return(nil).Once()
+				admin.EXPECT().AdminLinkProviderForUser(mock.Anything, "us-east-1_example", "test", testGoogleIdentity).
+					Return(errors.New("service unavailable")).Once()
+			},
+			wantStatus:  http.StatusInternalServerError,
+			wantMessage: errSourceDeleted.Error(),
+		},
+		{
+			name: "Native User Token",
+			body: gin.H{"token": "fake_provider_token"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService, admin *caws.MockCognitoUserAdmin) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_provider_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "other"})
+				admin.EXPECT().AdminGetUser(mock.Anything, "us-east-1_example", "other").
+					Return(newTestCognitoUser(t, "other", "CONFIRMED"), nil).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Own Token",
+			body: gin.H{"token": "fake_provider_token"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService, admin *caws.MockCognitoUserAdmin) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_provider_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test"})
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name: "Invalid Token",
+			body: gin.H{"token": "fake_provider_token"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService, admin *caws.MockCognitoUserAdmin) {
+				authSvc.EXPECT().ValidateToken(mock.Anything, "us-east-1_example", "fake_provider_token").
+					Return(nil, errors.New("token is expired")).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Missing Token",
+			body:       gin.H{},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService, admin *caws.MockCognitoUserAdmin) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			mockTokenValidation(cognitoAuthService, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "sub": "fake_sub", "username": "test"})
+			admin := caws.NewMockCognitoUserAdmin(t)
+			tt.buildStubs(cognitoAuthService, admin)
+
+			profiles := newTestProfiles(t)
+			if tt.sourceProfile {
+				profiles = newTestProfiles(t, "fake_sub")
+			}
+			repo := friends.NewMemoryRepository(cevents.NoopPublisher{})
+			if tt.sourceFriend {
+				_, err := friends.NewService(repo).SendRequest(context.Background(), "player-2", "fake_sub")
+				require.NoError(t, err)
+			}
+
+			data, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/me/identities", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Authorization", "Bearer fake_access_token")
+			recorder := httptest.NewRecorder()
+
+			newTestServer(t, cognitoAuthService,
+				WithCognitoUserAdmin(admin),
+				WithProfileRepository(profiles),
+				WithFriendsRepository(repo),
+			).engine.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantDiscarded {
+				_, err = profiles.Get(context.Background(), "fake_sub")
+				assert.ErrorIs(t, err, profile.ErrNotFound)
+				_, err = profiles.Rename(context.Background(), "player-3", "Name fake_sub", time.Hour)
+				assert.NoError(t, err, "the discarded player's name is released")
+				pair, err := repo.Get(context.Background(), "player-2", "fake_sub")
+				require.NoError(t, err)
+				assert.Equal(t, friends.Pair{}, pair, "the other player's side is cleared too")
+			}
+			if tt.wantMessage != "" {
+				var resp response
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantMessage, resp.Message)
+			}
+		})
+	}
+}
+
+func TestServer_unlinkIdentity(t *testing.T) {
+	primaryGoogle := testGoogleIdentity
+	primaryGoogle.Primary = true
+
+	tests := []struct {
+		name       string
+		provider   string
+		user       caws.CognitoUser
+		buildStubs func(admin *caws.MockCognitoUserAdmin)
+		wantStatus int
+	}{
+		{
+			name:     "OK",
+			provider: "Google",
+			user:     newTestCognitoUser(t, "test", "CONFIRMED", testGoogleIdentity),
+			buildStubs: func(admin *caws.MockCognitoUserAdmin) {
+				admin.EXPECT().AdminDisableProviderForUser(mock.Anything, "us-east-1_example", testGoogleIdentity).Return(nil).Once()
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:     "Linked To Provider User",
+			provider: "Discord",
+			user:     newTestCognitoUser(t, "google_1234", caws.UserStatusExternalProvider, primaryGoogle, testDiscordIdentity),
+			buildStubs: func(admin *caws.MockCognitoUserAdmin) {
+				admin.EXPECT().AdminDisableProviderForUser(mock.Anything, "us-east-1_example", testDiscordIdentity).Return(nil).Once()
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "Last Login Method",
+			provider:   "Google",
+			user:       newTestCognitoUser(t, "google_1234", caws.UserStatusExternalProvider, primaryGoogle),
+			buildStubs: func(admin *caws.MockCognitoUserAdmin) {},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "Primary Identity",
+			provider:   "Google",
+			user:       newTestCognitoUser(t, "google_1234", caws.UserStatusExternalProvider, primaryGoogle, testDiscordIdentity),
+			buildStubs: func(admin *caws.MockCognitoUserAdmin) {},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "Not Linked",
+			provider:   "Discord",
+			user:       newTestCognitoUser(t, "test", "CONFIRMED", testGoogleIdentity),
+			buildStubs: func(admin *caws.MockCognitoUserAdmin) {},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			mockTokenValidation(cognitoAuthService, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "sub": "fake_sub", "username": tt.user.Username})
+			admin := caws.NewMockCognitoUserAdmin(t)
+			admin.EXPECT().AdminGetUser(mock.Anything, "us-east-1_example", tt.user.Username).Return(tt.user, nil).Once()
+			tt.buildStubs(admin)
+
+			request, err := http.NewRequest(http.MethodDelete, "/v1/me/identities/"+tt.provider, nil)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", "Bearer fake_access_token")
+			recorder := httptest.NewRecorder()
+
+			newTestServer(t, cognitoAuthService, WithCognitoUserAdmin(admin)).engine.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/gin-gonic/gin"
+	"github.com/whatisusername/toon-tank-user-service/internal/lockout"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+)
+
+const reasonAccountLocked = "AccountLocked"
+
+var errAccountLocked = errors.New("account is temporarily locked, please try again later")
+
+// checkLockout returns the lockout status of the account and, if it is
+// currently locked, responds with 423 and a Retry-After header. Tracker
+// failures let the login through, since Cognito still applies its own
+// protections.
+func (s *Server) checkLockout(ctx *gin.Context, key string) (lockout.Status, bool) {
+	if s.lockout == nil {
+		return lockout.Status{}, false
+	}
+
+	status, err := s.lockout.Status(ctx, key)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to check account lockout", "error", err)
+		return lockout.Status{}, false
+	}
+
+	now := time.Now()
+	if !status.Locked(now) {
+		return status, false
+	}
+
+	logging.FromContext(ctx).Warn("Account locked", "failures", status.Failures, "lockedUntil", status.LockedUntil)
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(status.LockedUntil.Sub(now).Seconds()))))
+	ctx.JSON(http.StatusLocked, errorResponse(errAccountLocked))
+	return status, true
+}
+
+// recordLoginFailure counts err against the account if it means the
+// credentials were wrong.
+func (s *Server) recordLoginFailure(ctx *gin.Context, key string, err error) {
+	if s.lockout == nil || !isCredentialError(err) {
+		return
+	}
+
+	status, err := s.lockout.RecordFailure(ctx, key)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to record login failure", "error", err)
+		return
+	}
+	if !status.LockedUntil.IsZero() {
+		logging.FromContext(ctx).Warn("Account locked after failed login", "failures", status.Failures, "lockedUntil", status.LockedUntil)
+	}
+}
+
+func (s *Server) resetLockout(ctx *gin.Context, key string) {
+	if s.lockout == nil {
+		return
+	}
+
+	if err := s.lockout.Reset(ctx, key); err != nil {
+		logging.FromContext(ctx).Error("Failed to reset account lockout", "error", err)
+	}
+}
+
+func isCredentialError(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.ErrorCode() {
+	case codeNotAuthorizedException, codeUserNotFoundException:
+		return true
+	}
+	return false
+}
+
+type unlockUserRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+func (s *Server) unlockUser(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	var req unlockUserRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		logger.Error("Failed to bind request", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if s.lockout == nil {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	if err := s.lockout.Reset(ctx, normalizeUsername(req.Username)); err != nil {
+		logger.Error("Failed to unlock account", "target", req.Username, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("failed to unlock account: %w", err)))
+		return
+	}
+
+	logger.Info("Account unlocked", "target", req.Username)
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/lockout"
+)
+
+var testLockoutPolicy = lockout.Policy{Threshold: 2, BaseLock: time.Minute, MaxLock: time.Hour, Window: time.Hour}
+
+func TestServer_lockout(t *testing.T) {
+	fakeToken := &caws.CognitoToken{
+		IdToken:      "fake_id_token",
+		AccessToken:  "fake_access_token",
+		RefreshToken: "fake_refresh_token",
+	}
+
+	tests := []struct {
+		name         string
+		passwords    []string
+		buildStubs   func(authSvc *caws.MockCognitoAuthService)
+		wantStatuses []int
+		wantFailures int
+	}{
+		{
+			name:      "Locked After Threshold",
+			passwords: []string{"wrong", "wrong", "test123456A"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "wrong").
+					Return(nil, &smithy.GenericAPIError{Code: "NotAuthorizedException"}).Twice()
+			},
+			wantStatuses: []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusLocked},
+			wantFailures: 2,
+		},
+		{
+			name:      "Reset On Success",
+			passwords: []string{"wrong", "test123456A", "wrong"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "wrong").
+					Return(nil, &smithy.GenericAPIError{Code: "NotAuthorizedException"}).Twice()
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A").
+					Return(fakeToken, nil).Once()
+
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test"})
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_id_token", jwt.MapClaims{"aud": "fake_client_id", "cognito:username": "test", "email": "test@example.com"})
+
+				authSvc.EXPECT().ParseUserInfo(mock.AnythingOfType("*jwt.Token")).
+					Return(&caws.CognitoUserInfo{Username: "test", Email: "test@example.com"}, nil).Once()
+			},
+			wantStatuses: []int{http.StatusUnauthorized, http.StatusOK, http.StatusUnauthorized},
+			wantFailures: 1,
+		},
+		{
+			name:      "Service Errors Not Counted",
+			passwords: []string{"test123456A", "test123456A", "test123456A"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A").
+					Return(nil, &smithy.GenericAPIError{Code: "TooManyRequestsException"}).Times(3)
+			},
+			wantStatuses: []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			wantFailures: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			tt.buildStubs(cognitoAuthService)
+
+			tracker := lockout.NewMemoryTracker(testLockoutPolicy)
+			testServer := newTestServer(t, cognitoAuthService, WithLockoutTracker(tracker))
+
+			for i, password := range tt.passwords {
+				body := `{"username":"test","password":"` + password + `"}`
+				request, err := http.NewRequest(http.MethodPost, "/v1/users/login", strings.NewReader(body))
+				require.NoError(t, err)
+
+				recorder := httptest.NewRecorder()
+				testServer.engine.ServeHTTP(recorder, request)
+				assert.Equal(t, tt.wantStatuses[i], recorder.Code, "request %d", i)
+
+				if recorder.Code == http.StatusLocked {
+					assert.Equal(t, "60", recorder.Header().Get("Retry-After"))
+				}
+			}
+
+			status, err := tracker.Status(context.Background(), "test")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFailures, status.Failures)
+		})
+	}
+}
+
+func TestServer_unlockUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		buildStubs func(authSvc *caws.MockCognitoAuthService)
+		wantStatus int
+		wantLocked bool
+	}{
+		{
+			name:  "OK",
+			token: "fake_admin_token",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_admin_token", jwt.MapClaims{
+					"token_use":      "access",
+					"client_id":      "fake_client_id",
+					"username":       "admin",
+					"cognito:groups": []interface{}{"players", "admin"},
+				})
+			},
+			wantStatus: http.StatusNoContent,
+			wantLocked: false,
+		},
+		{
+			name:       "Missing Token",
+			token:      "",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus: http.StatusUnauthorized,
+			wantLocked: true,
+		},
+		{
+			name:  "Not Admin",
+			token: "fake_player_token",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_player_token", jwt.MapClaims{
+					"token_use":      "access",
+					"client_id":      "fake_client_id",
+					"username":       "player",
+					"cognito:groups": []interface{}{"players"},
+				})
+			},
+			wantStatus: http.StatusForbidden,
+			wantLocked: true,
+		},
+		{
+			name:  "Token Client Mismatch",
+			token: "fake_admin_token",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_admin_token", jwt.MapClaims{
+					"token_use":      "access",
+					"client_id":      "fake_pc_client_id",
+					"username":       "admin",
+					"cognito:groups": []interface{}{"admin"},
+				})
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantLocked: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			tt.buildStubs(cognitoAuthService)
+
+			tracker := lockout.NewMemoryTracker(testLockoutPolicy)
+			for i := 0; i < testLockoutPolicy.Threshold; i++ {
+				_, err := tracker.RecordFailure(ctx, "test")
+				require.NoError(t, err)
+			}
+
+			testServer := newTestServer(t, cognitoAuthService, WithLockoutTracker(tracker))
+
+			request, err := http.NewRequest(http.MethodDelete, "/v1/admin/users/Test/lockout", nil)
+			require.NoError(t, err)
+			if tt.token != "" {
+				request.Header.Set(authorizationHeader, "Bearer "+tt.token)
+			}
+
+			recorder := httptest.NewRecorder()
+			testServer.engine.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+
+			status, err := tracker.Status(ctx, "test")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLocked, status.Locked(time.Now()))
+		})
+	}
+}
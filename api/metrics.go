@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/whatisusername/toon-tank-user-service/internal/metrics"
+)
+
+const (
+	metricSignUp          = "SignUp"
+	metricLogin           = "Login"
+	metricTokenValidation = "TokenValidation"
+	metricPasswordChange  = "PasswordChange"
+	metricPasswordReset   = "PasswordReset"
+	metricAccountDeletion = "AccountDeletion"
+
+	outcomeSuccess = "success"
+	outcomeFailure = "failure"
+
+	reasonInvalidRequest   = "InvalidRequest"
+	reasonInvalidPassword  = "InvalidPassword"
+	reasonValidationFailed = "ValidationFailed"
+	reasonInvalidToken     = "InvalidToken"
+	reasonClientMismatch   = "ClientMismatch"
+	reasonInternalError    = "InternalError"
+)
+
+// recordOutcome counts one attempt at operation and observes its latency. An
+// empty reason means the attempt succeeded; otherwise the counter is also
+// split by reason so failures can be charted by cause.
+func (s *Server) recordOutcome(ctx context.Context, operation string, start time.Time, reason string) {
+	dims := metrics.Dimensions{"Outcome": outcomeSuccess}
+	if reason != "" {
+		dims = metrics.Dimensions{"Outcome": outcomeFailure, "Reason": reason}
+	}
+
+	s.metrics.Count(ctx, operation, 1, dims)
+	metrics.ObserveDuration(ctx, s.metrics, operation+"Latency", start, metrics.Dimensions{"Outcome": dims["Outcome"]})
+}
+
+// errorReason reports the Cognito error code behind err, if any.
+func errorReason(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return reasonInternalError
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/metrics"
+)
+
+func TestServer_metrics(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		body        gin.H
+		buildStubs  func(authSvc *caws.MockCognitoAuthService)
+		checkMetric func(recorder *metrics.MemoryRecorder)
+	}{
+		{
+			name: "Sign Up Succeeded",
+			url:  "/v1/users",
+			body: gin.H{"username": "test", "email": "test@example.com", "password": "test123456A"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+					Return(nil).Once()
+			},
+			checkMetric: func(recorder *metrics.MemoryRecorder) {
+				assert.Equal(t, 1.0, recorder.Sum(metricSignUp, metrics.Dimensions{"Outcome": outcomeSuccess}))
+				assert.Len(t, latencySamples(recorder, metricSignUp+"Latency"), 1)
+			},
+		},
+		{
+			name: "Sign Up Rejected By Cognito",
+			url:  "/v1/users",
+			body: gin.H{"username": "test", "email": "test@example.com", "password": "test123456A"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+					Return(&smithy.GenericAPIError{Code: "UsernameExistsException"}).Once()
+			},
+			checkMetric: func(recorder *metrics.MemoryRecorder) {
+				assert.Equal(t, 1.0, recorder.Sum(metricSignUp, metrics.Dimensions{"Outcome": outcomeFailure, "Reason": "UsernameExistsException"}))
+				assert.Equal(t, 0.0, recorder.Sum(metricSignUp, metrics.Dimensions{"Outcome": outcomeSuccess}))
+			},
+		},
+		{
+			name: "Sign Up Invalid Request",
+			url:  "/v1/users",
+			body: gin.H{"username": "test"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.AssertNotCalled(t, "SignUp")
+			},
+			checkMetric: func(recorder *metrics.MemoryRecorder) {
+				assert.Equal(t, 1.0, recorder.Sum(metricSignUp, metrics.Dimensions{"Outcome": outcomeFailure, "Reason": reasonInvalidRequest}))
+			},
+		},
+		{
+			name: "Login Succeeded",
+			url:  "/v1/users/login",
+			body: gin.H{"username": "test", "password": "test123456A"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A").
+					Return(&caws.CognitoToken{IdToken: "fake_id_token", AccessToken: "fake_access_token"}, nil).Once()
+
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test"})
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_id_token", jwt.MapClaims{"aud": "fake_client_id", "cognito:username": "test"})
+
+				authSvc.EXPECT().ParseUserInfo(mock.AnythingOfType("*jwt.Token")).
+					Return(&caws.CognitoUserInfo{Username: "test", Email: "test@example.com"}, nil).Once()
+			},
+			checkMetric: func(recorder *metrics.MemoryRecorder) {
+				assert.Equal(t, 1.0, recorder.Sum(metricLogin, metrics.Dimensions{"Outcome": outcomeSuccess}))
+				assert.Equal(t, 2.0, recorder.Sum(metricTokenValidation, metrics.Dimensions{"Outcome": outcomeSuccess}))
+				assert.Len(t, latencySamples(recorder, metricTokenValidation+"Latency"), 2)
+			},
+		},
+		{
+			name: "Login Wrong Password",
+			url:  "/v1/users/login",
+			body: gin.H{"username": "test", "password": "test123456"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456").
+					Return(nil, &smithy.GenericAPIError{Code: "NotAuthorizedException"}).Once()
+			},
+			checkMetric: func(recorder *metrics.MemoryRecorder) {
+				assert.Equal(t, 1.0, recorder.Sum(metricLogin, metrics.Dimensions{"Outcome": outcomeFailure, "Reason": "NotAuthorizedException"}))
+				assert.Equal(t, 0.0, recorder.Sum(metricTokenValidation, nil))
+			},
+		},
+		{
+			name: "Login Token Client Mismatch",
+			url:  "/v1/users/login",
+			body: gin.H{"username": "test", "password": "test123456A"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A").
+					Return(&caws.CognitoToken{IdToken: "fake_id_token", AccessToken: "fake_access_token"}, nil).Once()
+
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_pc_client_id", "username": "test"})
+			},
+			checkMetric: func(recorder *metrics.MemoryRecorder) {
+				assert.Equal(t, 1.0, recorder.Sum(metricLogin, metrics.Dimensions{"Outcome": outcomeFailure, "Reason": reasonInvalidToken}))
+				assert.Equal(t, 1.0, recorder.Sum(metricTokenValidation, metrics.Dimensions{"Outcome": outcomeFailure, "Reason": reasonClientMismatch}))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			tt.buildStubs(cognitoAuthService)
+
+			recorder := metrics.NewMemoryRecorder()
+			testServer := newTestServer(t, cognitoAuthService, WithMetricsRecorder(recorder))
+
+			data, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, tt.url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			testServer.engine.ServeHTTP(httptest.NewRecorder(), request)
+			tt.checkMetric(recorder)
+		})
+	}
+}
+
+func latencySamples(recorder *metrics.MemoryRecorder, name string) []metrics.Sample {
+	var samples []metrics.Sample
+	for _, s := range recorder.Samples() {
+		if s.Name == name {
+			samples = append(samples, s)
+		}
+	}
+	return samples
+}
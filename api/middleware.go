@@ -0,0 +1,71 @@
+package api
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/awslabs/aws-lambda-go-api-proxy/core"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	requestIDHeader = "X-Request-Id"
+	requestIDKey    = "requestId"
+	usernameKey     = "username"
+)
+
+// requestLogger tags the request with a correlation ID, scopes a logger to it
+// for everything downstream, and writes one access-log line once the request
+// has been handled.
+func (s *Server) requestLogger(ctx *gin.Context) {
+	start := time.Now()
+
+	requestID := requestIDFromContext(ctx)
+	logger := slog.Default().With("requestId", requestID)
+	if sc := trace.SpanContextFromContext(ctx.Request.Context()); sc.IsValid() {
+		logger = logger.With("traceId", sc.TraceID().String())
+	}
+	reqCtx := logging.WithLogger(ctx.Request.Context(), logger)
+	ctx.Request = ctx.Request.WithContext(cevents.WithCorrelationID(reqCtx, requestID))
+	ctx.Header(requestIDHeader, requestID)
+	ctx.Set(requestIDKey, requestID)
+
+	ctx.Next()
+
+	attrs := []any{
+		"method", ctx.Request.Method,
+		"route", ctx.FullPath(),
+		"status", ctx.Writer.Status(),
+		"latency", time.Since(start),
+	}
+	if username := ctx.GetString(usernameKey); username != "" {
+		attrs = append(attrs, "username", username)
+	}
+	if len(ctx.Errors) > 0 {
+		attrs = append(attrs, "errors", ctx.Errors.String())
+	}
+	logger.Info("Request completed", attrs...)
+}
+
+// markColdStart flags the span of the first request served by this Lambda
+// instance, which is the one that paid for the init phase.
+func (s *Server) markColdStart(ctx *gin.Context) {
+	trace.SpanFromContext(ctx.Request.Context()).SetAttributes(semconv.FaaSColdstart(s.coldStart.Swap(false)))
+	ctx.Next()
+}
+
+func requestIDFromContext(ctx *gin.Context) string {
+	if gwCtx, ok := core.GetAPIGatewayContextFromContext(ctx.Request.Context()); ok && gwCtx.RequestID != "" {
+		return gwCtx.RequestID
+	}
+	if lc, ok := lambdacontext.FromContext(ctx.Request.Context()); ok && lc.AwsRequestID != "" {
+		return lc.AwsRequestID
+	}
+	return uuid.NewString()
+}
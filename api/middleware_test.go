@@ -0,0 +1,151 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+func TestServer_requestLogger(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		requestID     string
+		wantRequestID string
+	}{
+		{
+			name:          "API Gateway Request ID",
+			ctx:           lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "fake_aws_request_id"}),
+			requestID:     "fake_gateway_request_id",
+			wantRequestID: "fake_gateway_request_id",
+		},
+		{
+			name:          "Lambda Request ID",
+			ctx:           lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "fake_aws_request_id"}),
+			requestID:     "",
+			wantRequestID: "fake_aws_request_id",
+		},
+		{
+			name:          "Generated Request ID",
+			ctx:           context.Background(),
+			requestID:     "",
+			wantRequestID: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			cognitoAuthService.EXPECT().
+				SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+				Return(errors.New("server is busy")).Once()
+
+			testServer := newTestServer(t, cognitoAuthService)
+			logs := captureLogs(t)
+
+			resp, err := testServer.HandleRequest(tt.ctx, events.APIGatewayProxyRequest{
+				Path:       "/v1/users",
+				HTTPMethod: http.MethodPost,
+				Body:       `{"username":"test","email":"test@example.com","password":"test123456A"}`,
+				RequestContext: events.APIGatewayProxyRequestContext{
+					RequestID: tt.requestID,
+				},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+			requestID := http.Header(resp.MultiValueHeaders).Get(requestIDHeader)
+			if tt.wantRequestID == "" {
+				assert.NoError(t, uuid.Validate(requestID), "expected a generated request id")
+			} else {
+				assert.Equal(t, tt.wantRequestID, requestID)
+			}
+
+			var accessLog map[string]interface{}
+			for _, line := range bytes.Split(bytes.TrimSpace(logs.Bytes()), []byte("\n")) {
+				var record map[string]interface{}
+				require.NoError(t, json.Unmarshal(line, &record))
+				if record["msg"] == "Received request" {
+					continue
+				}
+				assert.Equal(t, requestID, record["requestId"], "log line is missing the request id: %s", line)
+
+				if record["msg"] == "Request completed" {
+					accessLog = record
+				}
+			}
+			require.NotNil(t, accessLog, "access log line not written")
+			assert.Equal(t, "/v1/users", accessLog["route"])
+			assert.Equal(t, float64(http.StatusInternalServerError), accessLog["status"])
+			assert.Equal(t, "test", accessLog["username"])
+			assert.Contains(t, accessLog, "latency")
+		})
+	}
+}
+
+// captureLogs redirects the default logger to a JSON buffer for the duration
+// of the test.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	return &buf
+}
+
+func TestServer_tracing(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	cognitoAuthService := caws.NewMockCognitoAuthService(t)
+	cognitoAuthService.EXPECT().
+		SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+		Return(nil).Twice()
+
+	testServer := newTestServer(t, cognitoAuthService, WithTracerProvider(tp))
+
+	for i := 0; i < 2; i++ {
+		body := `{"username":"test","email":"test@example.com","password":"test123456A"}`
+		request, err := http.NewRequest(http.MethodPost, "/v1/users", strings.NewReader(body))
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		testServer.engine.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+	}
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 2)
+	for i, span := range spans {
+		assert.Equal(t, "/v1/users", span.Name())
+
+		attrs := attribute.NewSet(span.Attributes()...)
+		route, ok := attrs.Value(semconv.HTTPRouteKey)
+		assert.True(t, ok, "span is missing the http.route attribute")
+		assert.Equal(t, "/v1/users", route.AsString())
+
+		coldStart, ok := attrs.Value(semconv.FaaSColdstartKey)
+		assert.True(t, ok, "span is missing the faas.coldstart attribute")
+		assert.Equal(t, i == 0, coldStart.AsBool())
+	}
+}
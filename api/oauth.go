@@ -0,0 +1,173 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+	"github.com/whatisusername/toon-tank-user-service/internal/oauth"
+)
+
+const (
+	metricFederatedLogin = "FederatedLogin"
+
+	reasonAuthorizationDenied = "AuthorizationDenied"
+	reasonInvalidState        = "InvalidState"
+	reasonInvalidGrant        = "InvalidGrant"
+)
+
+var (
+	errUnknownProvider  = errors.New("identity provider is not supported")
+	errInvalidChallenge = errors.New("code_challenge must be an S256 PKCE challenge")
+	errMissingCode      = errors.New("authorization code is required")
+	errInvalidVerifier  = errors.New("code_verifier must be the PKCE verifier the sign-in was started with")
+	errSignInIncomplete = errors.New("sign-in was not completed")
+)
+
+type authorizeRequest struct {
+	Provider      string `form:"provider"`
+	CodeChallenge string `form:"code_challenge" binding:"required"`
+}
+
+type authorizeResponse struct {
+	URL   string `json:"url"`
+	State string `json:"state"`
+}
+
+// authorizeOAuth starts a sign-in through the hosted UI. The client opens URL
+// in a browser and should check that the callback carries the same state. The
+// client keeps the PKCE verifier behind code_challenge to itself, so a code
+// and state caught on the way back cannot be redeemed by anyone else.
+func (s *Server) authorizeOAuth(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	var req authorizeRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to bind request", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	cfg := s.config.OAuth
+	if req.Provider != "" && len(cfg.Providers) > 0 && !slices.Contains(cfg.Providers, req.Provider) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errUnknownProvider))
+		return
+	}
+
+	if !oauth.ValidChallenge(req.CodeChallenge) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidChallenge))
+		return
+	}
+
+	state, err := s.oauthStates.Seal(oauth.State{Platform: ctx.GetString(clientPlatformKey)})
+	if err != nil {
+		logger.Error("Failed to seal state", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(authorizeResponse{
+		URL: s.oauth.AuthorizeURL(oauth.AuthorizeRequest{
+			ClientID:         appClient(ctx).ClientID,
+			RedirectURI:      cfg.RedirectURI,
+			State:            state,
+			CodeChallenge:    req.CodeChallenge,
+			Scopes:           cfg.Scopes,
+			IdentityProvider: req.Provider,
+		}),
+		State: state,
+	}))
+}
+
+type oauthCallbackRequest struct {
+	Code             string `json:"code"`
+	CodeVerifier     string `json:"codeVerifier"`
+	State            string `json:"state" binding:"required"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"errorDescription"`
+}
+
+// oauthCallback finishes a hosted UI sign-in. The game client catches the
+// redirect itself and posts the code and state from it together with its PKCE
+// verifier, in the body so that none of them end up in access logs. The app
+// client comes from the state rather than the request, since the platform the
+// sign-in started on is sealed there.
+func (s *Server) oauthCallback(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	start := time.Now()
+	reason := reasonInternalError
+	defer func() { s.recordOutcome(ctx, metricFederatedLogin, start, reason) }()
+
+	var req oauthCallbackRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", "error", err)
+		reason = reasonInvalidRequest
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Error != "" {
+		logger.Warn("Hosted UI returned an error", "error", req.Error, "description", req.ErrorDescription)
+		reason = reasonAuthorizationDenied
+		ctx.JSON(http.StatusBadRequest, errorResponse(errSignInIncomplete))
+		return
+	}
+	if req.Code == "" {
+		reason = reasonInvalidRequest
+		ctx.JSON(http.StatusBadRequest, errorResponse(errMissingCode))
+		return
+	}
+	if !oauth.ValidVerifier(req.CodeVerifier) {
+		reason = reasonInvalidRequest
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidVerifier))
+		return
+	}
+
+	state, err := s.oauthStates.Open(req.State)
+	if err != nil {
+		logger.Warn("Rejected oauth state", "error", err)
+		reason = reasonInvalidState
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	client, err := s.config.Cognito.Client(state.Platform)
+	if err != nil {
+		logger.Error("Failed to select app client", "platform", state.Platform, "error", err)
+		reason = reasonInvalidState
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	ctx.Set(appClientKey, client)
+
+	cgToken, err := s.oauth.Exchange(ctx, client.ClientID, client.ClientSecrets, req.Code, s.config.OAuth.RedirectURI, req.CodeVerifier)
+	if errors.Is(err, oauth.ErrInvalidGrant) {
+		logger.Warn("Authorization code was rejected", "error", err)
+		reason = reasonInvalidGrant
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to exchange authorization code", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	resp, reason := s.verifyLoginTokens(ctx, client.ClientID, cgToken)
+	if reason != "" {
+		return
+	}
+	ctx.Set(usernameKey, resp.User.Username)
+
+	s.publishEvent(ctx, cevents.TypeUserLoggedIn, 1, cevents.UserLoggedIn{
+		Username: resp.User.Username,
+		ClientID: client.ClientID,
+	})
+
+	ctx.JSON(http.StatusOK, successResponse(resp))
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	cconfig "github.com/whatisusername/toon-tank-user-service/internal/config"
+	"github.com/whatisusername/toon-tank-user-service/internal/oauth"
+)
+
+func newTestStateCodec(t *testing.T) *oauth.StateCodec {
+	t.Helper()
+	codec, err := oauth.NewStateCodec([]byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+	return codec
+}
+
+const (
+	testCodeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	testCodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+func newTestOAuthServer(t *testing.T, authSvc *caws.MockCognitoAuthService, domain string, states *oauth.StateCodec) *Server {
+	t.Helper()
+	server := newTestServer(t, authSvc, WithOAuth(oauth.NewClient(domain), states))
+	server.config.OAuth = cconfig.OAuthConfig{
+		Domain:      domain,
+		RedirectURI: "https://example.com/callback",
+		Scopes:      []string{"openid"},
+		Providers:   []string{"Google", "Discord"},
+	}
+	return server
+}
+
+func TestServer_authorizeOAuth(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		wantStatus   int
+		wantClientID string
+		wantPlatform string
+		wantProvider string
+	}{
+		{
+			name:         "OK",
+			path:         "/v1/oauth/authorize?provider=Google&code_challenge=" + testCodeChallenge,
+			wantStatus:   http.StatusOK,
+			wantClientID: "fake_client_id",
+			wantProvider: "Google",
+		},
+		{
+			name:         "Platform",
+			path:         "/v1/platforms/pc/oauth/authorize?code_challenge=" + testCodeChallenge,
+			wantStatus:   http.StatusOK,
+			wantClientID: "fake_pc_client_id",
+			wantPlatform: "pc",
+		},
+		{
+			name:       "Unknown Provider",
+			path:       "/v1/oauth/authorize?provider=MySpace&code_challenge=" + testCodeChallenge,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Missing Challenge",
+			path:       "/v1/oauth/authorize?provider=Google",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Malformed Challenge",
+			path:       "/v1/oauth/authorize?provider=Google&code_challenge=not-a-challenge",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states := newTestStateCodec(t)
+			testServer := newTestOAuthServer(t, caws.NewMockCognitoAuthService(t), "https://auth.example.com", states)
+
+			request, err := http.NewRequest(http.MethodGet, tt.path, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			testServer.engine.ServeHTTP(recorder, request)
+			require.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Data authorizeResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+			authorizeURL, err := url.Parse(resp.Data.URL)
+			require.NoError(t, err)
+			query := authorizeURL.Query()
+			assert.Equal(t, "auth.example.com", authorizeURL.Host)
+			assert.Equal(t, tt.wantClientID, query.Get("client_id"))
+			assert.Equal(t, "https://example.com/callback", query.Get("redirect_uri"))
+			assert.Equal(t, resp.Data.State, query.Get("state"))
+			assert.Equal(t, tt.wantProvider, query.Get("identity_provider"))
+			assert.Equal(t, testCodeChallenge, query.Get("code_challenge"))
+			assert.Equal(t, "S256", query.Get("code_challenge_method"))
+
+			state, err := states.Open(resp.Data.State)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPlatform, state.Platform)
+		})
+	}
+}
+
+func TestServer_oauthCallback(t *testing.T) {
+	states := newTestStateCodec(t)
+	sealed, err := states.Seal(oauth.State{})
+	require.NoError(t, err)
+	sealedPC, err := states.Seal(oauth.State{Platform: "pc"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		body          gin.H
+		tokenStatus   int
+		tokenResponse map[string]string
+		wantClient    string
+		buildStubs    func(authSvc *caws.MockCognitoAuthService)
+		wantStatus    int
+	}{
+		{
+			name:          "OK",
+			body:          gin.H{"code": "fake_code", "codeVerifier": testCodeVerifier, "state": sealed},
+			tokenStatus:   http.StatusOK,
+			tokenResponse: map[string]string{"access_token": "fake_access_token", "id_token": "fake_id_token"},
+			wantClient:    "fake_client_id",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "google_1234567890"})
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_id_token", jwt.MapClaims{"aud": "fake_client_id", "cognito:username": "google_1234567890"})
+				authSvc.EXPECT().ParseUserInfo(mock.AnythingOfType("*jwt.Token")).
+					Return(&caws.CognitoUserInfo{Username: "google_1234567890", Email: "test@example.com"}, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:          "Token For Another Client",
+			body:          gin.H{"code": "fake_code", "codeVerifier": testCodeVerifier, "state": sealedPC},
+			tokenStatus:   http.StatusOK,
+			tokenResponse: map[string]string{"access_token": "fake_access_token", "id_token": "fake_id_token"},
+			wantClient:    "fake_pc_client_id",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id"})
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "Code Rejected",
+			body:          gin.H{"code": "fake_code", "codeVerifier": testCodeVerifier, "state": sealed},
+			tokenStatus:   http.StatusBadRequest,
+			tokenResponse: map[string]string{"error": "invalid_grant"},
+			wantClient:    "fake_client_id",
+			buildStubs:    func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus:    http.StatusBadRequest,
+		},
+		{
+			name:       "Forged State",
+			body:       gin.H{"code": "fake_code", "codeVerifier": testCodeVerifier, "state": "forged"},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Missing Verifier",
+			body:       gin.H{"code": "fake_code", "state": sealed},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Sign-In Cancelled",
+			body:       gin.H{"error": "access_denied", "state": sealed},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenEndpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.tokenStatus == 0 {
+					t.Error("token endpoint should not be called")
+					return
+				}
+				assert.Equal(t, "/oauth2/token", r.URL.Path)
+				require.NoError(t, r.ParseForm())
+				assert.Equal(t, tt.wantClient, r.PostForm.Get("client_id"))
+				assert.Equal(t, "fake_code", r.PostForm.Get("code"))
+				assert.Equal(t, testCodeVerifier, r.PostForm.Get("code_verifier"))
+				assert.Equal(t, "https://example.com/callback", r.PostForm.Get("redirect_uri"))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.tokenStatus)
+				assert.NoError(t, json.NewEncoder(w).Encode(tt.tokenResponse))
+			}))
+			t.Cleanup(tokenEndpoint.Close)
+
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			tt.buildStubs(cognitoAuthService)
+			testServer := newTestOAuthServer(t, cognitoAuthService, tokenEndpoint.URL, states)
+
+			data, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/v1/oauth/callback", bytes.NewReader(data))
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			testServer.engine.ServeHTTP(recorder, request)
+			require.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Data loginUserResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+			assert.Equal(t, "fake_access_token", resp.Data.AccessToken)
+			assert.Equal(t, "google_1234567890", resp.Data.User.Username)
+		})
+	}
+}
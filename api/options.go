@@ -0,0 +1,117 @@
+package api
+
+import (
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/captcha"
+	"github.com/whatisusername/toon-tank-user-service/internal/email"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/friends"
+	"github.com/whatisusername/toon-tank-user-service/internal/idempotency"
+	"github.com/whatisusername/toon-tank-user-service/internal/lockout"
+	"github.com/whatisusername/toon-tank-user-service/internal/metrics"
+	"github.com/whatisusername/toon-tank-user-service/internal/oauth"
+	"github.com/whatisusername/toon-tank-user-service/internal/password"
+	"github.com/whatisusername/toon-tank-user-service/internal/profile"
+	"github.com/whatisusername/toon-tank-user-service/internal/ratelimit"
+	"github.com/whatisusername/toon-tank-user-service/internal/username"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Option func(*Server)
+
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(s *Server) {
+		s.tracerProvider = tp
+	}
+}
+
+func WithMetricsRecorder(r metrics.Recorder) Option {
+	return func(s *Server) {
+		s.metrics = r
+	}
+}
+
+func WithRateLimiter(l ratelimit.Limiter) Option {
+	return func(s *Server) {
+		s.limiter = l
+	}
+}
+
+func WithLockoutTracker(t lockout.Tracker) Option {
+	return func(s *Server) {
+		s.lockout = t
+	}
+}
+
+func WithCaptchaVerifier(v captcha.CaptchaVerifier) Option {
+	return func(s *Server) {
+		s.captcha = v
+	}
+}
+
+func WithPasswordValidator(v *password.Validator) Option {
+	return func(s *Server) {
+		s.passwords = v
+	}
+}
+
+func WithEmailValidator(v *email.Validator) Option {
+	return func(s *Server) {
+		s.emails = v
+	}
+}
+
+func WithUsernameValidator(v *username.Validator) Option {
+	return func(s *Server) {
+		s.usernames = v
+	}
+}
+
+func WithIdempotencyStore(store idempotency.Store) Option {
+	return func(s *Server) {
+		s.idempotency = store
+	}
+}
+
+func WithEventPublisher(p cevents.EventPublisher) Option {
+	return func(s *Server) {
+		s.events = p
+	}
+}
+
+func WithProfileRepository(r profile.ProfileRepository) Option {
+	return func(s *Server) {
+		s.profiles = r
+	}
+}
+
+func WithFriendsRepository(r friends.Repository) Option {
+	return func(s *Server) {
+		s.friends = friends.NewService(r)
+	}
+}
+
+// WithCognitoUserAdmin enables the routes that read and change the caller's
+// user through the admin APIs, such as identity linking.
+func WithCognitoUserAdmin(admin caws.CognitoUserAdmin) Option {
+	return func(s *Server) {
+		s.userAdmin = admin
+	}
+}
+
+// WithGuestAccounts enables guest accounts, which also need
+// WithCognitoUserAdmin.
+func WithGuestAccounts() Option {
+	return func(s *Server) {
+		s.guests = true
+	}
+}
+
+// WithOAuth enables sign-in through the hosted UI, configured by the oauth
+// section of the config.
+func WithOAuth(client *oauth.Client, states *oauth.StateCodec) Option {
+	return func(s *Server) {
+		s.oauth = client
+		s.oauthStates = states
+	}
+}
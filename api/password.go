@@ -0,0 +1,181 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+	"github.com/whatisusername/toon-tank-user-service/internal/validation"
+)
+
+const (
+	codeInvalidPasswordException = "InvalidPasswordException"
+	codeUserNotFoundException    = "UserNotFoundException"
+	codeCodeMismatchException    = "CodeMismatchException"
+	codeExpiredCodeException     = "ExpiredCodeException"
+	codeNotAuthorizedException   = "NotAuthorizedException"
+
+	codePasswordResetRequiredException = "PasswordResetRequiredException"
+
+	codePasswordRejected = "PasswordRejected"
+
+	reasonPasswordChangeRequired = "PasswordChangeRequired"
+)
+
+var (
+	errInvalidResetCode       = errors.New("invalid or expired reset code")
+	errPasswordChangeRequired = errors.New("a new password must be set for this account before signing in")
+	errPasswordResetRequired  = errors.New("the password must be reset before signing in; request a reset code to choose a new one")
+)
+
+// validatePassword runs the password policy and breach check, responding with
+// the violations if there are any.
+func (s *Server) validatePassword(ctx *gin.Context, field, password string) bool {
+	violations, err := s.passwords.Validate(ctx, field, password)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to validate password", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+	if len(violations) > 0 {
+		ctx.JSON(http.StatusBadRequest, validationErrorResponse(violations))
+		return false
+	}
+	return true
+}
+
+// cognitoPasswordViolations stands in for Cognito's InvalidPasswordException,
+// whose message is not meant for players. It only happens when the user pool
+// is stricter than the local policy.
+func cognitoPasswordViolations(field string) []validation.Violation {
+	return []validation.Violation{{
+		Field:   field,
+		Code:    codePasswordRejected,
+		Message: "does not meet the password requirements",
+	}}
+}
+
+type changePasswordRequest struct {
+	PreviousPassword string `json:"previousPassword" binding:"required"`
+	ProposedPassword string `json:"proposedPassword" binding:"required"`
+}
+
+func (s *Server) changePassword(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	start := time.Now()
+	reason := reasonInternalError
+	defer func() { s.recordOutcome(ctx, metricPasswordChange, start, reason) }()
+
+	var req changePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", "error", err)
+		reason = reasonInvalidRequest
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !s.validatePassword(ctx, "proposedPassword", req.ProposedPassword) {
+		reason = reasonInvalidPassword
+		return
+	}
+
+	if err := s.cognitoAuthService.ChangePassword(ctx, ctx.GetString(accessTokenKey), req.PreviousPassword, req.ProposedPassword); err != nil {
+		logger.Error("Failed to change password", "error", err)
+		reason = errorReason(err)
+		switch reason {
+		case codeInvalidPasswordException:
+			ctx.JSON(http.StatusBadRequest, validationErrorResponse(cognitoPasswordViolations("proposedPassword")))
+		case codeNotAuthorizedException:
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	reason = ""
+	ctx.Status(http.StatusNoContent)
+}
+
+type forgotPasswordRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+}
+
+// forgotPassword asks Cognito to send a reset code. Unknown usernames get the
+// same response as known ones, so the endpoint can't be used to probe for
+// accounts.
+func (s *Server) forgotPassword(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	var req forgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	ctx.Set(usernameKey, req.Username)
+
+	client := appClient(ctx)
+	if err := s.cognitoAuthService.ForgotPassword(ctx, client.ClientID, client.ClientSecrets, req.Username); err != nil {
+		if errorReason(err) != codeUserNotFoundException {
+			logger.Error("Failed to start password reset", "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		logger.Warn("Password reset requested for unknown user")
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
+
+type resetPasswordRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+	Code     string `json:"code" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+func (s *Server) resetPassword(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	start := time.Now()
+	reason := reasonInternalError
+	defer func() { s.recordOutcome(ctx, metricPasswordReset, start, reason) }()
+
+	var req resetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", "error", err)
+		reason = reasonInvalidRequest
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	ctx.Set(usernameKey, req.Username)
+
+	if !s.validatePassword(ctx, "password", req.Password) {
+		reason = reasonInvalidPassword
+		return
+	}
+
+	client := appClient(ctx)
+	if err := s.cognitoAuthService.ConfirmForgotPassword(ctx, client.ClientID, client.ClientSecrets, req.Username, req.Code, req.Password); err != nil {
+		logger.Error("Failed to reset password", "error", err)
+		reason = errorReason(err)
+		switch reason {
+		case codeInvalidPasswordException:
+			ctx.JSON(http.StatusBadRequest, validationErrorResponse(cognitoPasswordViolations("password")))
+		case codeCodeMismatchException, codeExpiredCodeException, codeUserNotFoundException:
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidResetCode))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	// Proving ownership of the account is as good as a successful login.
+	s.resetLockout(ctx, normalizeUsername(req.Username))
+
+	reason = ""
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,240 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/lockout"
+	"github.com/whatisusername/toon-tank-user-service/internal/password"
+)
+
+func TestServer_changePassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		body       string
+		buildStubs func(authSvc *caws.MockCognitoAuthService)
+		wantStatus int
+		wantCodes  []string
+	}{
+		{
+			name:  "OK",
+			token: "fake_access_token",
+			body:  `{"previousPassword":"test123456A","proposedPassword":"test123456B"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test"})
+				authSvc.EXPECT().
+					ChangePassword(mock.Anything, "fake_access_token", "test123456A", "test123456B").
+					Return(nil).Once()
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "Missing Token",
+			body:       `{"previousPassword":"test123456A","proposedPassword":"test123456B"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:  "Weak Password",
+			token: "fake_access_token",
+			body:  `{"previousPassword":"test123456A","proposedPassword":"TEST123456"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test"})
+			},
+			wantStatus: http.StatusBadRequest,
+			wantCodes:  []string{password.CodeMissingLower},
+		},
+		{
+			name:  "Wrong Previous Password",
+			token: "fake_access_token",
+			body:  `{"previousPassword":"wrong","proposedPassword":"test123456B"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test"})
+				authSvc.EXPECT().
+					ChangePassword(mock.Anything, "fake_access_token", "wrong", "test123456B").
+					Return(&smithy.GenericAPIError{Code: "NotAuthorizedException"}).Once()
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			tt.buildStubs(cognitoAuthService)
+
+			testServer := newTestServer(t, cognitoAuthService)
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/me/password", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			if tt.token != "" {
+				request.Header.Set(authorizationHeader, "Bearer "+tt.token)
+			}
+
+			recorder := httptest.NewRecorder()
+			testServer.engine.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			assertViolationCodes(t, recorder, tt.wantCodes)
+		})
+	}
+}
+
+func TestServer_forgotPassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{
+			name:       "OK",
+			err:        nil,
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "Unknown User",
+			err:        &smithy.GenericAPIError{Code: "UserNotFoundException"},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "Internal Server Error",
+			err:        errors.New("server is busy"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			cognitoAuthService.EXPECT().
+				ForgotPassword(mock.Anything, "fake_client_id", "fake_client_secret", "test").
+				Return(tt.err).Once()
+
+			testServer := newTestServer(t, cognitoAuthService)
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/users/password/forgot", strings.NewReader(`{"username":"test"}`))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			testServer.engine.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+		})
+	}
+}
+
+func TestServer_resetPassword(t *testing.T) {
+	// SHA-1("Password123") = B2E98AD6F6EB8508DD6A14CFA704BAD7F05F6FB1
+	breachDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(breachDir, "B2E98.txt"), []byte("AD6F6EB8508DD6A14CFA704BAD7F05F6FB1:128123\n"), 0o600))
+
+	tests := []struct {
+		name       string
+		body       string
+		buildStubs func(authSvc *caws.MockCognitoAuthService)
+		wantStatus int
+		wantCodes  []string
+		wantLocked bool
+	}{
+		{
+			name: "OK",
+			body: `{"username":"Test","code":"123456","password":"test123456B"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					ConfirmForgotPassword(mock.Anything, "fake_client_id", "fake_client_secret", "Test", "123456", "test123456B").
+					Return(nil).Once()
+			},
+			wantStatus: http.StatusNoContent,
+			wantLocked: false,
+		},
+		{
+			name:       "Breached Password",
+			body:       `{"username":"test","code":"123456","password":"Password123"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus: http.StatusBadRequest,
+			wantCodes:  []string{password.CodeBreached},
+			wantLocked: true,
+		},
+		{
+			name: "Code Mismatch",
+			body: `{"username":"test","code":"000000","password":"test123456B"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					ConfirmForgotPassword(mock.Anything, "fake_client_id", "fake_client_secret", "test", "000000", "test123456B").
+					Return(&smithy.GenericAPIError{Code: "CodeMismatchException"}).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+			wantLocked: true,
+		},
+		{
+			name: "Password Rejected By Cognito",
+			body: `{"username":"test","code":"123456","password":"test123456B"}`,
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					ConfirmForgotPassword(mock.Anything, "fake_client_id", "fake_client_secret", "test", "123456", "test123456B").
+					Return(&smithy.GenericAPIError{Code: "InvalidPasswordException"}).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+			wantCodes:  []string{codePasswordRejected},
+			wantLocked: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			tt.buildStubs(cognitoAuthService)
+
+			tracker := lockout.NewMemoryTracker(testLockoutPolicy)
+			for i := 0; i < testLockoutPolicy.Threshold; i++ {
+				_, err := tracker.RecordFailure(ctx, "test")
+				require.NoError(t, err)
+			}
+
+			testServer := newTestServer(t, cognitoAuthService,
+				WithLockoutTracker(tracker),
+				WithPasswordValidator(password.NewValidator(password.DefaultPolicy(), password.NewFileBreachList(breachDir))),
+			)
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/users/password/reset", strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			testServer.engine.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			assertViolationCodes(t, recorder, tt.wantCodes)
+
+			status, err := tracker.Status(ctx, "test")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLocked, status.Failures > 0)
+		})
+	}
+}
+
+func assertViolationCodes(t *testing.T, recorder *httptest.ResponseRecorder, wantCodes []string) {
+	t.Helper()
+	if recorder.Body.Len() == 0 {
+		assert.Empty(t, wantCodes)
+		return
+	}
+
+	var resp response
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+	var codes []string
+	for _, v := range resp.Errors {
+		codes = append(codes, v.Code)
+	}
+	assert.Equal(t, wantCodes, codes)
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+	"github.com/whatisusername/toon-tank-user-service/internal/profile"
+	"github.com/whatisusername/toon-tank-user-service/internal/ratelimit"
+)
+
+const (
+	playerActionParam = "action"
+	actionBatchGet    = ":batchGet"
+
+	// playerCacheMaxAge is how long API Gateway, a CDN or the client may
+	// reuse a lookup. Profile edits show up in lobbies after at most this.
+	playerCacheMaxAge = time.Minute
+)
+
+var (
+	playerRateLimits = rateLimitRules{
+		perIP:       ratelimit.Rule{Limit: 120, Interval: time.Minute},
+		perUsername: ratelimit.Rule{Limit: 120, Interval: time.Minute},
+	}
+
+	errPlayerNotFound = errors.New("player not found")
+)
+
+// publicPlayer is everything other players may see about a player. It is
+// deliberately separate from profileResponse so that new profile fields stay
+// private unless added here.
+type publicPlayer struct {
+	PlayerID    string `json:"playerId"`
+	DisplayName string `json:"displayName"`
+	Avatar      string `json:"avatar"`
+	TankSkin    string `json:"tankSkin"`
+	Level       int    `json:"level"`
+}
+
+type playerIDRequest struct {
+	ID string `uri:"id" binding:"required,max=64"`
+}
+
+type batchGetPlayersRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,max=100,dive,required,max=64"`
+}
+
+// batchGetPlayersResponse lists the players found in the order they were
+// requested, and the IDs that matched nobody.
+type batchGetPlayersResponse struct {
+	Players  []publicPlayer `json:"players"`
+	NotFound []string       `json:"notFound"`
+}
+
+func (s *Server) getPlayer(ctx *gin.Context) {
+	var req playerIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, err := s.profiles.Get(ctx, req.ID)
+	if errors.Is(err, profile.ErrNotFound) {
+		ctx.JSON(http.StatusNotFound, errorResponse(errPlayerNotFound))
+		return
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get player", "playerId", req.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.cacheableJSON(ctx, successResponse(newPublicPlayer(p)))
+}
+
+// playerAction dispatches custom methods such as POST /players:batchGet. gin
+// cannot register a static path with a colon in it, so the colon and the
+// method name arrive as a path parameter.
+func (s *Server) playerAction(ctx *gin.Context) {
+	switch ctx.Param(playerActionParam) {
+	case actionBatchGet:
+		s.batchGetPlayers(ctx)
+	default:
+		ctx.JSON(http.StatusNotFound, errorResponse(errors.New("unknown method")))
+	}
+}
+
+func (s *Server) batchGetPlayers(ctx *gin.Context) {
+	var req batchGetPlayersRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	profiles, err := s.profiles.BatchGet(ctx, req.IDs)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get players", "count", len(req.IDs), "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	found := make(map[string]*profile.Profile, len(profiles))
+	for i := range profiles {
+		found[profiles[i].PlayerID] = &profiles[i]
+	}
+
+	resp := batchGetPlayersResponse{Players: []publicPlayer{}, NotFound: []string{}}
+	seen := make(map[string]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if p, ok := found[id]; ok {
+			resp.Players = append(resp.Players, newPublicPlayer(p))
+		} else {
+			resp.NotFound = append(resp.NotFound, id)
+		}
+	}
+
+	s.cacheableJSON(ctx, successResponse(resp))
+}
+
+// cacheableJSON responds with body, a strong ETag over it and a public
+// max-age, and answers a matching If-None-Match with 304.
+func (s *Server) cacheableJSON(ctx *gin.Context, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	etag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(playerCacheMaxAge.Seconds())))
+
+	if ctx.GetHeader("If-None-Match") == etag {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	ctx.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+func newPublicPlayer(p *profile.Profile) publicPlayer {
+	return publicPlayer{
+		PlayerID:    p.PlayerID,
+		DisplayName: p.DisplayName,
+		Avatar:      p.Avatar,
+		TankSkin:    p.TankSkin,
+		Level:       p.Level,
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/profile"
+)
+
+func TestServer_getPlayer(t *testing.T) {
+	profiles := newTestProfiles(t, "player-1")
+	testServer := newTestServer(t, caws.NewMockCognitoAuthService(t), WithProfileRepository(profiles))
+
+	request, err := http.NewRequest(http.MethodGet, "/v1/players/player-1", nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	testServer.engine.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "public, max-age=60", recorder.Header().Get("Cache-Control"))
+	etag := recorder.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	var resp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, map[string]interface{}{
+		"playerId":    "player-1",
+		"displayName": "Name player-1",
+		"avatar":      "avatar.png",
+		"tankSkin":    "camo",
+		"level":       float64(0),
+	}, resp.Data, "only public fields are exposed")
+
+	t.Run("Not Modified", func(t *testing.T) {
+		request, err := http.NewRequest(http.MethodGet, "/v1/players/player-1", nil)
+		require.NoError(t, err)
+		request.Header.Set("If-None-Match", etag)
+		recorder := httptest.NewRecorder()
+		testServer.engine.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusNotModified, recorder.Code)
+		assert.Empty(t, recorder.Body.Bytes())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		request, err := http.NewRequest(http.MethodGet, "/v1/players/missing", nil)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		testServer.engine.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestServer_batchGetPlayers(t *testing.T) {
+	tooMany := make([]string, profile.MaxBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("player-%d", i)
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		body         gin.H
+		wantStatus   int
+		wantPlayers  []string
+		wantNotFound []string
+	}{
+		{
+			name:         "OK",
+			path:         "/v1/players:batchGet",
+			body:         gin.H{"ids": []string{"player-2", "missing", "player-1", "player-2"}},
+			wantStatus:   http.StatusOK,
+			wantPlayers:  []string{"player-2", "player-1"},
+			wantNotFound: []string{"missing"},
+		},
+		{
+			name:       "Empty",
+			path:       "/v1/players:batchGet",
+			body:       gin.H{"ids": []string{}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Too Many",
+			path:       "/v1/players:batchGet",
+			body:       gin.H{"ids": tooMany},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Unknown Method",
+			path:       "/v1/players:delete",
+			body:       gin.H{"ids": []string{"player-1"}},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profiles := newTestProfiles(t, "player-1", "player-2")
+			testServer := newTestServer(t, caws.NewMockCognitoAuthService(t), WithProfileRepository(profiles))
+
+			var body bytes.Buffer
+			require.NoError(t, json.NewEncoder(&body).Encode(tt.body))
+			request, err := http.NewRequest(http.MethodPost, tt.path, &body)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			testServer.engine.ServeHTTP(recorder, request)
+
+			require.Equal(t, tt.wantStatus, recorder.Code, recorder.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Data batchGetPlayersResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+			ids := make([]string, len(resp.Data.Players))
+			for i, p := range resp.Data.Players {
+				ids[i] = p.PlayerID
+			}
+			assert.Equal(t, tt.wantPlayers, ids)
+			assert.Equal(t, tt.wantNotFound, resp.Data.NotFound)
+			assert.NotEmpty(t, recorder.Header().Get("ETag"))
+		})
+	}
+}
+
+func newTestProfiles(t *testing.T, playerIDs ...string) *profile.MemoryRepository {
+	t.Helper()
+
+	profiles := profile.NewMemoryRepository()
+	for _, id := range playerIDs {
+		_, err := profiles.Put(context.Background(), profile.Profile{
+			PlayerID: id,
+			Avatar:   "avatar.png",
+			TankSkin: "camo",
+			Region:   "eu",
+		})
+		require.NoError(t, err)
+		_, err = profiles.Rename(context.Background(), id, "Name "+id, time.Hour)
+		require.NoError(t, err)
+	}
+	return profiles
+}
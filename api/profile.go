@@ -0,0 +1,116 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+	"github.com/whatisusername/toon-tank-user-service/internal/profile"
+)
+
+var errMissingPlayerID = errors.New("token has no subject")
+
+type profileResponse struct {
+	PlayerID    string    `json:"playerId"`
+	DisplayName string    `json:"displayName"`
+	Avatar      string    `json:"avatar"`
+	TankSkin    string    `json:"tankSkin"`
+	Region      string    `json:"region"`
+	Level       int       `json:"level"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	Version     int64     `json:"version"`
+}
+
+// putProfileRequest replaces the fields a player edits freely. Version must be
+// the version the client last read, or 0 to create the profile. The display
+// name has its own endpoint because it must stay unique.
+type putProfileRequest struct {
+	Avatar   string `json:"avatar" binding:"max=256"`
+	TankSkin string `json:"tankSkin" binding:"max=64"`
+	Region   string `json:"region" binding:"max=16"`
+	Version  *int64 `json:"version" binding:"required,min=0"`
+}
+
+func (s *Server) getProfile(ctx *gin.Context) {
+	playerID, ok := s.playerID(ctx)
+	if !ok {
+		return
+	}
+
+	p, err := s.profiles.Get(ctx, playerID)
+	if errors.Is(err, profile.ErrNotFound) {
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get profile", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(newProfileResponse(p)))
+}
+
+func (s *Server) putProfile(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	playerID, ok := s.playerID(ctx)
+	if !ok {
+		return
+	}
+
+	var req putProfileRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, err := s.profiles.Put(ctx, profile.Profile{
+		PlayerID: playerID,
+		Avatar:   req.Avatar,
+		TankSkin: req.TankSkin,
+		Region:   req.Region,
+		Version:  *req.Version,
+	})
+	if errors.Is(err, profile.ErrVersionConflict) {
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to put profile", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	logger.Info("Updated profile", "version", p.Version)
+	ctx.JSON(http.StatusOK, successResponse(newProfileResponse(p)))
+}
+
+// playerID returns the Cognito sub of the authenticated user, which is the
+// player ID everything game-related is keyed by.
+func (s *Server) playerID(ctx *gin.Context) (string, bool) {
+	sub, _ := tokenClaims(ctx)["sub"].(string)
+	if sub == "" {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errMissingPlayerID))
+		return "", false
+	}
+	return sub, true
+}
+
+func newProfileResponse(p *profile.Profile) profileResponse {
+	return profileResponse{
+		PlayerID:    p.PlayerID,
+		DisplayName: p.DisplayName,
+		Avatar:      p.Avatar,
+		TankSkin:    p.TankSkin,
+		Region:      p.Region,
+		Level:       p.Level,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+		Version:     p.Version,
+	}
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/profile"
+)
+
+func TestServer_profile(t *testing.T) {
+	claims := jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test", "sub": "fake_sub"}
+
+	tests := []struct {
+		name       string
+		existing   *profile.Profile
+		method     string
+		body       gin.H
+		claims     jwt.MapClaims
+		wantStatus int
+		wantData   gin.H
+	}{
+		{
+			name:       "Get Not Found",
+			method:     http.MethodGet,
+			claims:     claims,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Get",
+			existing:   &profile.Profile{PlayerID: "fake_sub", Region: "eu"},
+			method:     http.MethodGet,
+			claims:     claims,
+			wantStatus: http.StatusOK,
+			wantData:   gin.H{"playerId": "fake_sub", "region": "eu", "version": float64(1)},
+		},
+		{
+			name:       "Create",
+			method:     http.MethodPut,
+			body:       gin.H{"tankSkin": "camo", "version": 0},
+			claims:     claims,
+			wantStatus: http.StatusOK,
+			wantData:   gin.H{"playerId": "fake_sub", "tankSkin": "camo", "version": float64(1)},
+		},
+		{
+			name:       "Update",
+			existing:   &profile.Profile{PlayerID: "fake_sub"},
+			method:     http.MethodPut,
+			body:       gin.H{"region": "na", "version": 1},
+			claims:     claims,
+			wantStatus: http.StatusOK,
+			wantData:   gin.H{"region": "na", "version": float64(2)},
+		},
+		{
+			name:       "Stale Version",
+			existing:   &profile.Profile{PlayerID: "fake_sub"},
+			method:     http.MethodPut,
+			body:       gin.H{"avatar": "other.png", "version": 0},
+			claims:     claims,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "Missing Version",
+			method:     http.MethodPut,
+			body:       gin.H{"avatar": "avatar.png"},
+			claims:     claims,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Field Too Long",
+			method:     http.MethodPut,
+			body:       gin.H{"region": "a-region-name-that-is-far-too-long", "version": 0},
+			claims:     claims,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Missing Subject",
+			method:     http.MethodGet,
+			claims:     jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test"},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			mockTokenValidation(cognitoAuthService, "us-east-1_example", "fake_access_token", tt.claims)
+
+			profiles := profile.NewMemoryRepository()
+			if tt.existing != nil {
+				_, err := profiles.Put(context.Background(), *tt.existing)
+				require.NoError(t, err)
+			}
+
+			var body bytes.Buffer
+			if tt.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tt.body))
+			}
+			request, err := http.NewRequest(tt.method, "/v1/me/profile", &body)
+			require.NoError(t, err)
+			request.Header.Set(authorizationHeader, "Bearer fake_access_token")
+
+			testServer := newTestServer(t, cognitoAuthService, WithProfileRepository(profiles))
+			recorder := httptest.NewRecorder()
+
+			testServer.engine.ServeHTTP(recorder, request)
+			require.Equal(t, tt.wantStatus, recorder.Code, recorder.Body.String())
+
+			if tt.wantData != nil {
+				var resp struct {
+					Data map[string]interface{} `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				for k, v := range tt.wantData {
+					assert.Equal(t, v, resp.Data[k], k)
+				}
+			}
+		})
+	}
+}
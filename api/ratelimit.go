@@ -0,0 +1,121 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/awslabs/aws-lambda-go-api-proxy/core"
+	"github.com/gin-gonic/gin"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+	"github.com/whatisusername/toon-tank-user-service/internal/ratelimit"
+)
+
+type rateLimitRules struct {
+	perIP       ratelimit.Rule
+	perUsername ratelimit.Rule
+}
+
+var (
+	loginRateLimits = rateLimitRules{
+		perIP:       ratelimit.Rule{Limit: 20, Interval: time.Minute},
+		perUsername: ratelimit.Rule{Limit: 5, Interval: time.Minute},
+	}
+	signUpRateLimits = rateLimitRules{
+		perIP:       ratelimit.Rule{Limit: 10, Interval: time.Hour},
+		perUsername: ratelimit.Rule{Limit: 5, Interval: time.Hour},
+	}
+	availabilityRateLimits = rateLimitRules{
+		perIP:       ratelimit.Rule{Limit: 60, Interval: time.Minute},
+		perUsername: ratelimit.Rule{Limit: 60, Interval: time.Minute},
+	}
+	passwordResetRateLimits = rateLimitRules{
+		perIP:       ratelimit.Rule{Limit: 10, Interval: time.Hour},
+		perUsername: ratelimit.Rule{Limit: 5, Interval: time.Hour},
+	}
+)
+
+type rateLimitKey struct {
+	key  string
+	rule ratelimit.Rule
+}
+
+var errTooManyRequests = errors.New("too many requests, please try again later")
+
+// rateLimit throttles the route per source IP and per normalised username
+// before the request reaches Cognito. Limiter failures let the request
+// through, since Cognito still applies its own quotas.
+func (s *Server) rateLimit(scope string, rules rateLimitRules) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if s.limiter == nil {
+			ctx.Next()
+			return
+		}
+
+		keys := []rateLimitKey{
+			{key: scope + ":ip:" + sourceIP(ctx), rule: rules.perIP},
+		}
+		if username := peekUsername(ctx); username != "" {
+			keys = append(keys, rateLimitKey{key: scope + ":user:" + username, rule: rules.perUsername})
+		}
+
+		for _, k := range keys {
+			decision, err := s.limiter.Allow(ctx, k.key, k.rule)
+			if err != nil {
+				logging.FromContext(ctx).Error("Failed to check rate limit", "key", k.key, "error", err)
+				continue
+			}
+
+			if !decision.Allowed {
+				logging.FromContext(ctx).Warn("Rate limit exceeded", "key", k.key, "retryAfter", decision.RetryAfter)
+				ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds()))))
+				ctx.AbortWithStatusJSON(http.StatusTooManyRequests, errorResponse(errTooManyRequests))
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+// sourceIP prefers the caller address seen by API Gateway. Outside API Gateway
+// it falls back to the connection's address; the engine trusts no proxies, so
+// headers the client controls are never used.
+func sourceIP(ctx *gin.Context) string {
+	if gwCtx, ok := core.GetAPIGatewayContextFromContext(ctx.Request.Context()); ok && gwCtx.Identity.SourceIP != "" {
+		return gwCtx.Identity.SourceIP
+	}
+	return ctx.ClientIP()
+}
+
+// peekUsername reads the username from a JSON body and puts the body back for
+// the handler. Requests without a body may carry it in the query string.
+func peekUsername(ctx *gin.Context) string {
+	if ctx.Request.Body == nil || ctx.Request.Body == http.NoBody {
+		return normalizeUsername(ctx.Query("username"))
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var req struct {
+		Username string `json:"username"`
+	}
+	if err = json.Unmarshal(body, &req); err != nil {
+		return ""
+	}
+	return normalizeUsername(req.Username)
+}
+
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/ratelimit"
+)
+
+func TestServer_rateLimit(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		requests       int
+		body           func(i int) string
+		remoteAddr     func(i int) string
+		forwardedFor   func(i int) string
+		buildStubs     func(authSvc *caws.MockCognitoAuthService)
+		wantRetryAfter string
+	}{
+		{
+			name:     "Login Per Username",
+			url:      "/v1/users/login",
+			requests: loginRateLimits.perUsername.Limit,
+			body: func(i int) string {
+				// Changing the case must not yield a fresh budget.
+				usernames := []string{"test", "Test", "TEST", "tEsT", "TeSt"}
+				return fmt.Sprintf(`{"username":%q,"password":"wrong"}`, usernames[i%len(usernames)])
+			},
+			remoteAddr: func(i int) string { return fmt.Sprintf("10.0.0.%d:1234", i) },
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", mock.AnythingOfType("string"), "wrong").
+					Return(nil, &smithy.GenericAPIError{Code: "NotAuthorizedException"}).
+					Times(loginRateLimits.perUsername.Limit)
+			},
+			wantRetryAfter: "12",
+		},
+		{
+			name:     "Sign Up Per IP",
+			url:      "/v1/users",
+			requests: signUpRateLimits.perIP.Limit,
+			body: func(i int) string {
+				return fmt.Sprintf(`{"username":"test%d","email":"test%d@example.com","password":"test123456A"}`, i, i)
+			},
+			remoteAddr: func(i int) string { return "10.0.0.1:1234" },
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", mock.AnythingOfType("string"), "test123456A", mock.AnythingOfType("string")).
+					Return(nil).
+					Times(signUpRateLimits.perIP.Limit)
+			},
+			wantRetryAfter: "360",
+		},
+		{
+			// A client picking its own address must not get a fresh budget.
+			name:     "Sign Up Per IP Ignores Forwarded For",
+			url:      "/v1/users",
+			requests: signUpRateLimits.perIP.Limit,
+			body: func(i int) string {
+				return fmt.Sprintf(`{"username":"test%d","email":"test%d@example.com","password":"test123456A"}`, i, i)
+			},
+			remoteAddr:   func(i int) string { return "10.0.0.1:1234" },
+			forwardedFor: func(i int) string { return fmt.Sprintf("192.0.2.%d", i) },
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", mock.AnythingOfType("string"), "test123456A", mock.AnythingOfType("string")).
+					Return(nil).
+					Times(signUpRateLimits.perIP.Limit)
+			},
+			wantRetryAfter: "360",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			tt.buildStubs(cognitoAuthService)
+
+			testServer := newTestServer(t, cognitoAuthService, WithRateLimiter(ratelimit.NewMemoryLimiter()))
+
+			for i := 0; i <= tt.requests; i++ {
+				request, err := http.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body(i)))
+				require.NoError(t, err)
+				request.RemoteAddr = tt.remoteAddr(i)
+				if tt.forwardedFor != nil {
+					request.Header.Set("X-Forwarded-For", tt.forwardedFor(i))
+				}
+
+				recorder := httptest.NewRecorder()
+				testServer.engine.ServeHTTP(recorder, request)
+
+				if i < tt.requests {
+					assert.NotEqual(t, http.StatusTooManyRequests, recorder.Code, "request %d was limited too early", i)
+					continue
+				}
+				assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				assert.Equal(t, tt.wantRetryAfter, recorder.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestServer_rateLimit_gatewaySourceIP(t *testing.T) {
+	cognitoAuthService := caws.NewMockCognitoAuthService(t)
+	cognitoAuthService.EXPECT().
+		Login(mock.Anything, "fake_client_id", "fake_client_secret", mock.AnythingOfType("string"), "wrong").
+		Return(nil, &smithy.GenericAPIError{Code: "NotAuthorizedException"}).
+		Times(loginRateLimits.perIP.Limit)
+
+	testServer := newTestServer(t, cognitoAuthService, WithRateLimiter(ratelimit.NewMemoryLimiter()))
+
+	for i := 0; i <= loginRateLimits.perIP.Limit; i++ {
+		resp, err := testServer.HandleRequest(context.Background(), events.APIGatewayProxyRequest{
+			Path:       "/v1/users/login",
+			HTTPMethod: http.MethodPost,
+			Headers:    map[string]string{"X-Forwarded-For": fmt.Sprintf("10.0.0.%d", i)},
+			Body:       fmt.Sprintf(`{"username":"test%d","password":"wrong"}`, i),
+			RequestContext: events.APIGatewayProxyRequestContext{
+				Identity: events.APIGatewayRequestIdentity{SourceIP: "203.0.113.7"},
+			},
+		})
+		require.NoError(t, err)
+
+		if i < loginRateLimits.perIP.Limit {
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "request %d was limited too early", i)
+			continue
+		}
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	}
+}
@@ -1,9 +1,12 @@
 package api
 
+import "github.com/whatisusername/toon-tank-user-service/internal/validation"
+
 type response struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+	Success bool                   `json:"success"`
+	Message string                 `json:"message"`
+	Data    interface{}            `json:"data,omitempty"`
+	Errors  []validation.Violation `json:"errors,omitempty"`
 }
 
 func successResponse(data interface{}) response {
@@ -20,3 +23,11 @@ func errorResponse(err error) response {
 		Message: err.Error(),
 	}
 }
+
+func validationErrorResponse(violations []validation.Violation) response {
+	return response{
+		Success: false,
+		Message: "Validation failed",
+		Errors:  violations,
+	}
+}
@@ -4,12 +4,30 @@ import (
 	"context"
 	"errors"
 	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/captcha"
 	cconfig "github.com/whatisusername/toon-tank-user-service/internal/config"
+	"github.com/whatisusername/toon-tank-user-service/internal/email"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/friends"
+	"github.com/whatisusername/toon-tank-user-service/internal/guest"
+	"github.com/whatisusername/toon-tank-user-service/internal/idempotency"
+	"github.com/whatisusername/toon-tank-user-service/internal/lockout"
+	"github.com/whatisusername/toon-tank-user-service/internal/metrics"
+	"github.com/whatisusername/toon-tank-user-service/internal/oauth"
+	"github.com/whatisusername/toon-tank-user-service/internal/password"
+	"github.com/whatisusername/toon-tank-user-service/internal/profile"
+	"github.com/whatisusername/toon-tank-user-service/internal/ratelimit"
+	"github.com/whatisusername/toon-tank-user-service/internal/telemetry"
+	"github.com/whatisusername/toon-tank-user-service/internal/username"
 	"log/slog"
+	"sync/atomic"
 
 	"github.com/aws/aws-lambda-go/events"
 	ginadapter "github.com/awslabs/aws-lambda-go-api-proxy/gin"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Server struct {
@@ -17,9 +35,27 @@ type Server struct {
 	ginLambda          *ginadapter.GinLambda
 	config             *cconfig.Config
 	cognitoAuthService caws.CognitoAuthService
+	userAdmin          caws.CognitoUserAdmin
+	guests             bool
+	oauth              *oauth.Client
+	oauthStates        *oauth.StateCodec
+	tracerProvider     trace.TracerProvider
+	metrics            metrics.Recorder
+	limiter            ratelimit.Limiter
+	lockout            lockout.Tracker
+	captcha            captcha.CaptchaVerifier
+	passwords          *password.Validator
+	emails             *email.Validator
+	usernames          *username.Validator
+	availability       *availabilityCache
+	idempotency        idempotency.Store
+	events             cevents.EventPublisher
+	profiles           profile.ProfileRepository
+	friends            *friends.Service
+	coldStart          atomic.Bool
 }
 
-func NewServer(cfg *cconfig.Config, cognitoAuthService caws.CognitoAuthService) (*Server, error) {
+func NewServer(cfg *cconfig.Config, cognitoAuthService caws.CognitoAuthService, opts ...Option) (*Server, error) {
 	if cfg == nil {
 		return nil, errors.New("config is required")
 	}
@@ -27,6 +63,29 @@ func NewServer(cfg *cconfig.Config, cognitoAuthService caws.CognitoAuthService)
 	s := &Server{
 		config:             cfg,
 		cognitoAuthService: cognitoAuthService,
+		tracerProvider:     otel.GetTracerProvider(),
+		metrics:            metrics.NoopRecorder{},
+		passwords:          password.NewValidator(password.DefaultPolicy(), nil),
+		emails:             email.NewValidator(false, nil),
+		usernames:          username.NewValidator(username.DefaultPolicy(), username.DefaultReserved, nil),
+		availability:       newAvailabilityCache(availabilityCacheTTL),
+		events:             cevents.NoopPublisher{},
+	}
+	s.coldStart.Store(true)
+
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.guests && s.userAdmin == nil {
+		return nil, errors.New("guest accounts need a Cognito user admin")
+	}
+	// Players' data has no safe default: keeping it in memory would lose it
+	// without a word.
+	if s.profiles == nil {
+		return nil, errors.New("a profile repository is required")
+	}
+	if s.friends == nil {
+		return nil, errors.New("a friends repository is required")
 	}
 
 	s.registerRoutes()
@@ -34,17 +93,85 @@ func NewServer(cfg *cconfig.Config, cognitoAuthService caws.CognitoAuthService)
 }
 
 func (s *Server) registerRoutes() {
-	s.engine = gin.Default()
+	s.engine = gin.New()
+	s.engine.ContextWithFallback = true
+	// The function is only reached through API Gateway, which reports the
+	// caller itself, so no proxy gets to set the client IP through
+	// X-Forwarded-For. A nil list can't be invalid.
+	_ = s.engine.SetTrustedProxies(nil)
+	s.engine.Use(
+		otelgin.Middleware(telemetry.ServiceName, otelgin.WithTracerProvider(s.tracerProvider)),
+		s.markColdStart,
+		s.requestLogger,
+		gin.Recovery(),
+	)
 
 	v1 := s.engine.Group("/v1")
-	v1.POST("/users", s.createUser)
-	v1.POST("/users/login", s.loginUser)
+	s.registerClientRoutes(v1.Group("", s.selectClient))
+	s.registerClientRoutes(v1.Group("/platforms/:"+clientPlatformParam, s.selectClient))
+
+	// Player lookups are public and carry no app client, so that API Gateway
+	// or a CDN can cache them.
+	v1.GET("/players/:id", s.rateLimit("players", playerRateLimits), s.getPlayer)
+	v1.POST("/players:"+playerActionParam, s.rateLimit("players", playerRateLimits), s.playerAction)
+
+	// The game client calls this itself once the hosted UI has redirected to
+	// it, so redirectUri must be the client's URI. The platform comes from the
+	// state rather than the path.
+	if s.oauth != nil {
+		v1.POST("/oauth/callback", s.rateLimit("login", loginRateLimits), s.oauthCallback)
+	}
 
 	s.ginLambda = ginadapter.New(s.engine)
 
 	slog.Info("Routes registered")
 }
 
+// registerClientRoutes registers the routes that act on behalf of a Cognito app
+// client, so they are reachable both with and without a platform path prefix.
+func (s *Server) registerClientRoutes(rg *gin.RouterGroup) {
+	rg.POST("/users", s.rateLimit("signup", signUpRateLimits), s.idempotent("signup"), s.createUser)
+	rg.POST("/users/login", s.rateLimit("login", loginRateLimits), s.loginUser)
+	rg.GET("/users/availability", s.rateLimit("availability", availabilityRateLimits), s.checkAvailability)
+	rg.POST("/users/password/forgot", s.rateLimit("password-reset", passwordResetRateLimits), s.forgotPassword)
+	rg.POST("/users/password/reset", s.rateLimit("password-reset", passwordResetRateLimits), s.resetPassword)
+	if s.oauth != nil {
+		rg.GET("/oauth/authorize", s.rateLimit("login", loginRateLimits), s.authorizeOAuth)
+	}
+	if s.guests {
+		rg.POST("/users/guest", s.rateLimit("guest", signUpRateLimits), s.createGuest)
+	}
+
+	me := rg.Group("/me", s.authenticate)
+	me.DELETE("", s.deleteUser)
+	me.POST("/password", s.changePassword)
+	me.GET("/profile", s.getProfile)
+	me.PUT("/profile", s.putProfile)
+	me.PUT("/display-name", s.putDisplayName)
+	if s.guests {
+		me.POST("/upgrade", s.requireGroup(guest.Group), s.upgradeGuest)
+	}
+	if s.userAdmin != nil {
+		me.GET("", s.getMe)
+		me.POST("/identities", s.linkIdentity)
+		me.DELETE("/identities/:provider", s.unlinkIdentity)
+	}
+
+	friendships := me.Group("/friends")
+	friendships.GET("", s.listFriends)
+	friendships.DELETE("/:id", s.removeFriend)
+	friendships.POST("/requests", s.sendFriendRequest)
+	friendships.POST("/requests/:id/accept", s.acceptFriendRequest)
+	friendships.POST("/requests/:id/decline", s.declineFriendRequest)
+	friendships.DELETE("/requests/:id", s.cancelFriendRequest)
+	friendships.PUT("/blocked/:id", s.blockPlayer)
+	friendships.DELETE("/blocked/:id", s.unblockPlayer)
+
+	admin := rg.Group("/admin", s.authenticate, s.requireGroup(adminGroup))
+	admin.DELETE("/users/:username/lockout", s.unlockUser)
+	admin.GET("/players/:id/display-names", s.listDisplayNames)
+}
+
 func (s *Server) HandleRequest(ctx context.Context, event events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	slog.Info("Received request", "event", event)
 	return s.ginLambda.ProxyWithContext(ctx, event)
@@ -1,16 +1,34 @@
 package api
 
 import (
-	"log/slog"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/guest"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+)
+
+const codeUsernameExistsException = "UsernameExistsException"
+
+// Cognito's own messages name its exceptions and tell apart unknown users from
+// wrong passwords, so clients get these instead.
+var (
+	errUsernameTaken      = errors.New("username is already taken")
+	errSignUpFailed       = errors.New("failed to create the account, please try again later")
+	errInvalidCredentials = errors.New("incorrect username or password")
+	errLoginFailed        = errors.New("failed to sign in, please try again later")
 )
 
 type createUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"`
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
+
+	CaptchaToken string `json:"captchaToken"`
 }
 
 type createUserResponse struct {
@@ -19,28 +37,88 @@ type createUserResponse struct {
 }
 
 func (s *Server) createUser(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	start := time.Now()
+	reason := reasonInternalError
+	defer func() { s.recordOutcome(ctx, metricSignUp, start, reason) }()
+
 	var req createUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", "error", err)
+		reason = reasonInvalidRequest
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
+	ctx.Set(usernameKey, req.Username)
+
+	if s.captcha != nil {
+		if reason = s.verifyCaptcha(ctx, req.CaptchaToken); reason != "" {
+			return
+		}
+	}
+
+	violations := s.usernames.Validate("username", req.Username)
+
+	emailAddress, emailViolations := s.emails.Validate("email", req.Email)
+	violations = append(violations, emailViolations...)
+
+	passwordViolations, err := s.passwords.Validate(ctx, "password", req.Password)
+	if err != nil {
+		logger.Error("Failed to validate password", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	violations = append(violations, passwordViolations...)
+
+	if len(violations) > 0 {
+		reason = reasonValidationFailed
+		ctx.JSON(http.StatusBadRequest, validationErrorResponse(violations))
+		return
+	}
+
+	client := appClient(ctx)
+	settleEvent, err := s.holdEvent(ctx, cevents.TypeUserSignedUp, 1, cevents.UserSignedUp{
+		Username: req.Username,
+		Email:    emailAddress,
+		ClientID: client.ClientID,
+	})
+	if err != nil {
+		logger.Error("Failed to hold event", "type", cevents.TypeUserSignedUp, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
 
-	if signUpErr := s.cognitoAuthService.SignUp(ctx, s.config.Cognito.ClientID, s.config.Cognito.ClientSecrets, req.Username, req.Password, req.Email); signUpErr != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(signUpErr))
+	signUpErr := s.cognitoAuthService.SignUp(ctx, client.ClientID, client.ClientSecrets, req.Username, req.Password, emailAddress)
+	settleEvent(signUpErr)
+	if signUpErr != nil {
+		logger.Error("Failed to sign up", "error", signUpErr)
+		reason = errorReason(signUpErr)
+		switch reason {
+		case codeInvalidPasswordException:
+			ctx.JSON(http.StatusBadRequest, validationErrorResponse(cognitoPasswordViolations("password")))
+		case codeUsernameExistsException:
+			ctx.JSON(http.StatusConflict, errorResponse(errUsernameTaken))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(errSignUpFailed))
+		}
 		return
 	}
 
 	resp := createUserResponse{
 		Username: req.Username,
-		Email:    req.Email,
+		Email:    emailAddress,
 	}
 
+	reason = ""
 	ctx.JSON(http.StatusCreated, successResponse(resp))
 }
 
 type loginUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"`
 	Password string `json:"password" binding:"required"`
+
+	CaptchaToken string `json:"captchaToken"`
 }
 
 type loginUserResponse struct {
@@ -49,48 +127,140 @@ type loginUserResponse struct {
 }
 
 func (s *Server) loginUser(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	start := time.Now()
+	reason := reasonInternalError
+	defer func() { s.recordOutcome(ctx, metricLogin, start, reason) }()
+
 	var req loginUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		slog.Error("Failed to bind request", "error", err)
+		logger.Error("Failed to bind request", "error", err)
+		reason = reasonInvalidRequest
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
+	ctx.Set(usernameKey, req.Username)
+
+	lockoutKey := normalizeUsername(req.Username)
+	status, locked := s.checkLockout(ctx, lockoutKey)
+	if locked {
+		reason = reasonAccountLocked
+		return
+	}
+
+	// Accounts that have been failing recently look like credential
+	// stuffing, so the caller has to prove it is human before trying again.
+	if s.captcha != nil && status.Failures >= captchaLoginFailures {
+		if reason = s.verifyCaptcha(ctx, req.CaptchaToken); reason != "" {
+			return
+		}
+	}
 
-	cgToken, err := s.cognitoAuthService.Login(ctx, s.config.Cognito.ClientID, s.config.Cognito.ClientSecrets, req.Username, req.Password)
+	client := appClient(ctx)
+	cgToken, err := s.cognitoAuthService.Login(ctx, client.ClientID, client.ClientSecrets, req.Username, req.Password)
+	// Users created by an import or migrated without their password can't get
+	// tokens until they set one. Neither is a failed attempt to lock out on.
+	var challengeErr *caws.ChallengeError
+	switch {
+	case errors.As(err, &challengeErr):
+		logger.Warn("Login needs a challenge", "challenge", challengeErr.Name)
+		reason = reasonPasswordChangeRequired
+		ctx.JSON(http.StatusForbidden, errorResponse(errPasswordChangeRequired))
+		return
+	case errorReason(err) == codePasswordResetRequiredException:
+		logger.Warn("Login needs a password reset", "error", err)
+		reason = reasonPasswordChangeRequired
+		ctx.JSON(http.StatusForbidden, errorResponse(errPasswordResetRequired))
+		return
+	}
 	if err != nil {
-		slog.Error("Failed to login", "error", err)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		logger.Error("Failed to login", "error", err)
+		reason = errorReason(err)
+		if !isCredentialError(err) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(errLoginFailed))
+			return
+		}
+		s.recordLoginFailure(ctx, lockoutKey, err)
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidCredentials))
+		return
+	}
+
+	resp, reason := s.verifyLoginTokens(ctx, client.ClientID, cgToken)
+	if reason != "" {
 		return
 	}
 
-	accessToken, err := s.cognitoAuthService.ValidateToken(ctx, s.config.Cognito.UserPoolID, cgToken.AccessToken)
+	s.resetLockout(ctx, lockoutKey)
+	if s.guests && guest.IsUsername(resp.User.Username) {
+		s.markGuestSeen(ctx, resp.User.Username)
+	}
+	s.publishEvent(ctx, cevents.TypeUserLoggedIn, 1, cevents.UserLoggedIn{
+		Username: resp.User.Username,
+		ClientID: client.ClientID,
+	})
+
+	ctx.JSON(http.StatusOK, successResponse(resp))
+}
+
+// verifyLoginTokens checks the tokens Cognito issued at the end of a sign-in
+// and builds the response from them. On failure it responds itself and
+// returns the metric reason; an empty reason means the tokens are good.
+func (s *Server) verifyLoginTokens(ctx *gin.Context, clientID string, cgToken *caws.CognitoToken) (loginUserResponse, string) {
+	logger := logging.FromContext(ctx)
+
+	accessToken, err := s.validateToken(ctx, clientID, cgToken.AccessToken)
 	if err != nil {
-		slog.Error("Failed to validate access token", "error", err)
+		logger.Error("Failed to validate access token", "error", err)
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
+		return loginUserResponse{}, reasonInvalidToken
 	}
 
-	idToken, err := s.cognitoAuthService.ValidateToken(ctx, s.config.Cognito.UserPoolID, cgToken.IdToken)
+	idToken, err := s.validateToken(ctx, clientID, cgToken.IdToken)
 	if err != nil {
-		slog.Error("Failed to validate id token", "error", err)
+		logger.Error("Failed to validate id token", "error", err)
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
+		return loginUserResponse{}, reasonInvalidToken
 	}
 
 	userInfo, err := s.cognitoAuthService.ParseUserInfo(idToken)
 	if err != nil {
-		slog.Error("Failed to parse user info", "error", err)
+		logger.Error("Failed to parse user info", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return loginUserResponse{}, reasonInternalError
 	}
 
-	resp := loginUserResponse{
+	return loginUserResponse{
 		AccessToken: accessToken.Raw,
 		User: createUserResponse{
 			Username: userInfo.Username,
 			Email:    userInfo.Email,
 		},
+	}, ""
+}
+
+// deleteUser deletes the caller's own account. The access token is revoked by
+// Cognito along with the user, so the client should discard it.
+func (s *Server) deleteUser(ctx *gin.Context) {
+	logger := logging.FromContext(ctx)
+
+	start := time.Now()
+	reason := reasonInternalError
+	defer func() { s.recordOutcome(ctx, metricAccountDeletion, start, reason) }()
+
+	if err := s.cognitoAuthService.DeleteUser(ctx, ctx.GetString(accessTokenKey)); err != nil {
+		logger.Error("Failed to delete user", "error", err)
+		reason = errorReason(err)
+		if reason == codeNotAuthorizedException {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
 
-	ctx.JSON(http.StatusOK, successResponse(resp))
+	s.publishEvent(ctx, cevents.TypeUserDeleted, 1, cevents.UserDeleted{Username: ctx.GetString(usernameKey)})
+
+	reason = ""
+	ctx.Status(http.StatusNoContent)
 }
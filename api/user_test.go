@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/aws/smithy-go"
 	"github.com/golang-jwt/jwt/v5"
 
 	"github.com/gin-gonic/gin"
@@ -17,11 +18,20 @@ import (
 	"github.com/stretchr/testify/require"
 	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
 	cconfig "github.com/whatisusername/toon-tank-user-service/internal/config"
+	"github.com/whatisusername/toon-tank-user-service/internal/email"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/friends"
+	"github.com/whatisusername/toon-tank-user-service/internal/password"
+	"github.com/whatisusername/toon-tank-user-service/internal/profile"
+	"github.com/whatisusername/toon-tank-user-service/internal/username"
+	"github.com/whatisusername/toon-tank-user-service/internal/validation"
 )
 
 func TestServer_createUser(t *testing.T) {
 	tests := []struct {
 		name          string
+		url           string
+		platform      string
 		body          gin.H
 		buildStubs    func(authSvc *caws.MockCognitoAuthService)
 		checkResponse func(recorder *httptest.ResponseRecorder)
@@ -53,6 +63,55 @@ func TestServer_createUser(t *testing.T) {
 				assert.Equal(t, expected, recorder.Body.Bytes())
 			},
 		},
+		{
+			name:     "Platform Header",
+			platform: "mobile",
+			body: gin.H{
+				"username": "test",
+				"email":    "test@example.com",
+				"password": "test123456A",
+			},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_mobile_client_id", "", "test", "test123456A", "test@example.com").
+					Return(nil).Once()
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "Platform Path Prefix",
+			url:  "/v1/platforms/pc/users",
+			body: gin.H{
+				"username": "test",
+				"email":    "test@example.com",
+				"password": "test123456A",
+			},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_pc_client_id", "fake_pc_client_secret", "test", "test123456A", "test@example.com").
+					Return(nil).Once()
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:     "Unknown Platform",
+			platform: "console",
+			body: gin.H{
+				"username": "test",
+				"email":    "test@example.com",
+				"password": "test123456A",
+			},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.AssertNotCalled(t, "SignUp")
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "Bad Request",
 			body: gin.H{
@@ -66,6 +125,74 @@ func TestServer_createUser(t *testing.T) {
 				assert.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name: "Reserved Username",
+			body: gin.H{
+				"username": "Adm1n",
+				"email":    "test@example.com",
+				"password": "test123456A",
+			},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.AssertNotCalled(t, "SignUp")
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				expected, err := json.Marshal(response{
+					Success: false,
+					Message: "Validation failed",
+					Errors: []validation.Violation{
+						{Field: "username", Code: username.CodeReserved, Message: "is reserved"},
+					},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, expected, recorder.Body.Bytes())
+			},
+		},
+		{
+			name: "Weak Password",
+			body: gin.H{
+				"username": "test",
+				"email":    "test@example.com",
+				"password": "test",
+			},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.AssertNotCalled(t, "SignUp")
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				expected, err := json.Marshal(response{
+					Success: false,
+					Message: "Validation failed",
+					Errors: []validation.Violation{
+						{Field: "password", Code: password.CodeTooShort, Message: "must be at least 8 characters long"},
+						{Field: "password", Code: password.CodeMissingUpper, Message: "must contain an uppercase letter"},
+						{Field: "password", Code: password.CodeMissingDigit, Message: "must contain a digit"},
+					},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, expected, recorder.Body.Bytes())
+			},
+		},
+		{
+			name: "Password Rejected By Cognito",
+			body: gin.H{
+				"username": "test",
+				"email":    "test@example.com",
+				"password": "test123456A",
+			},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+					Return(&smithy.GenericAPIError{Code: "InvalidPasswordException", Message: "Password did not conform with policy"}).Once()
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.NotContains(t, recorder.Body.String(), "did not conform")
+				assert.Contains(t, recorder.Body.String(), codePasswordRejected)
+			},
+		},
 		{
 			name: "Internal Server Error",
 			body: gin.H{
@@ -80,6 +207,24 @@ func TestServer_createUser(t *testing.T) {
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertErrorMessage(t, recorder, errSignUpFailed)
+			},
+		},
+		{
+			name: "Username Taken",
+			body: gin.H{
+				"username": "test",
+				"email":    "test@example.com",
+				"password": "test123456A",
+			},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "test@example.com").
+					Return(&smithy.GenericAPIError{Code: "UsernameExistsException", Message: "User already exists"}).Once()
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+				assertErrorMessage(t, recorder, errUsernameTaken)
 			},
 		},
 	}
@@ -92,8 +237,14 @@ func TestServer_createUser(t *testing.T) {
 			require.NoError(t, err)
 
 			url := "/v1/users"
+			if tt.url != "" {
+				url = tt.url
+			}
 			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
 			require.NoError(t, err)
+			if tt.platform != "" {
+				request.Header.Set(clientPlatformHeader, tt.platform)
+			}
 
 			testServer := newTestServer(t, cognitoAuthService)
 			recorder := httptest.NewRecorder()
@@ -104,6 +255,88 @@ func TestServer_createUser(t *testing.T) {
 	}
 }
 
+func TestServer_createUserEmail(t *testing.T) {
+	tests := []struct {
+		name       string
+		email      string
+		password   string
+		buildStubs func(authSvc *caws.MockCognitoAuthService)
+		wantStatus int
+		wantEmail  string
+		wantErrors []validation.Violation
+	}{
+		{
+			name:     "Normalized",
+			email:    "Test+ToonTank@Example.COM",
+			password: "test123456A",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					SignUp(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A", "Test@example.com").
+					Return(nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+			wantEmail:  "Test@example.com",
+		},
+		{
+			name:       "Invalid",
+			email:      "abc",
+			password:   "test123456A",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []validation.Violation{
+				{Field: "email", Code: email.CodeInvalid, Message: "must be a valid email address"},
+			},
+		},
+		{
+			name:       "Disposable",
+			email:      "test@mailinator.com",
+			password:   "test123456A",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []validation.Violation{
+				{Field: "email", Code: email.CodeDomainBlocked, Message: "disposable email addresses are not allowed"},
+			},
+		},
+		{
+			name:       "Reports Every Field",
+			email:      "abc",
+			password:   "TEST123456",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []validation.Violation{
+				{Field: "email", Code: email.CodeInvalid, Message: "must be a valid email address"},
+				{Field: "password", Code: password.CodeMissingLower, Message: "must contain a lowercase letter"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			tt.buildStubs(cognitoAuthService)
+
+			data, err := json.Marshal(gin.H{"username": "test", "email": tt.email, "password": tt.password})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/users", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			testServer := newTestServer(t, cognitoAuthService, WithEmailValidator(email.NewValidator(true, email.NewBlocklist("mailinator.com"))))
+			recorder := httptest.NewRecorder()
+
+			testServer.engine.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+
+			var resp struct {
+				Data   createUserResponse     `json:"data"`
+				Errors []validation.Violation `json:"errors"`
+			}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantEmail, resp.Data.Email)
+			assert.Equal(t, tt.wantErrors, resp.Errors)
+		})
+	}
+}
+
 func TestServer_loginUser(t *testing.T) {
 	fakeToken := &caws.CognitoToken{
 		IdToken:      "fake_id_token",
@@ -128,8 +361,8 @@ func TestServer_loginUser(t *testing.T) {
 					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A").
 					Return(fakeToken, nil).Once()
 
-				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"email": "test@example.com"})
-				mockTokenValidation(authSvc, "us-east-1_example", "fake_id_token", jwt.MapClaims{"cognito:username": "test", "email": "test@example.com"})
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test"})
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_id_token", jwt.MapClaims{"aud": "fake_client_id", "cognito:username": "test", "email": "test@example.com"})
 
 				authSvc.EXPECT().ParseUserInfo(mock.AnythingOfType("*jwt.Token")).
 					Return(&caws.CognitoUserInfo{
@@ -178,13 +411,82 @@ func TestServer_loginUser(t *testing.T) {
 			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
 				authSvc.EXPECT().
 					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456").
-					Return(nil, errors.New("incorrect username or password")).Once()
+					Return(nil, &smithy.GenericAPIError{Code: "NotAuthorizedException", Message: "Incorrect username or password."}).Once()
 
 				authSvc.AssertNotCalled(t, "ValidateToken")
 				authSvc.AssertNotCalled(t, "ParseUserInfo")
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+				assertErrorMessage(t, recorder, errInvalidCredentials)
+			},
+		},
+		{
+			// An unknown user gets the same answer as a wrong password, so
+			// usernames can't be probed through login.
+			name: "Unknown User",
+			body: gin.H{
+				"username": "test",
+				"password": "test123456",
+			},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456").
+					Return(nil, &smithy.GenericAPIError{Code: "UserNotFoundException", Message: "User does not exist."}).Once()
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+				assertErrorMessage(t, recorder, errInvalidCredentials)
+			},
+		},
+		{
+			name: "Cognito Unavailable",
+			body: gin.H{
+				"username": "test",
+				"password": "test123456",
+			},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456").
+					Return(nil, &smithy.GenericAPIError{Code: "TooManyRequestsException", Message: "Rate exceeded"}).Once()
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertErrorMessage(t, recorder, errLoginFailed)
+			},
+		},
+		{
+			// Users created by an import keep a temporary password until they
+			// set their own.
+			name: "New Password Required",
+			body: gin.H{
+				"username": "test",
+				"password": "test123456A",
+			},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A").
+					Return(nil, &caws.ChallengeError{Name: caws.ChallengeNewPasswordRequired}).Once()
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), errPasswordChangeRequired.Error())
+			},
+		},
+		{
+			name: "Password Reset Required",
+			body: gin.H{
+				"username": "test",
+				"password": "test123456A",
+			},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A").
+					Return(nil, &smithy.GenericAPIError{Code: "PasswordResetRequiredException"}).Once()
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), errPasswordResetRequired.Error())
 			},
 		},
 		{
@@ -218,7 +520,7 @@ func TestServer_loginUser(t *testing.T) {
 					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A").
 					Return(fakeToken, nil).Once()
 
-				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"email": "test@example.com"})
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test"})
 
 				authSvc.EXPECT().ValidateToken(mock.Anything, "us-east-1_example", "fake_id_token").
 					Return(nil, errors.New("invalid id token")).Once()
@@ -229,6 +531,45 @@ func TestServer_loginUser(t *testing.T) {
 				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name: "Access Token Client Mismatch",
+			body: gin.H{
+				"username": "test",
+				"password": "test123456A",
+			},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A").
+					Return(fakeToken, nil).Once()
+
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_pc_client_id", "username": "test"})
+
+				authSvc.AssertNotCalled(t, "ParseUserInfo")
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Id Token Client Mismatch",
+			body: gin.H{
+				"username": "test",
+				"password": "test123456A",
+			},
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				authSvc.EXPECT().
+					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A").
+					Return(fakeToken, nil).Once()
+
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test"})
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_id_token", jwt.MapClaims{"aud": "fake_pc_client_id", "cognito:username": "test", "email": "test@example.com"})
+
+				authSvc.AssertNotCalled(t, "ParseUserInfo")
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 		{
 			name: "Parse User Info Failed",
 			body: gin.H{
@@ -240,8 +581,8 @@ func TestServer_loginUser(t *testing.T) {
 					Login(mock.Anything, "fake_client_id", "fake_client_secret", "test", "test123456A").
 					Return(fakeToken, nil).Once()
 
-				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"email": "test@example.com"})
-				mockTokenValidation(authSvc, "us-east-1_example", "fake_id_token", jwt.MapClaims{"cognito:username": "test", "email": "test@example.com"})
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test"})
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_id_token", jwt.MapClaims{"aud": "fake_client_id", "cognito:username": "test", "email": "test@example.com"})
 
 				authSvc.EXPECT().ParseUserInfo(mock.AnythingOfType("*jwt.Token")).
 					Return(nil, errors.New("unexpected claims type")).Once()
@@ -272,20 +613,50 @@ func TestServer_loginUser(t *testing.T) {
 	}
 }
 
-func newTestServer(t *testing.T, cognitoAuthService *caws.MockCognitoAuthService) *Server {
+func newTestServer(t *testing.T, cognitoAuthService *caws.MockCognitoAuthService, opts ...Option) *Server {
 	t.Helper()
 	cfg := &cconfig.Config{
 		Cognito: cconfig.CognitoConfig{
 			UserPoolID:    "us-east-1_example",
 			ClientID:      "fake_client_id",
 			ClientSecrets: "fake_client_secret",
+			Clients: map[string]cconfig.ClientConfig{
+				"pc":     {ClientID: "fake_pc_client_id", ClientSecrets: "fake_pc_client_secret"},
+				"mobile": {ClientID: "fake_mobile_client_id"},
+			},
 		}}
 
-	server, err := NewServer(cfg, cognitoAuthService)
+	// Options given by the test come later and override these.
+	opts = append([]Option{
+		WithProfileRepository(profile.NewMemoryRepository()),
+		WithFriendsRepository(friends.NewMemoryRepository(cevents.NoopPublisher{})),
+	}, opts...)
+
+	server, err := NewServer(cfg, cognitoAuthService, opts...)
 	require.NoError(t, err)
 	return server
 }
 
+func TestNewServer_repositoriesRequired(t *testing.T) {
+	cfg := &cconfig.Config{}
+	profiles := WithProfileRepository(profile.NewMemoryRepository())
+	friendships := WithFriendsRepository(friends.NewMemoryRepository(cevents.NoopPublisher{}))
+
+	_, err := NewServer(cfg, caws.NewMockCognitoAuthService(t), friendships)
+	assert.Error(t, err, "profiles must not silently live in memory")
+	_, err = NewServer(cfg, caws.NewMockCognitoAuthService(t), profiles)
+	assert.Error(t, err, "friends must not silently live in memory")
+	_, err = NewServer(cfg, caws.NewMockCognitoAuthService(t), profiles, friendships)
+	assert.NoError(t, err)
+}
+
+func assertErrorMessage(t *testing.T, recorder *httptest.ResponseRecorder, want error) {
+	t.Helper()
+	var resp response
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, want.Error(), resp.Message)
+}
+
 func mockTokenValidation(authSvc *caws.MockCognitoAuthService, userPoolId, token string, claims jwt.MapClaims) {
 	authSvc.EXPECT().ValidateToken(mock.Anything, userPoolId, token).
 		Return(&jwt.Token{
@@ -297,3 +668,73 @@ func mockTokenValidation(authSvc *caws.MockCognitoAuthService, userPoolId, token
 			Valid:     true,
 		}, nil).Once()
 }
+
+func TestServer_deleteUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		buildStubs func(authSvc *caws.MockCognitoAuthService)
+		wantStatus int
+	}{
+		{
+			name:  "OK",
+			token: "fake_access_token",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test"})
+				authSvc.EXPECT().DeleteUser(mock.Anything, "fake_access_token").Return(nil).Once()
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "Missing Token",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:  "ID Token",
+			token: "fake_id_token",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_id_token", jwt.MapClaims{"token_use": "id", "aud": "fake_client_id", "cognito:username": "test"})
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:  "Token Revoked",
+			token: "fake_access_token",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test"})
+				authSvc.EXPECT().DeleteUser(mock.Anything, "fake_access_token").
+					Return(&smithy.GenericAPIError{Code: "NotAuthorizedException"}).Once()
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:  "Cognito Unavailable",
+			token: "fake_access_token",
+			buildStubs: func(authSvc *caws.MockCognitoAuthService) {
+				mockTokenValidation(authSvc, "us-east-1_example", "fake_access_token", jwt.MapClaims{"token_use": "access", "client_id": "fake_client_id", "username": "test"})
+				authSvc.EXPECT().DeleteUser(mock.Anything, "fake_access_token").
+					Return(errors.New("server is busy")).Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cognitoAuthService := caws.NewMockCognitoAuthService(t)
+			tt.buildStubs(cognitoAuthService)
+
+			request, err := http.NewRequest(http.MethodDelete, "/v1/me", nil)
+			require.NoError(t, err)
+			if tt.token != "" {
+				request.Header.Set(authorizationHeader, "Bearer "+tt.token)
+			}
+
+			testServer := newTestServer(t, cognitoAuthService)
+			recorder := httptest.NewRecorder()
+
+			testServer.engine.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+		})
+	}
+}
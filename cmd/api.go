@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/whatisusername/toon-tank-user-service/api"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/captcha"
+	cconfig "github.com/whatisusername/toon-tank-user-service/internal/config"
+	"github.com/whatisusername/toon-tank-user-service/internal/env"
+	"github.com/whatisusername/toon-tank-user-service/internal/friends"
+	"github.com/whatisusername/toon-tank-user-service/internal/idempotency"
+	"github.com/whatisusername/toon-tank-user-service/internal/lockout"
+	"github.com/whatisusername/toon-tank-user-service/internal/metrics"
+	"github.com/whatisusername/toon-tank-user-service/internal/oauth"
+	"github.com/whatisusername/toon-tank-user-service/internal/outbox"
+	"github.com/whatisusername/toon-tank-user-service/internal/profile"
+	"github.com/whatisusername/toon-tank-user-service/internal/ratelimit"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const handlerAPI = "api"
+
+func startAPI(ctx context.Context, tp *sdktrace.TracerProvider) {
+	secretStore, err := caws.NewSecretsService(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	cfg, err := cconfig.LoadConfig(ctx, secretStore)
+	if err != nil {
+		panic(err)
+	}
+
+	cognitoAuthSvc, err := caws.NewCognitoService(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	dynamoClient, err := caws.NewDynamoDBClient(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	recorder := metrics.NewEMFRecorder(env.GetValueOrDefault("METRICS_NAMESPACE", "ToonTank/UserService"), os.Stdout)
+
+	var limiter ratelimit.Limiter = ratelimit.NewMemoryLimiter()
+	if table := env.GetValueOrDefault("RATE_LIMIT_TABLE", ""); table != "" {
+		limiter = ratelimit.NewDynamoDBLimiter(dynamoClient, table)
+	}
+
+	var tracker lockout.Tracker = lockout.NewMemoryTracker(lockout.DefaultPolicy())
+	if table := env.GetValueOrDefault("LOCKOUT_TABLE", ""); table != "" {
+		tracker = lockout.NewDynamoDBTracker(lockout.DefaultPolicy(), dynamoClient, table)
+	}
+
+	// An in-progress claim only needs to outlive the request holding it, so the
+	// lease follows the function timeout.
+	lease, err := time.ParseDuration(env.GetValueOrDefault("IDEMPOTENCY_LEASE", "30s"))
+	if err != nil {
+		panic(err)
+	}
+
+	var idempotencyStore idempotency.Store = idempotency.NewMemoryStore(lease, 24*time.Hour)
+	if table := env.GetValueOrDefault("IDEMPOTENCY_TABLE", ""); table != "" {
+		idempotencyStore = idempotency.NewDynamoDBStore(dynamoClient, table, lease, 24*time.Hour)
+	}
+
+	// Players' data only lives in memory on a local run; a deployed function
+	// would lose it on every cold start.
+	localDev := env.GetValueOrDefault("LOCAL_DEV", "") == "true"
+
+	var profiles profile.ProfileRepository = profile.NewMemoryRepository()
+	if table := env.GetValueOrDefault("PROFILE_TABLE", ""); table != "" {
+		namesTable := env.GetValueOrDefault("DISPLAY_NAME_TABLE", "")
+		if namesTable == "" {
+			panic(errors.New("PROFILE_TABLE requires DISPLAY_NAME_TABLE"))
+		}
+		profiles = profile.NewDynamoDBRepository(dynamoClient, table, namesTable)
+	} else if !localDev {
+		panic(errors.New("PROFILE_TABLE is required unless LOCAL_DEV=true"))
+	}
+
+	publisher, err := newDomainEventPublisher(ctx, dynamoClient)
+	if err != nil {
+		panic(err)
+	}
+
+	var friendships friends.Repository = friends.NewMemoryRepository(publisher)
+	if table := env.GetValueOrDefault("FRIENDS_TABLE", ""); table != "" {
+		// Friendship events are written in the same transaction as the
+		// change, which needs the outbox to live in DynamoDB too.
+		outboxTable := env.GetValueOrDefault("OUTBOX_TABLE", "")
+		if outboxTable == "" {
+			panic(errors.New("FRIENDS_TABLE requires OUTBOX_TABLE"))
+		}
+		friendships = friends.NewDynamoDBRepository(dynamoClient, table, outbox.NewDynamoDBStore(dynamoClient, outboxTable))
+	} else if !localDev {
+		panic(errors.New("FRIENDS_TABLE is required unless LOCAL_DEV=true"))
+	}
+
+	opts := []api.Option{
+		api.WithMetricsRecorder(recorder),
+		api.WithRateLimiter(limiter),
+		api.WithLockoutTracker(tracker),
+		api.WithIdempotencyStore(idempotencyStore),
+		api.WithEventPublisher(publisher),
+		api.WithProfileRepository(profiles),
+		api.WithFriendsRepository(friendships),
+		api.WithCognitoUserAdmin(cognitoAuthSvc),
+	}
+
+	passwords := newPasswordValidator(cfg)
+	emails, err := newEmailValidator(cfg)
+	if err != nil {
+		panic(err)
+	}
+	usernames, err := newUsernameValidator(cfg)
+	if err != nil {
+		panic(err)
+	}
+	opts = append(opts,
+		api.WithPasswordValidator(passwords),
+		api.WithEmailValidator(emails),
+		api.WithUsernameValidator(usernames),
+	)
+
+	// Upgraded guests sign in with preferred_username, which the pool must
+	// accept as an alias.
+	if env.GetValueOrDefault("GUEST_ACCOUNTS", "") == "true" {
+		opts = append(opts, api.WithGuestAccounts())
+	}
+
+	if cfg.OAuth.Domain != "" {
+		states, err := newOAuthStateCodec(cfg.OAuth)
+		if err != nil {
+			panic(err)
+		}
+		opts = append(opts, api.WithOAuth(oauth.NewClient(cfg.OAuth.Domain), states))
+	}
+
+	verifier, err := newCaptchaVerifier(cfg.Captcha)
+	if err != nil {
+		panic(err)
+	}
+	if verifier != nil {
+		opts = append(opts, api.WithCaptchaVerifier(verifier))
+	}
+
+	server, err := api.NewServer(cfg, cognitoAuthSvc, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	start(ctx, tp, server.HandleRequest)
+}
+
+func newOAuthStateCodec(cfg cconfig.OAuthConfig) (*oauth.StateCodec, error) {
+	key, err := base64.StdEncoding.DecodeString(cfg.StateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid oauth state key: %w", err)
+	}
+	return oauth.NewStateCodec(key)
+}
+
+func newCaptchaVerifier(cfg cconfig.CaptchaConfig) (captcha.CaptchaVerifier, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "hcaptcha":
+		return captcha.NewHCaptchaVerifier(cfg.SecretKey, cfg.VerifyURL), nil
+	case "recaptcha":
+		return captcha.NewReCaptchaVerifier(cfg.SecretKey, cfg.VerifyURL, cfg.MinScore), nil
+	case "fake":
+		// Anyone who knows the token passes, so it must never reach a deployed
+		// function.
+		if env.GetValueOrDefault("LOCAL_DEV", "") != "true" {
+			return nil, errors.New("the fake captcha provider is only allowed with LOCAL_DEV=true")
+		}
+		return captcha.FakeVerifier{Token: cfg.SecretKey}, nil
+	default:
+		return nil, fmt.Errorf("unknown captcha provider %q", cfg.Provider)
+	}
+}
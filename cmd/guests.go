@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	cconfig "github.com/whatisusername/toon-tank-user-service/internal/config"
+	"github.com/whatisusername/toon-tank-user-service/internal/env"
+	"github.com/whatisusername/toon-tank-user-service/internal/guest"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const handlerGuestCleanup = "guest-cleanup"
+
+// startGuestCleanup deletes, on a schedule, guest accounts that were not
+// upgraded within GUEST_MAX_AGE.
+func startGuestCleanup(ctx context.Context, tp *sdktrace.TracerProvider) {
+	secretStore, err := caws.NewSecretsService(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	cfg, err := cconfig.LoadConfig(ctx, secretStore)
+	if err != nil {
+		panic(err)
+	}
+
+	cognitoSvc, err := caws.NewCognitoService(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	dynamoClient, err := caws.NewDynamoDBClient(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	publisher, err := newDomainEventPublisher(ctx, dynamoClient)
+	if err != nil {
+		panic(err)
+	}
+
+	maxAge, err := time.ParseDuration(env.GetValueOrDefault("GUEST_MAX_AGE", guest.DefaultMaxAge.String()))
+	if err != nil {
+		panic(err)
+	}
+
+	cleaner := guest.NewCleaner(cognitoSvc, cfg.Cognito.UserPoolID, maxAge, publisher)
+
+	start(ctx, tp, func(ctx context.Context, _ events.CloudWatchEvent) (guest.Result, error) {
+		return cleaner.Clean(ctx)
+	})
+}
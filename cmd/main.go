@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"github.com/aws/aws-lambda-go/lambda"
-	"github.com/whatisusername/toon-tank-user-service/api"
 	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
-	cconfig "github.com/whatisusername/toon-tank-user-service/internal/config"
+	"github.com/whatisusername/toon-tank-user-service/internal/env"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/telemetry"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"log/slog"
 )
 
@@ -19,25 +22,70 @@ func main() {
 
 	ctx := context.Background()
 
-	secretStore, err := caws.NewSecretsService(ctx)
+	tp, err := telemetry.NewTracerProvider(ctx, env.GetValueOrDefault("OTEL_TRACES_EXPORTER", telemetry.ExporterNone))
 	if err != nil {
 		panic(err)
 	}
 
-	cfg, err := cconfig.LoadConfig(ctx, secretStore)
-	if err != nil {
-		panic(err)
+	// Every function is deployed from the same image and told which handler
+	// to run through HANDLER.
+	switch handler := env.GetValueOrDefault("HANDLER", handlerAPI); handler {
+	case handlerAPI:
+		startAPI(ctx, tp)
+	case handlerOutboxDrain:
+		startOutboxDrain(ctx, tp)
+	case handlerCognitoTriggers:
+		startCognitoTriggers(ctx, tp)
+	case handlerGuestCleanup:
+		startGuestCleanup(ctx, tp)
+	default:
+		panic(fmt.Errorf("unknown handler %q", handler))
 	}
+}
 
-	cognitoAuthSvc, err := caws.NewCognitoService(ctx)
-	if err != nil {
-		panic(err)
+// start runs handler as the Lambda function. Lambda freezes the sandbox
+// between invocations, so spans are flushed before each response instead of
+// relying on the batcher's timer.
+func start[TIn, TOut any](ctx context.Context, tp *sdktrace.TracerProvider, handler func(context.Context, TIn) (TOut, error)) {
+	traced := func(ctx context.Context, event TIn) (TOut, error) {
+		defer func() {
+			if err := tp.ForceFlush(ctx); err != nil {
+				slog.Error("Failed to flush spans", "error", err)
+			}
+		}()
+		return handler(ctx, event)
 	}
 
-	server, err := api.NewServer(cfg, cognitoAuthSvc)
-	if err != nil {
-		panic(err)
-	}
+	lambda.StartWithOptions(traced, lambda.WithEnableSIGTERM(func() {
+		if err := tp.Shutdown(ctx); err != nil {
+			slog.Error("Failed to shut down tracer provider", "error", err)
+		}
+	}))
+}
 
-	lambda.Start(server.HandleRequest)
+// newEventPublisher picks the event destination from the environment. Only one
+// destination is used; EventBridge wins if several are set.
+func newEventPublisher(ctx context.Context) (cevents.EventPublisher, error) {
+	if busName := env.GetValueOrDefault("EVENT_BUS_NAME", ""); busName != "" {
+		client, err := caws.NewEventBridgeClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return cevents.NewEventBridgePublisher(client, busName), nil
+	}
+	if topicArn := env.GetValueOrDefault("EVENT_TOPIC_ARN", ""); topicArn != "" {
+		client, err := caws.NewSNSClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return cevents.NewSNSPublisher(client, topicArn), nil
+	}
+	if queueURL := env.GetValueOrDefault("EVENT_QUEUE_URL", ""); queueURL != "" {
+		client, err := caws.NewSQSClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return cevents.NewSQSPublisher(client, queueURL), nil
+	}
+	return cevents.NoopPublisher{}, nil
 }
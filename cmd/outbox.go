@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aws/aws-lambda-go/events"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	cconfig "github.com/whatisusername/toon-tank-user-service/internal/config"
+	"github.com/whatisusername/toon-tank-user-service/internal/env"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/outbox"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const handlerOutboxDrain = "outbox-drain"
+
+// startOutboxDrain runs the drainer on a schedule, sending the events the API
+// wrote to OUTBOX_TABLE to the configured destination.
+func startOutboxDrain(ctx context.Context, tp *sdktrace.TracerProvider) {
+	secretStore, err := caws.NewSecretsService(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	cfg, err := cconfig.LoadConfig(ctx, secretStore)
+	if err != nil {
+		panic(err)
+	}
+
+	cognitoSvc, err := caws.NewCognitoService(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	dynamoClient, err := caws.NewDynamoDBClient(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	publisher, err := newEventPublisher(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	store := outbox.NewDynamoDBStore(dynamoClient, env.GetValueOrDefault("OUTBOX_TABLE", ""))
+	drainer := outbox.NewDrainer(outbox.DefaultPolicy(), store, publisher, confirmSignUp(cognitoSvc, cfg.Cognito.UserPoolID))
+
+	start(ctx, tp, func(ctx context.Context, _ events.CloudWatchEvent) (outbox.Result, error) {
+		return drainer.Drain(ctx)
+	})
+}
+
+// confirmSignUp checks held user.signed_up events against the user pool: the
+// API holds them before calling SignUp, so an event left unsettled only
+// describes a real sign-up if the user exists.
+func confirmSignUp(cognitoSvc caws.CognitoAuthService, userPoolID string) outbox.ConfirmFunc {
+	return func(ctx context.Context, e cevents.Event) (bool, error) {
+		if e.Type != cevents.TypeUserSignedUp {
+			return true, nil
+		}
+
+		var data cevents.UserSignedUp
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return false, err
+		}
+		return cognitoSvc.UserExists(ctx, userPoolID, data.Username)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	cconfig "github.com/whatisusername/toon-tank-user-service/internal/config"
+	"github.com/whatisusername/toon-tank-user-service/internal/email"
+	"github.com/whatisusername/toon-tank-user-service/internal/env"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/outbox"
+	"github.com/whatisusername/toon-tank-user-service/internal/password"
+	"github.com/whatisusername/toon-tank-user-service/internal/username"
+)
+
+func newPasswordValidator(cfg *cconfig.Config) *password.Validator {
+	policy := password.DefaultPolicy()
+	if cfg.PasswordPolicy != nil {
+		policy = *cfg.PasswordPolicy
+	}
+	var breaches password.BreachList
+	if dir := env.GetValueOrDefault("BREACHED_PASSWORDS_DIR", ""); dir != "" {
+		breaches = password.NewFileBreachList(dir)
+	}
+	return password.NewValidator(policy, breaches)
+}
+
+func newEmailValidator(cfg *cconfig.Config) (*email.Validator, error) {
+	blocklist := email.NewBlocklist(cfg.Email.BlockedDomains...)
+	if path := env.GetValueOrDefault("DISPOSABLE_EMAIL_DOMAINS_FILE", ""); path != "" {
+		fromFile, err := email.LoadBlocklistFile(path)
+		if err != nil {
+			return nil, err
+		}
+		blocklist.Merge(fromFile)
+	}
+	return email.NewValidator(cfg.Email.StripTags, blocklist), nil
+}
+
+func newUsernameValidator(cfg *cconfig.Config) (*username.Validator, error) {
+	var profanity []string
+	if path := env.GetValueOrDefault("PROFANITY_WORDS_FILE", ""); path != "" {
+		var err error
+		if profanity, err = username.LoadWordListFile(path); err != nil {
+			return nil, err
+		}
+	}
+	reserved := slices.Concat(username.DefaultReserved, cfg.Username.ReservedNames)
+	return username.NewValidator(username.DefaultPolicy(), reserved, profanity), nil
+}
+
+// newDomainEventPublisher is the publisher for handlers that change state. They
+// write to the outbox when OUTBOX_TABLE is set and publish directly otherwise.
+func newDomainEventPublisher(ctx context.Context, dynamoClient *dynamodb.Client) (cevents.EventPublisher, error) {
+	if table := env.GetValueOrDefault("OUTBOX_TABLE", ""); table != "" {
+		return outbox.NewPublisher(outbox.NewDynamoDBStore(dynamoClient, table)), nil
+	}
+	return newEventPublisher(ctx)
+}
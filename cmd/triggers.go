@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	cconfig "github.com/whatisusername/toon-tank-user-service/internal/config"
+	"github.com/whatisusername/toon-tank-user-service/internal/env"
+	"github.com/whatisusername/toon-tank-user-service/internal/legacy"
+	"github.com/whatisusername/toon-tank-user-service/triggers"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const handlerCognitoTriggers = "cognito-triggers"
+
+// startCognitoTriggers serves every Cognito user pool trigger from one
+// function; the handler tells them apart by the event's trigger source.
+func startCognitoTriggers(ctx context.Context, tp *sdktrace.TracerProvider) {
+	secretStore, err := caws.NewSecretsService(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	cfg, err := cconfig.LoadConfig(ctx, secretStore)
+	if err != nil {
+		panic(err)
+	}
+
+	dynamoClient, err := caws.NewDynamoDBClient(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	publisher, err := newDomainEventPublisher(ctx, dynamoClient)
+	if err != nil {
+		panic(err)
+	}
+
+	emails, err := newEmailValidator(cfg)
+	if err != nil {
+		panic(err)
+	}
+	usernames, err := newUsernameValidator(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	opts := []triggers.Option{
+		triggers.WithEmailValidator(emails),
+		triggers.WithUsernameValidator(usernames),
+		triggers.WithEventPublisher(publisher),
+	}
+
+	if path := env.GetValueOrDefault("LEGACY_USERS_FILE", ""); path != "" {
+		legacyUsers, err := legacy.LoadFileStoreFile(path)
+		if err != nil {
+			panic(err)
+		}
+		opts = append(opts, triggers.WithLegacyUserStore(legacyUsers))
+	}
+
+	handler := triggers.NewHandler(opts...)
+
+	start(ctx, tp, handler.HandleRequest)
+}
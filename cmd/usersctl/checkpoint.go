@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+type checkpointEntry struct {
+	Username string `json:"username"`
+	Outcome  string `json:"outcome"`
+}
+
+// checkpoint is an append-only log of the users an import has finished with,
+// so that a rerun after a crash or an interrupt skips them. Only users that
+// now exist in the pool are recorded; failed ones are tried again.
+type checkpoint struct {
+	mu   sync.Mutex
+	f    *os.File
+	done map[string]bool
+}
+
+func openCheckpoint(path string) (*checkpoint, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &checkpoint{f: f, done: make(map[string]bool)}
+	if err = c.load(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to read checkpoint %s: %w", path, err)
+	}
+	return c, nil
+}
+
+func (c *checkpoint) load(f *os.File) error {
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	// Terminate a torn last line so the next record starts on its own line.
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		if _, err = f.Write([]byte{'\n'}); err != nil {
+			return err
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var e checkpointEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			// A crash can leave a torn last line; that user is simply
+			// retried.
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				continue
+			}
+			return err
+		}
+		c.done[e.Username] = true
+	}
+	return scanner.Err()
+}
+
+// Done reports whether username was finished by an earlier run.
+func (c *checkpoint) Done(username string) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.done[username]
+}
+
+func (c *checkpoint) Record(username, outcome string) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	line, err := json.Marshal(checkpointEntry{Username: username, Outcome: outcome})
+	if err != nil {
+		return err
+	}
+	if _, err = c.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	c.done[username] = true
+	return nil
+}
+
+func (c *checkpoint) Close() error {
+	return c.f.Close()
+}
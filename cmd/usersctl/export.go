@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+)
+
+const (
+	formatJSONL = "jsonl"
+	formatCSV   = "csv"
+)
+
+// csvHeader lists the columns of a CSV export. JSONL exports carry every
+// attribute instead.
+var csvHeader = []string{"username", "sub", "email", "email_verified", "status", "enabled", "created", "modified"}
+
+// exportUsers writes every user of the pool to w and returns how many there
+// were.
+func exportUsers(ctx context.Context, admin caws.CognitoUserAdmin, userPoolID, format string, w io.Writer) (int, error) {
+	var write func(caws.CognitoUser) error
+	var flush func() error
+
+	switch format {
+	case formatJSONL:
+		enc := json.NewEncoder(w)
+		write = func(u caws.CognitoUser) error { return enc.Encode(u) }
+		flush = func() error { return nil }
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return 0, err
+		}
+		write = func(u caws.CognitoUser) error {
+			return cw.Write([]string{
+				u.Username,
+				u.Attributes["sub"],
+				u.Attributes["email"],
+				u.Attributes["email_verified"],
+				u.Status,
+				strconv.FormatBool(u.Enabled),
+				u.Created.UTC().Format(time.RFC3339),
+				u.Modified.UTC().Format(time.RFC3339),
+			})
+		}
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	default:
+		return 0, fmt.Errorf("unknown format %q, want %s or %s", format, formatJSONL, formatCSV)
+	}
+
+	n := 0
+	err := admin.ListUsers(ctx, userPoolID, func(u caws.CognitoUser) error {
+		n++
+		return write(u)
+	})
+	if err != nil {
+		return n, err
+	}
+	return n, flush()
+}
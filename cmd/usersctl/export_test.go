@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+)
+
+func TestExportUsers(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	users := []caws.CognitoUser{
+		{
+			Username:   "alice",
+			Status:     "CONFIRMED",
+			Enabled:    true,
+			Created:    created,
+			Modified:   created,
+			Attributes: map[string]string{"sub": "sub-1", "email": "alice@example.com", "email_verified": "true"},
+		},
+		{
+			Username:   "bob",
+			Status:     "FORCE_CHANGE_PASSWORD",
+			Enabled:    false,
+			Created:    created,
+			Modified:   created.Add(time.Hour),
+			Attributes: map[string]string{"sub": "sub-2", "email": "bob@example.com"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "JSONL",
+			format: formatJSONL,
+			want: `{"username":"alice","status":"CONFIRMED","enabled":true,"created":"2024-05-01T12:00:00Z","modified":"2024-05-01T12:00:00Z","attributes":{"email":"alice@example.com","email_verified":"true","sub":"sub-1"}}
+{"username":"bob","status":"FORCE_CHANGE_PASSWORD","enabled":false,"created":"2024-05-01T12:00:00Z","modified":"2024-05-01T13:00:00Z","attributes":{"email":"bob@example.com","sub":"sub-2"}}
+`,
+		},
+		{
+			name:   "CSV",
+			format: formatCSV,
+			want: `username,sub,email,email_verified,status,enabled,created,modified
+alice,sub-1,alice@example.com,true,CONFIRMED,true,2024-05-01T12:00:00Z,2024-05-01T12:00:00Z
+bob,sub-2,bob@example.com,,FORCE_CHANGE_PASSWORD,false,2024-05-01T12:00:00Z,2024-05-01T13:00:00Z
+`,
+		},
+		{
+			name:    "Unknown Format",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := caws.NewMockCognitoUserAdmin(t)
+			if !tt.wantErr {
+				admin.EXPECT().ListUsers(mock.Anything, "us-east-1_example", mock.Anything).
+					RunAndReturn(func(_ context.Context, _ string, fn func(caws.CognitoUser) error) error {
+						for _, u := range users {
+							if err := fn(u); err != nil {
+								return err
+							}
+						}
+						return nil
+					})
+			}
+
+			var buf bytes.Buffer
+			n, err := exportUsers(context.Background(), admin, "us-east-1_example", tt.format, &buf)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, len(users), n)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/smithy-go"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/email"
+)
+
+const (
+	columnUsername          = "username"
+	columnEmail             = "email"
+	columnTemporaryPassword = "temporary_password"
+
+	outcomeCreated = "created"
+	outcomeExists  = "exists"
+
+	codeUsernameExistsException = "UsernameExistsException"
+)
+
+// importRow is one user from the CSV. Columns other than username, email and
+// temporary_password are passed to Cognito as attributes under their header
+// name, e.g. email_verified or custom:region.
+type importRow struct {
+	line int
+	user caws.NewCognitoUser
+}
+
+type rowFailure struct {
+	Line     int
+	Username string
+	Reason   string
+}
+
+type importSummary struct {
+	DryRun   bool
+	Rows     int
+	Created  int
+	Existing int
+	Resumed  int
+	Invalid  int
+	Failed   int
+	Failures []rowFailure
+}
+
+func (s importSummary) write(w io.Writer) {
+	mode := ""
+	if s.DryRun {
+		mode = " (dry run)"
+	}
+	fmt.Fprintf(w, "Import summary%s\n", mode)
+	fmt.Fprintf(w, "  rows:     %d\n", s.Rows)
+	fmt.Fprintf(w, "  created:  %d\n", s.Created)
+	fmt.Fprintf(w, "  existing: %d\n", s.Existing)
+	fmt.Fprintf(w, "  resumed:  %d\n", s.Resumed)
+	fmt.Fprintf(w, "  invalid:  %d\n", s.Invalid)
+	fmt.Fprintf(w, "  failed:   %d\n", s.Failed)
+	for _, f := range s.Failures {
+		fmt.Fprintf(w, "  line %d %s: %s\n", f.Line, f.Username, f.Reason)
+	}
+}
+
+type importer struct {
+	admin          caws.CognitoUserAdmin
+	userPoolID     string
+	dryRun         bool
+	concurrency    int
+	suppressInvite bool
+	checkpoint     *checkpoint
+
+	mu      sync.Mutex
+	summary importSummary
+}
+
+// run validates every row and, unless this is a dry run, creates the valid
+// ones. Users that already exist count as done, so an import can be rerun
+// without a checkpoint too. Cancelling ctx stops new users from being
+// created; the ones in flight finish and are recorded.
+func (imp *importer) run(ctx context.Context, r io.Reader) (importSummary, error) {
+	imp.summary = importSummary{DryRun: imp.dryRun}
+
+	rows, err := imp.parse(r)
+	if err != nil {
+		return imp.summary, err
+	}
+
+	work := make(chan importRow)
+	var wg sync.WaitGroup
+	for i := 0; i < imp.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range work {
+				imp.create(ctx, row)
+			}
+		}()
+	}
+
+feed:
+	for _, row := range rows {
+		if imp.checkpoint.Done(row.user.Username) {
+			imp.summary.Resumed++
+			continue
+		}
+		if imp.dryRun {
+			continue
+		}
+		select {
+		case work <- row:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	sort.Slice(imp.summary.Failures, func(i, j int) bool {
+		return imp.summary.Failures[i].Line < imp.summary.Failures[j].Line
+	})
+	return imp.summary, ctx.Err()
+}
+
+// parse reads the CSV and returns the valid rows, counting the others as
+// invalid. Only problems with the file as a whole are returned as errors.
+func (imp *importer) parse(r io.Reader) ([]importRow, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	if !containsAll(header, columnUsername, columnEmail) {
+		return nil, fmt.Errorf("header must include %q and %q columns", columnUsername, columnEmail)
+	}
+
+	var rows []importRow
+	seen := make(map[string]int)
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		imp.summary.Rows++
+
+		row := importRow{line: line, user: caws.NewCognitoUser{
+			Attributes:      make(map[string]string),
+			SuppressMessage: imp.suppressInvite,
+		}}
+		for i, column := range header {
+			value := strings.TrimSpace(record[i])
+			switch column {
+			case columnUsername:
+				row.user.Username = value
+			case columnTemporaryPassword:
+				row.user.TemporaryPassword = value
+			default:
+				if value != "" {
+					row.user.Attributes[column] = value
+				}
+			}
+		}
+
+		if reason := imp.validate(&row, seen); reason != "" {
+			imp.fail(row, reason, true)
+			continue
+		}
+		seen[strings.ToLower(row.user.Username)] = line
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (imp *importer) validate(row *importRow, seen map[string]int) string {
+	if row.user.Username == "" {
+		return "username is empty"
+	}
+	if first, ok := seen[strings.ToLower(row.user.Username)]; ok {
+		return fmt.Sprintf("duplicate of line %d", first)
+	}
+
+	normalized, err := email.Normalize(row.user.Attributes[columnEmail], false)
+	if err != nil {
+		return "invalid email: " + err.Error()
+	}
+	row.user.Attributes[columnEmail] = normalized
+	return ""
+}
+
+func (imp *importer) create(ctx context.Context, row importRow) {
+	err := imp.admin.AdminCreateUser(ctx, imp.userPoolID, row.user)
+
+	outcome := outcomeCreated
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == codeUsernameExistsException {
+		outcome, err = outcomeExists, nil
+	}
+	if err != nil {
+		imp.fail(row, err.Error(), false)
+		return
+	}
+
+	if err = imp.checkpoint.Record(row.user.Username, outcome); err != nil {
+		imp.fail(row, "created but not checkpointed: "+err.Error(), false)
+		return
+	}
+
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+	if outcome == outcomeCreated {
+		imp.summary.Created++
+	} else {
+		imp.summary.Existing++
+	}
+}
+
+func (imp *importer) fail(row importRow, reason string, invalid bool) {
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+
+	if invalid {
+		imp.summary.Invalid++
+	} else {
+		imp.summary.Failed++
+	}
+	imp.summary.Failures = append(imp.summary.Failures, rowFailure{Line: row.line, Username: row.user.Username, Reason: reason})
+}
+
+func containsAll(header []string, columns ...string) bool {
+	for _, c := range columns {
+		found := false
+		for _, h := range header {
+			if h == c {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+)
+
+const importCSV = `username,email,email_verified,custom:region
+alice, Alice@Example.com ,true,eu
+bob,bob@example.com,false,
+,nobody@example.com,,
+carol,not-an-email,,
+ALICE,alice2@example.com,,
+dave,dave@example.com,,
+`
+
+func TestImporter_run(t *testing.T) {
+	tests := []struct {
+		name       string
+		dryRun     bool
+		checkpoint string
+		create     map[string]error
+		want       importSummary
+	}{
+		{
+			name: "Import",
+			create: map[string]error{
+				"alice": nil,
+				"bob":   &smithy.GenericAPIError{Code: codeUsernameExistsException},
+				"dave":  errors.New("throttled"),
+			},
+			want: importSummary{Rows: 6, Created: 1, Existing: 1, Invalid: 3, Failed: 1},
+		},
+		{
+			name:   "Dry Run",
+			dryRun: true,
+			want:   importSummary{DryRun: true, Rows: 6, Invalid: 3},
+		},
+		{
+			name:       "Resume",
+			checkpoint: `{"username":"alice","outcome":"created"}` + "\n" + `{"username":"bob","outc`,
+			create: map[string]error{
+				"bob":  nil,
+				"dave": nil,
+			},
+			want: importSummary{Rows: 6, Created: 2, Resumed: 1, Invalid: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := caws.NewMockCognitoUserAdmin(t)
+			var mu sync.Mutex
+			created := make(map[string]caws.NewCognitoUser)
+			for username, err := range tt.create {
+				admin.EXPECT().AdminCreateUser(mock.Anything, "us-east-1_example", mock.MatchedBy(func(u caws.NewCognitoUser) bool {
+					return u.Username == username
+				})).RunAndReturn(func(_ context.Context, _ string, u caws.NewCognitoUser) error {
+					mu.Lock()
+					defer mu.Unlock()
+					created[u.Username] = u
+					return err
+				}).Once()
+			}
+
+			path := filepath.Join(t.TempDir(), "users.csv.checkpoint")
+			require.NoError(t, os.WriteFile(path, []byte(tt.checkpoint), 0o644))
+			cp, err := openCheckpoint(path)
+			require.NoError(t, err)
+			defer cp.Close()
+
+			imp := &importer{
+				admin:          admin,
+				userPoolID:     "us-east-1_example",
+				dryRun:         tt.dryRun,
+				concurrency:    2,
+				suppressInvite: true,
+				checkpoint:     cp,
+			}
+			got, err := imp.run(context.Background(), strings.NewReader(importCSV))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want.Rows, got.Rows)
+			assert.Equal(t, tt.want.Created, got.Created)
+			assert.Equal(t, tt.want.Existing, got.Existing)
+			assert.Equal(t, tt.want.Resumed, got.Resumed)
+			assert.Equal(t, tt.want.Invalid, got.Invalid)
+			assert.Equal(t, tt.want.Failed, got.Failed)
+			assert.Len(t, got.Failures, tt.want.Invalid+tt.want.Failed)
+
+			if u, ok := created["alice"]; ok {
+				assert.Equal(t, map[string]string{"email": "Alice@example.com", "email_verified": "true", "custom:region": "eu"}, u.Attributes)
+				assert.True(t, u.SuppressMessage)
+			}
+
+			// Everything that now exists in the pool is checkpointed, so a rerun
+			// has nothing left to create.
+			reopened, err := openCheckpoint(path)
+			require.NoError(t, err)
+			defer reopened.Close()
+			for username, err := range tt.create {
+				assert.Equal(t, err == nil || username == "bob", reopened.Done(username), username)
+			}
+		})
+	}
+}
+
+func TestImporter_runInvalidHeader(t *testing.T) {
+	imp := &importer{concurrency: 1, dryRun: true}
+	_, err := imp.run(context.Background(), strings.NewReader("name,mail\nalice,alice@example.com\n"))
+	require.Error(t, err)
+}
@@ -0,0 +1,159 @@
+// Command usersctl exports the users of a Cognito user pool and imports users
+// into one, for seeding test pools and feeding analytics.
+//
+//	usersctl export [-user-pool-id ID] [-format jsonl|csv] [-out FILE]
+//	usersctl import -in FILE [-user-pool-id ID] [-dry-run] [-concurrency N] [-checkpoint FILE] [-suppress-invite]
+//
+// Without -user-pool-id the pool is read from the service config named by
+// SECRET_NAME, like the service itself does.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	cconfig "github.com/whatisusername/toon-tank-user-service/internal/config"
+)
+
+const usage = `usage:
+  usersctl export [-user-pool-id ID] [-format jsonl|csv] [-out FILE]
+  usersctl import -in FILE [-user-pool-id ID] [-dry-run] [-concurrency N] [-checkpoint FILE] [-suppress-invite]`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		slog.Error("usersctl failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	switch args[0] {
+	case "export":
+		return runExport(ctx, args[1:], stdout)
+	case "import":
+		return runImport(ctx, args[1:], stdout)
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+}
+
+func runExport(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	userPoolID := fs.String("user-pool-id", "", "user pool to export; defaults to the one in the service config")
+	format := fs.String("format", formatJSONL, "output format, jsonl or csv")
+	out := fs.String("out", "", "file to write to; defaults to stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	svc, poolID, err := connect(ctx, *userPoolID)
+	if err != nil {
+		return err
+	}
+
+	w := stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	n, err := exportUsers(ctx, svc, poolID, *format, w)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Exported users", "count", n, "userPoolId", poolID)
+	return nil
+}
+
+func runImport(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	userPoolID := fs.String("user-pool-id", "", "user pool to import into; defaults to the one in the service config")
+	in := fs.String("in", "", "CSV file to import")
+	dryRun := fs.Bool("dry-run", false, "validate the file without creating users")
+	concurrency := fs.Int("concurrency", 4, "number of users created at the same time")
+	checkpoint := fs.String("checkpoint", "", "file recording imported users, to resume from; defaults to FILE.checkpoint")
+	suppressInvite := fs.Bool("suppress-invite", false, "create users without sending the invitation message")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" {
+		return errors.New("-in is required")
+	}
+	if *concurrency < 1 {
+		return errors.New("-concurrency must be at least 1")
+	}
+	if *checkpoint == "" {
+		*checkpoint = *in + ".checkpoint"
+	}
+
+	f, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	imp := &importer{
+		dryRun:         *dryRun,
+		concurrency:    *concurrency,
+		suppressInvite: *suppressInvite,
+	}
+	if !imp.dryRun {
+		if imp.admin, imp.userPoolID, err = connect(ctx, *userPoolID); err != nil {
+			return err
+		}
+		if imp.checkpoint, err = openCheckpoint(*checkpoint); err != nil {
+			return err
+		}
+		defer imp.checkpoint.Close()
+	}
+
+	summary, err := imp.run(ctx, f)
+	summary.write(stdout)
+	if err != nil {
+		return err
+	}
+	if summary.Failed > 0 || summary.Invalid > 0 {
+		return fmt.Errorf("%d users failed and %d rows were invalid", summary.Failed, summary.Invalid)
+	}
+	return nil
+}
+
+// connect returns the Cognito admin client and the pool to work on.
+func connect(ctx context.Context, userPoolID string) (caws.CognitoUserAdmin, string, error) {
+	svc, err := caws.NewCognitoService(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	if userPoolID != "" {
+		return svc, userPoolID, nil
+	}
+
+	secretStore, err := caws.NewSecretsService(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	cfg, err := cconfig.LoadConfig(ctx, secretStore)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load config, pass -user-pool-id or set SECRET_NAME: %w", err)
+	}
+	return svc, cfg.Cognito.UserPoolID, nil
+}
@@ -15,6 +15,153 @@ data "aws_ecr_image" "main" {
   most_recent     = var.image_digest == null && var.image_tag == null ? true : null
 }
 
+# Resource: aws_dynamodb_table
+# https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/dynamodb_table
+
+resource "aws_dynamodb_table" "rate_limits" {
+  name         = format("%s-rate-limits-%s", lower(var.product), var.env)
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "key"
+
+  attribute {
+    name = "key"
+    type = "S"
+  }
+
+  ttl {
+    attribute_name = "expiresAt"
+    enabled        = true
+  }
+}
+
+resource "aws_dynamodb_table" "idempotency" {
+  name         = format("%s-idempotency-%s", lower(var.product), var.env)
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "key"
+
+  attribute {
+    name = "key"
+    type = "S"
+  }
+
+  ttl {
+    attribute_name = "purgeAt"
+    enabled        = true
+  }
+}
+
+resource "aws_dynamodb_table" "lockouts" {
+  name         = format("%s-lockouts-%s", lower(var.product), var.env)
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "key"
+
+  attribute {
+    name = "key"
+    type = "S"
+  }
+
+  ttl {
+    attribute_name = "expiresAt"
+    enabled        = true
+  }
+}
+
+resource "aws_dynamodb_table" "profiles" {
+  name         = format("%s-profiles-%s", lower(var.product), var.env)
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "playerId"
+
+  attribute {
+    name = "playerId"
+    type = "S"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+}
+
+resource "aws_dynamodb_table" "display_names" {
+  name         = format("%s-display-names-%s", lower(var.product), var.env)
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "nameKey"
+
+  attribute {
+    name = "nameKey"
+    type = "S"
+  }
+}
+
+resource "aws_dynamodb_table" "friends" {
+  name         = format("%s-friends-%s", lower(var.product), var.env)
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "playerId"
+  range_key    = "otherId"
+
+  attribute {
+    name = "playerId"
+    type = "S"
+  }
+
+  attribute {
+    name = "otherId"
+    type = "S"
+  }
+
+  attribute {
+    name = "playerState"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "state"
+    hash_key        = "playerState"
+    range_key       = "otherId"
+    projection_type = "ALL"
+  }
+}
+
+resource "aws_dynamodb_table" "outbox" {
+  name         = format("%s-outbox-%s", lower(var.product), var.env)
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "id"
+
+  attribute {
+    name = "id"
+    type = "S"
+  }
+
+  attribute {
+    name = "queue"
+    type = "S"
+  }
+
+  attribute {
+    name = "createdAt"
+    type = "N"
+  }
+
+  global_secondary_index {
+    name               = "pending"
+    hash_key           = "queue"
+    range_key          = "createdAt"
+    projection_type    = "INCLUDE"
+    non_key_attributes = ["leaseUntil"]
+  }
+
+  ttl {
+    attribute_name = "expiresAt"
+    enabled        = true
+  }
+}
+
+# Resource: aws_cloudwatch_event_bus
+# https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/cloudwatch_event_bus
+
+resource "aws_cloudwatch_event_bus" "user_events" {
+  name = format("%s-user-events-%s", lower(var.product), var.env)
+}
+
 # module: lambda
 # https://registry.terraform.io/modules/terraform-aws-modules/lambda/aws/latest
 
@@ -35,7 +182,17 @@ module "lambda_with_image" {
   architectures  = ["x86_64"]
 
   environment_variables = {
-    SECRET_NAME = format("%s-cognito-secrets-%s", lower(var.product), var.env)
+    SECRET_NAME          = format("%s-cognito-secrets-%s", lower(var.product), var.env)
+    OTEL_TRACES_EXPORTER = var.trace_exporter
+    RATE_LIMIT_TABLE     = aws_dynamodb_table.rate_limits.name
+    LOCKOUT_TABLE        = aws_dynamodb_table.lockouts.name
+    IDEMPOTENCY_TABLE    = aws_dynamodb_table.idempotency.name
+    IDEMPOTENCY_LEASE    = format("%ds", var.timeout)
+    OUTBOX_TABLE         = aws_dynamodb_table.outbox.name
+    PROFILE_TABLE        = aws_dynamodb_table.profiles.name
+    DISPLAY_NAME_TABLE   = aws_dynamodb_table.display_names.name
+    FRIENDS_TABLE        = aws_dynamodb_table.friends.name
+    GUEST_ACCOUNTS       = tostring(var.guest_accounts)
   }
 
   use_existing_cloudwatch_log_group = false
@@ -48,6 +205,156 @@ module "lambda_with_image" {
   }
 }
 
+module "lambda_outbox_drain" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "~> 7.17.0"
+
+  function_name  = format("%s-outbox-drain-%s", var.name, var.env)
+  description    = "Publishes the events queued in the outbox"
+  create_role    = false
+  lambda_role    = data.aws_iam_role.user_auth.arn
+  memory_size    = var.memory_size
+  publish        = true
+  timeout        = 30
+  image_uri      = data.aws_ecr_image.main.image_uri
+  create_package = false
+  package_type   = "Image"
+  architectures  = ["x86_64"]
+
+  environment_variables = {
+    HANDLER              = "outbox-drain"
+    SECRET_NAME          = format("%s-cognito-secrets-%s", lower(var.product), var.env)
+    OTEL_TRACES_EXPORTER = var.trace_exporter
+    OUTBOX_TABLE         = aws_dynamodb_table.outbox.name
+    EVENT_BUS_NAME       = aws_cloudwatch_event_bus.user_events.name
+  }
+
+  use_existing_cloudwatch_log_group = false
+  cloudwatch_logs_retention_in_days = 30
+  cloudwatch_logs_skip_destroy      = false
+  cloudwatch_logs_log_group_class   = "STANDARD"
+}
+
+module "lambda_cognito_triggers" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "~> 7.17.0"
+
+  function_name  = format("%s-cognito-triggers-%s", var.name, var.env)
+  description    = "Cognito user pool triggers"
+  create_role    = false
+  lambda_role    = data.aws_iam_role.user_auth.arn
+  memory_size    = var.memory_size
+  publish        = true
+  timeout        = 5
+  image_uri      = data.aws_ecr_image.main.image_uri
+  create_package = false
+  package_type   = "Image"
+  architectures  = ["x86_64"]
+
+  environment_variables = {
+    HANDLER              = "cognito-triggers"
+    SECRET_NAME          = format("%s-cognito-secrets-%s", lower(var.product), var.env)
+    OTEL_TRACES_EXPORTER = var.trace_exporter
+    OUTBOX_TABLE         = aws_dynamodb_table.outbox.name
+  }
+
+  use_existing_cloudwatch_log_group = false
+  cloudwatch_logs_retention_in_days = 30
+  cloudwatch_logs_skip_destroy      = false
+  cloudwatch_logs_log_group_class   = "STANDARD"
+}
+
+module "lambda_guest_cleanup" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "~> 7.17.0"
+  count   = var.guest_accounts ? 1 : 0
+
+  function_name  = format("%s-guest-cleanup-%s", var.name, var.env)
+  description    = "Deletes guest accounts that were never upgraded"
+  create_role    = false
+  lambda_role    = data.aws_iam_role.user_auth.arn
+  memory_size    = var.memory_size
+  publish        = true
+  timeout        = 300
+  image_uri      = data.aws_ecr_image.main.image_uri
+  create_package = false
+  package_type   = "Image"
+  architectures  = ["x86_64"]
+
+  environment_variables = {
+    HANDLER              = "guest-cleanup"
+    SECRET_NAME          = format("%s-cognito-secrets-%s", lower(var.product), var.env)
+    OTEL_TRACES_EXPORTER = var.trace_exporter
+    OUTBOX_TABLE         = aws_dynamodb_table.outbox.name
+    GUEST_MAX_AGE        = format("%dh", var.guest_max_age_days * 24)
+  }
+
+  use_existing_cloudwatch_log_group = false
+  cloudwatch_logs_retention_in_days = 30
+  cloudwatch_logs_skip_destroy      = false
+  cloudwatch_logs_log_group_class   = "STANDARD"
+}
+
+resource "aws_lambda_permission" "cognito_triggers" {
+  statement_id  = "AllowCognitoTriggers"
+  action        = "lambda:InvokeFunction"
+  function_name = module.lambda_cognito_triggers.lambda_function_name
+  principal     = "cognito-idp.amazonaws.com"
+  source_arn    = var.user_pool_arn
+}
+
+# Resource: aws_cloudwatch_event_rule
+# https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/cloudwatch_event_rule
+
+resource "aws_cloudwatch_event_rule" "outbox_drain" {
+  name                = format("%s-outbox-drain-%s", var.name, var.env)
+  schedule_expression = "rate(1 minute)"
+}
+
+resource "aws_cloudwatch_event_target" "outbox_drain" {
+  rule = aws_cloudwatch_event_rule.outbox_drain.name
+  arn  = module.lambda_outbox_drain.lambda_function_arn
+}
+
+resource "aws_lambda_permission" "outbox_drain" {
+  statement_id  = "AllowScheduledDrain"
+  action        = "lambda:InvokeFunction"
+  function_name = module.lambda_outbox_drain.lambda_function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.outbox_drain.arn
+}
+
+resource "aws_cloudwatch_event_rule" "guest_cleanup" {
+  count               = var.guest_accounts ? 1 : 0
+  name                = format("%s-guest-cleanup-%s", var.name, var.env)
+  schedule_expression = "rate(1 day)"
+}
+
+resource "aws_cloudwatch_event_target" "guest_cleanup" {
+  count = var.guest_accounts ? 1 : 0
+  rule  = aws_cloudwatch_event_rule.guest_cleanup[0].name
+  arn   = module.lambda_guest_cleanup[0].lambda_function_arn
+}
+
+resource "aws_lambda_permission" "guest_cleanup" {
+  count         = var.guest_accounts ? 1 : 0
+  statement_id  = "AllowScheduledGuestCleanup"
+  action        = "lambda:InvokeFunction"
+  function_name = module.lambda_guest_cleanup[0].lambda_function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.guest_cleanup[0].arn
+}
+
+# Resource: aws_cognito_user_group
+# https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/cognito_user_group
+
+resource "aws_cognito_user_group" "guests" {
+  count        = var.guest_accounts ? 1 : 0
+  name         = "guests"
+  user_pool_id = element(split("/", var.user_pool_arn), 1)
+  description  = "Guest accounts that have not been upgraded yet"
+}
+
 # submodule: alias
 # https://registry.terraform.io/modules/terraform-aws-modules/lambda/aws/latest/submodules/alias
 
@@ -42,6 +42,11 @@ output "lambda_alias_invoke_arn" {
   value       = module.lambda_alias_release.lambda_alias_invoke_arn
 }
 
+output "cognito_triggers_function_arn" {
+  description = "The ARN to attach to the user pool's Pre sign-up, Post confirmation, Pre token generation and User migration triggers"
+  value       = module.lambda_cognito_triggers.lambda_function_arn
+}
+
 ################################################################################
 # CloudWatch Logs
 ################################################################################
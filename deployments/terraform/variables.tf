@@ -55,12 +55,40 @@ variable "timeout" {
   default     = 3
 }
 
+variable "trace_exporter" {
+  type        = string
+  description = "Where to export OpenTelemetry traces. Should be otlp/stdout/none"
+  default     = "none"
+
+  validation {
+    condition     = can(regex("^(otlp|stdout|none)$", var.trace_exporter))
+    error_message = "The trace_exporter should be otlp, stdout or none."
+  }
+}
+
 variable "alias_name" {
   type        = string
   description = "Name for the alias you are creating."
   default     = "release"
 }
 
+variable "user_pool_arn" {
+  type        = string
+  description = "ARN of the Cognito user pool allowed to invoke the trigger function."
+}
+
+variable "guest_accounts" {
+  type        = bool
+  description = "Whether players can create guest accounts. The user pool must allow preferred_username as an alias."
+  default     = false
+}
+
+variable "guest_max_age_days" {
+  type        = number
+  description = "Days a guest account is kept before it is deleted unless upgraded."
+  default     = 30
+}
+
 ################################################################################
 # ECR Image
 ################################################################################
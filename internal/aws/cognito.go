@@ -2,8 +2,8 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log/slog"
 	"strings"
 
 	"github.com/MicahParks/keyfunc/v3"
@@ -12,15 +12,36 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
 	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+	"github.com/whatisusername/toon-tank-user-service/internal/telemetry"
 	"github.com/whatisusername/toon-tank-user-service/internal/token"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const cognitoServiceName = "Cognito"
+
 type CognitoToken struct {
 	IdToken      string
 	AccessToken  string
 	RefreshToken string
 }
 
+// ChallengeNewPasswordRequired is the challenge for a user created by an
+// administrator, e.g. through an import, until they choose their own password.
+const ChallengeNewPasswordRequired = "NEW_PASSWORD_REQUIRED"
+
+// ChallengeError is returned by Login when Cognito answers with a challenge
+// instead of tokens.
+type ChallengeError struct {
+	Name string
+}
+
+func (e *ChallengeError) Error() string {
+	return fmt.Sprintf("sign-in requires the %s challenge", e.Name)
+}
+
 type CognitoUserInfo struct {
 	Username string
 	Email    string
@@ -31,6 +52,11 @@ type CognitoAuthService interface {
 	Login(ctx context.Context, clientId, clientSecret, username, password string) (*CognitoToken, error)
 	ValidateToken(ctx context.Context, userPoolId, tokenString string) (*jwt.Token, error)
 	ParseUserInfo(idToken *jwt.Token) (*CognitoUserInfo, error)
+	ChangePassword(ctx context.Context, accessToken, previousPassword, proposedPassword string) error
+	ForgotPassword(ctx context.Context, clientId, clientSecret, username string) error
+	ConfirmForgotPassword(ctx context.Context, clientId, clientSecret, username, code, password string) error
+	UserExists(ctx context.Context, userPoolId, username string) (bool, error)
+	DeleteUser(ctx context.Context, accessToken string) error
 }
 
 func NewCognitoService(ctx context.Context, optFns ...func(options *config.LoadOptions) error) (*CognitoService, error) {
@@ -39,6 +65,7 @@ func NewCognitoService(ctx context.Context, optFns ...func(options *config.LoadO
 		return nil, err
 	}
 
+	otelaws.AppendMiddlewares(&cfg.APIOptions)
 	client := cognitoidentityprovider.NewFromConfig(cfg)
 
 	return &CognitoService{
@@ -50,10 +77,14 @@ type CognitoService struct {
 	client *cognitoidentityprovider.Client
 }
 
-func (c *CognitoService) SignUp(ctx context.Context, clientId, clientSecret, username, password, email string) error {
-	slog.Info("Signing up user", "username", username)
+func (c *CognitoService) SignUp(ctx context.Context, clientId, clientSecret, username, password, email string) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "SignUp")
+	defer func() { telemetry.EndSpan(span, err) }()
 
-	secretHash, err := token.GenerateBase64HMAC(clientSecret, username+clientId)
+	logger := logging.FromContext(ctx)
+	logger.Info("Signing up user", "username", username)
+
+	hash, err := secretHash(clientId, clientSecret, username)
 	if err != nil {
 		return err
 	}
@@ -62,7 +93,7 @@ func (c *CognitoService) SignUp(ctx context.Context, clientId, clientSecret, use
 		ClientId:   aws.String(clientId),
 		Username:   aws.String(username),
 		Password:   aws.String(password),
-		SecretHash: aws.String(secretHash),
+		SecretHash: hash,
 		UserAttributes: []types.AttributeType{
 			{
 				Name:  aws.String("email"),
@@ -75,34 +106,45 @@ func (c *CognitoService) SignUp(ctx context.Context, clientId, clientSecret, use
 		return err
 	}
 
-	slog.Info("Created user", "username", username)
+	logger.Info("Created user", "username", username)
 
 	return nil
 }
 
-func (c *CognitoService) Login(ctx context.Context, clientId, clientSecret, username, password string) (*CognitoToken, error) {
-	slog.Info("Logging in user", "username", username)
+func (c *CognitoService) Login(ctx context.Context, clientId, clientSecret, username, password string) (_ *CognitoToken, err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "Login")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	logger := logging.FromContext(ctx)
+	logger.Info("Logging in user", "username", username)
 
-	secretHash, err := token.GenerateBase64HMAC(clientSecret, username+clientId)
+	hash, err := secretHash(clientId, clientSecret, username)
 	if err != nil {
 		return nil, err
 	}
 
+	authParameters := map[string]string{
+		"USERNAME": username,
+		"PASSWORD": password,
+	}
+	if hash != nil {
+		authParameters["SECRET_HASH"] = *hash
+	}
+
 	output, err := c.client.InitiateAuth(ctx, &cognitoidentityprovider.InitiateAuthInput{
-		AuthFlow: types.AuthFlowTypeUserPasswordAuth,
-		ClientId: aws.String(clientId),
-		AuthParameters: map[string]string{
-			"USERNAME":    username,
-			"PASSWORD":    password,
-			"SECRET_HASH": secretHash,
-		},
+		AuthFlow:       types.AuthFlowTypeUserPasswordAuth,
+		ClientId:       aws.String(clientId),
+		AuthParameters: authParameters,
 	})
 
 	if err != nil {
 		return nil, err
 	}
+	if output.AuthenticationResult == nil {
+		return nil, &ChallengeError{Name: string(output.ChallengeName)}
+	}
 
-	slog.Info("Logged in user", "token type", *output.AuthenticationResult.TokenType, "expires in", output.AuthenticationResult.ExpiresIn)
+	logger.Info("Logged in user", "token type", *output.AuthenticationResult.TokenType, "expires in", output.AuthenticationResult.ExpiresIn)
 
 	return &CognitoToken{
 		IdToken:      *output.AuthenticationResult.IdToken,
@@ -111,10 +153,16 @@ func (c *CognitoService) Login(ctx context.Context, clientId, clientSecret, user
 	}, nil
 }
 
-func (c *CognitoService) ValidateToken(ctx context.Context, userPoolId, tokenString string) (*jwt.Token, error) {
+func (c *CognitoService) ValidateToken(ctx context.Context, userPoolId, tokenString string) (_ *jwt.Token, err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "ValidateToken")
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	region := strings.Split(userPoolId, "_")[0]
 	jwkUrl := fmt.Sprintf("https://cognito-idp.%s.amazonaws.com/%s/.well-known/jwks.json", region, userPoolId)
+
+	_, jwksSpan := tracer.Start(ctx, "JWKS.Fetch", trace.WithAttributes(semconv.URLFull(jwkUrl)))
 	k, err := keyfunc.NewDefault([]string{jwkUrl})
+	telemetry.EndSpan(jwksSpan, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load JWK: %w", err)
 	}
@@ -163,3 +211,122 @@ func (c *CognitoService) ParseUserInfo(idToken *jwt.Token) (*CognitoUserInfo, er
 		Email:    email,
 	}, nil
 }
+
+func (c *CognitoService) ChangePassword(ctx context.Context, accessToken, previousPassword, proposedPassword string) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "ChangePassword")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	_, err = c.client.ChangePassword(ctx, &cognitoidentityprovider.ChangePasswordInput{
+		AccessToken:      aws.String(accessToken),
+		PreviousPassword: aws.String(previousPassword),
+		ProposedPassword: aws.String(proposedPassword),
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("Changed password")
+
+	return nil
+}
+
+func (c *CognitoService) ForgotPassword(ctx context.Context, clientId, clientSecret, username string) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "ForgotPassword")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	hash, err := secretHash(clientId, clientSecret, username)
+	if err != nil {
+		return err
+	}
+
+	output, err := c.client.ForgotPassword(ctx, &cognitoidentityprovider.ForgotPasswordInput{
+		ClientId:   aws.String(clientId),
+		Username:   aws.String(username),
+		SecretHash: hash,
+	})
+	if err != nil {
+		return err
+	}
+
+	if output.CodeDeliveryDetails != nil {
+		logging.FromContext(ctx).Info("Sent password reset code", "username", username, "medium", output.CodeDeliveryDetails.DeliveryMedium)
+	}
+
+	return nil
+}
+
+func (c *CognitoService) ConfirmForgotPassword(ctx context.Context, clientId, clientSecret, username, code, password string) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "ConfirmForgotPassword")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	hash, err := secretHash(clientId, clientSecret, username)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.client.ConfirmForgotPassword(ctx, &cognitoidentityprovider.ConfirmForgotPasswordInput{
+		ClientId:         aws.String(clientId),
+		Username:         aws.String(username),
+		ConfirmationCode: aws.String(code),
+		Password:         aws.String(password),
+		SecretHash:       hash,
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("Reset password", "username", username)
+
+	return nil
+}
+
+func (c *CognitoService) UserExists(ctx context.Context, userPoolId, username string) (_ bool, err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "AdminGetUser")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	_, err = c.client.AdminGetUser(ctx, &cognitoidentityprovider.AdminGetUserInput{
+		UserPoolId: aws.String(userPoolId),
+		Username:   aws.String(username),
+	})
+
+	var notFound *types.UserNotFoundException
+	if errors.As(err, &notFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (c *CognitoService) DeleteUser(ctx context.Context, accessToken string) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "DeleteUser")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	_, err = c.client.DeleteUser(ctx, &cognitoidentityprovider.DeleteUserInput{
+		AccessToken: aws.String(accessToken),
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("Deleted user")
+
+	return nil
+}
+
+// secretHash returns nil for public app clients, which are created without a
+// client secret and reject requests that carry a SECRET_HASH.
+func secretHash(clientId, clientSecret, username string) (*string, error) {
+	if clientSecret == "" {
+		return nil, nil
+	}
+
+	hash, err := token.GenerateBase64HMAC(clientSecret, username+clientId)
+	if err != nil {
+		return nil, err
+	}
+
+	return aws.String(hash), nil
+}
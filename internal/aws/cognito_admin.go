@@ -0,0 +1,310 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
+	"github.com/whatisusername/toon-tank-user-service/internal/telemetry"
+)
+
+// CognitoUser is a user as the user pool admin APIs report it.
+type CognitoUser struct {
+	Username   string            `json:"username"`
+	Status     string            `json:"status"`
+	Enabled    bool              `json:"enabled"`
+	Created    time.Time         `json:"created"`
+	Modified   time.Time         `json:"modified"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// NewCognitoUser describes a user to create on a player's behalf. Without a
+// TemporaryPassword Cognito generates one; SuppressMessage skips the
+// invitation that would carry it.
+type NewCognitoUser struct {
+	Username          string
+	Attributes        map[string]string
+	TemporaryPassword string
+	SuppressMessage   bool
+}
+
+// UserStatusExternalProvider is the status of users created by a federated
+// sign-in. They have no password of their own.
+const UserStatusExternalProvider = "EXTERNAL_PROVIDER"
+
+// CognitoIdentity is an external identity provider account that signs in as a
+// user, as listed in the user's identities attribute.
+type CognitoIdentity struct {
+	UserID       string `json:"userId"`
+	ProviderName string `json:"providerName"`
+	ProviderType string `json:"providerType"`
+	Primary      bool   `json:"primary"`
+	DateCreated  int64  `json:"dateCreated"`
+}
+
+// Identities decodes the user's identities attribute. Users that never signed
+// in through a provider have none.
+func (u CognitoUser) Identities() ([]CognitoIdentity, error) {
+	raw, ok := u.Attributes["identities"]
+	if !ok || raw == "" {
+		return nil, nil
+	}
+
+	var identities []CognitoIdentity
+	if err := json.Unmarshal([]byte(raw), &identities); err != nil {
+		return nil, fmt.Errorf("failed to decode identities: %w", err)
+	}
+	return identities, nil
+}
+
+// CognitoUserAdmin is the operator side of the user pool. Tooling uses it, and
+// so do the API routes that act on the caller's own user, such as guest
+// upgrades and identity linking; those routes must check that the change is
+// one the caller may make before calling it.
+type CognitoUserAdmin interface {
+	ListUsers(ctx context.Context, userPoolId string, fn func(CognitoUser) error) error
+	AdminCreateUser(ctx context.Context, userPoolId string, user NewCognitoUser) error
+	AdminDeleteUser(ctx context.Context, userPoolId, username string) error
+	AdminSetUserPassword(ctx context.Context, userPoolId, username, password string, permanent bool) error
+	AdminUpdateUserAttributes(ctx context.Context, userPoolId, username string, attributes map[string]string) error
+	AdminDeleteUserAttributes(ctx context.Context, userPoolId, username string, names ...string) error
+	AdminAddUserToGroup(ctx context.Context, userPoolId, username, group string) error
+	AdminRemoveUserFromGroup(ctx context.Context, userPoolId, username, group string) error
+	ListUsersInGroup(ctx context.Context, userPoolId, group string, fn func(CognitoUser) error) error
+	AdminGetUser(ctx context.Context, userPoolId, username string) (CognitoUser, error)
+	AdminLinkProviderForUser(ctx context.Context, userPoolId, username string, identity CognitoIdentity) error
+	AdminDisableProviderForUser(ctx context.Context, userPoolId string, identity CognitoIdentity) error
+}
+
+// ListUsers calls fn for every user in the pool, one page at a time, and stops
+// at the first error fn returns.
+func (c *CognitoService) ListUsers(ctx context.Context, userPoolId string, fn func(CognitoUser) error) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "ListUsers")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	paginator := cognitoidentityprovider.NewListUsersPaginator(c.client, &cognitoidentityprovider.ListUsersInput{
+		UserPoolId: aws.String(userPoolId),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range page.Users {
+			if err = fn(cognitoUser(u)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *CognitoService) AdminCreateUser(ctx context.Context, userPoolId string, user NewCognitoUser) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "AdminCreateUser")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	input := &cognitoidentityprovider.AdminCreateUserInput{
+		UserPoolId: aws.String(userPoolId),
+		Username:   aws.String(user.Username),
+	}
+	for name, value := range user.Attributes {
+		input.UserAttributes = append(input.UserAttributes, types.AttributeType{Name: aws.String(name), Value: aws.String(value)})
+	}
+	if user.TemporaryPassword != "" {
+		input.TemporaryPassword = aws.String(user.TemporaryPassword)
+	}
+	if user.SuppressMessage {
+		input.MessageAction = types.MessageActionTypeSuppress
+	}
+
+	_, err = c.client.AdminCreateUser(ctx, input)
+	return err
+}
+
+func (c *CognitoService) AdminDeleteUser(ctx context.Context, userPoolId, username string) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "AdminDeleteUser")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	_, err = c.client.AdminDeleteUser(ctx, &cognitoidentityprovider.AdminDeleteUserInput{
+		UserPoolId: aws.String(userPoolId),
+		Username:   aws.String(username),
+	})
+	return err
+}
+
+// AdminSetUserPassword sets the user's password without knowing the old one.
+// A permanent password confirms the user; otherwise they must change it at
+// their next sign-in.
+func (c *CognitoService) AdminSetUserPassword(ctx context.Context, userPoolId, username, password string, permanent bool) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "AdminSetUserPassword")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	_, err = c.client.AdminSetUserPassword(ctx, &cognitoidentityprovider.AdminSetUserPasswordInput{
+		UserPoolId: aws.String(userPoolId),
+		Username:   aws.String(username),
+		Password:   aws.String(password),
+		Permanent:  permanent,
+	})
+	return err
+}
+
+func (c *CognitoService) AdminUpdateUserAttributes(ctx context.Context, userPoolId, username string, attributes map[string]string) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "AdminUpdateUserAttributes")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	input := &cognitoidentityprovider.AdminUpdateUserAttributesInput{
+		UserPoolId: aws.String(userPoolId),
+		Username:   aws.String(username),
+	}
+	for name, value := range attributes {
+		input.UserAttributes = append(input.UserAttributes, types.AttributeType{Name: aws.String(name), Value: aws.String(value)})
+	}
+
+	_, err = c.client.AdminUpdateUserAttributes(ctx, input)
+	return err
+}
+
+func (c *CognitoService) AdminDeleteUserAttributes(ctx context.Context, userPoolId, username string, names ...string) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "AdminDeleteUserAttributes")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	_, err = c.client.AdminDeleteUserAttributes(ctx, &cognitoidentityprovider.AdminDeleteUserAttributesInput{
+		UserPoolId:         aws.String(userPoolId),
+		Username:           aws.String(username),
+		UserAttributeNames: names,
+	})
+	return err
+}
+
+func (c *CognitoService) AdminAddUserToGroup(ctx context.Context, userPoolId, username, group string) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "AdminAddUserToGroup")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	_, err = c.client.AdminAddUserToGroup(ctx, &cognitoidentityprovider.AdminAddUserToGroupInput{
+		UserPoolId: aws.String(userPoolId),
+		Username:   aws.String(username),
+		GroupName:  aws.String(group),
+	})
+	return err
+}
+
+func (c *CognitoService) AdminRemoveUserFromGroup(ctx context.Context, userPoolId, username, group string) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "AdminRemoveUserFromGroup")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	_, err = c.client.AdminRemoveUserFromGroup(ctx, &cognitoidentityprovider.AdminRemoveUserFromGroupInput{
+		UserPoolId: aws.String(userPoolId),
+		Username:   aws.String(username),
+		GroupName:  aws.String(group),
+	})
+	return err
+}
+
+// ListUsersInGroup calls fn for every member of the group, one page at a time,
+// and stops at the first error fn returns.
+func (c *CognitoService) ListUsersInGroup(ctx context.Context, userPoolId, group string, fn func(CognitoUser) error) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "ListUsersInGroup")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	paginator := cognitoidentityprovider.NewListUsersInGroupPaginator(c.client, &cognitoidentityprovider.ListUsersInGroupInput{
+		UserPoolId: aws.String(userPoolId),
+		GroupName:  aws.String(group),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range page.Users {
+			if err = fn(cognitoUser(u)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *CognitoService) AdminGetUser(ctx context.Context, userPoolId, username string) (user CognitoUser, err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "AdminGetUser")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	out, err := c.client.AdminGetUser(ctx, &cognitoidentityprovider.AdminGetUserInput{
+		UserPoolId: aws.String(userPoolId),
+		Username:   aws.String(username),
+	})
+	if err != nil {
+		return CognitoUser{}, err
+	}
+
+	return cognitoUser(types.UserType{
+		Username:             out.Username,
+		UserStatus:           out.UserStatus,
+		Enabled:              out.Enabled,
+		UserCreateDate:       out.UserCreateDate,
+		UserLastModifiedDate: out.UserLastModifiedDate,
+		Attributes:           out.UserAttributes,
+	}), nil
+}
+
+// AdminLinkProviderForUser lets identity sign in as the existing user. The
+// provider account must not have a user of its own yet.
+func (c *CognitoService) AdminLinkProviderForUser(ctx context.Context, userPoolId, username string, identity CognitoIdentity) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "AdminLinkProviderForUser")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	_, err = c.client.AdminLinkProviderForUser(ctx, &cognitoidentityprovider.AdminLinkProviderForUserInput{
+		UserPoolId: aws.String(userPoolId),
+		DestinationUser: &types.ProviderUserIdentifierType{
+			ProviderName:           aws.String("Cognito"),
+			ProviderAttributeValue: aws.String(username),
+		},
+		SourceUser: providerUser(identity),
+	})
+	return err
+}
+
+// AdminDisableProviderForUser unlinks identity from whichever user it signs in
+// as.
+func (c *CognitoService) AdminDisableProviderForUser(ctx context.Context, userPoolId string, identity CognitoIdentity) (err error) {
+	ctx, span := startSpan(ctx, cognitoServiceName, "AdminDisableProviderForUser")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	_, err = c.client.AdminDisableProviderForUser(ctx, &cognitoidentityprovider.AdminDisableProviderForUserInput{
+		UserPoolId: aws.String(userPoolId),
+		User:       providerUser(identity),
+	})
+	return err
+}
+
+// providerUser identifies a provider account by its subject, which every
+// provider type supports.
+func providerUser(identity CognitoIdentity) *types.ProviderUserIdentifierType {
+	return &types.ProviderUserIdentifierType{
+		ProviderName:           aws.String(identity.ProviderName),
+		ProviderAttributeName:  aws.String("Cognito_Subject"),
+		ProviderAttributeValue: aws.String(identity.UserID),
+	}
+}
+
+func cognitoUser(u types.UserType) CognitoUser {
+	user := CognitoUser{
+		Username:   aws.ToString(u.Username),
+		Status:     string(u.UserStatus),
+		Enabled:    u.Enabled,
+		Created:    aws.ToTime(u.UserCreateDate),
+		Modified:   aws.ToTime(u.UserLastModifiedDate),
+		Attributes: make(map[string]string, len(u.Attributes)),
+	}
+	for _, a := range u.Attributes {
+		user.Attributes[aws.ToString(a.Name)] = aws.ToString(a.Value)
+	}
+	return user
+}
@@ -0,0 +1,644 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package aws
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockCognitoUserAdmin is an autogenerated mock type for the CognitoUserAdmin type
+type MockCognitoUserAdmin struct {
+	mock.Mock
+}
+
+type MockCognitoUserAdmin_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCognitoUserAdmin) EXPECT() *MockCognitoUserAdmin_Expecter {
+	return &MockCognitoUserAdmin_Expecter{mock: &_m.Mock}
+}
+
+// AdminAddUserToGroup provides a mock function with given fields: ctx, userPoolId, username, group
+func (_m *MockCognitoUserAdmin) AdminAddUserToGroup(ctx context.Context, userPoolId string, username string, group string) error {
+	ret := _m.Called(ctx, userPoolId, username, group)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminAddUserToGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, userPoolId, username, group)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCognitoUserAdmin_AdminAddUserToGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdminAddUserToGroup'
+type MockCognitoUserAdmin_AdminAddUserToGroup_Call struct {
+	*mock.Call
+}
+
+// AdminAddUserToGroup is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userPoolId string
+//   - username string
+//   - group string
+func (_e *MockCognitoUserAdmin_Expecter) AdminAddUserToGroup(ctx interface{}, userPoolId interface{}, username interface{}, group interface{}) *MockCognitoUserAdmin_AdminAddUserToGroup_Call {
+	return &MockCognitoUserAdmin_AdminAddUserToGroup_Call{Call: _e.mock.On("AdminAddUserToGroup", ctx, userPoolId, username, group)}
+}
+
+func (_c *MockCognitoUserAdmin_AdminAddUserToGroup_Call) Run(run func(ctx context.Context, userPoolId string, username string, group string)) *MockCognitoUserAdmin_AdminAddUserToGroup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminAddUserToGroup_Call) Return(_a0 error) *MockCognitoUserAdmin_AdminAddUserToGroup_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminAddUserToGroup_Call) RunAndReturn(run func(context.Context, string, string, string) error) *MockCognitoUserAdmin_AdminAddUserToGroup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AdminCreateUser provides a mock function with given fields: ctx, userPoolId, user
+func (_m *MockCognitoUserAdmin) AdminCreateUser(ctx context.Context, userPoolId string, user NewCognitoUser) error {
+	ret := _m.Called(ctx, userPoolId, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminCreateUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, NewCognitoUser) error); ok {
+		r0 = rf(ctx, userPoolId, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCognitoUserAdmin_AdminCreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdminCreateUser'
+type MockCognitoUserAdmin_AdminCreateUser_Call struct {
+	*mock.Call
+}
+
+// AdminCreateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userPoolId string
+//   - user NewCognitoUser
+func (_e *MockCognitoUserAdmin_Expecter) AdminCreateUser(ctx interface{}, userPoolId interface{}, user interface{}) *MockCognitoUserAdmin_AdminCreateUser_Call {
+	return &MockCognitoUserAdmin_AdminCreateUser_Call{Call: _e.mock.On("AdminCreateUser", ctx, userPoolId, user)}
+}
+
+func (_c *MockCognitoUserAdmin_AdminCreateUser_Call) Run(run func(ctx context.Context, userPoolId string, user NewCognitoUser)) *MockCognitoUserAdmin_AdminCreateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(NewCognitoUser))
+	})
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminCreateUser_Call) Return(_a0 error) *MockCognitoUserAdmin_AdminCreateUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminCreateUser_Call) RunAndReturn(run func(context.Context, string, NewCognitoUser) error) *MockCognitoUserAdmin_AdminCreateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AdminDeleteUser provides a mock function with given fields: ctx, userPoolId, username
+func (_m *MockCognitoUserAdmin) AdminDeleteUser(ctx context.Context, userPoolId string, username string) error {
+	ret := _m.Called(ctx, userPoolId, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminDeleteUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userPoolId, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCognitoUserAdmin_AdminDeleteUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdminDeleteUser'
+type MockCognitoUserAdmin_AdminDeleteUser_Call struct {
+	*mock.Call
+}
+
+// AdminDeleteUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userPoolId string
+//   - username string
+func (_e *MockCognitoUserAdmin_Expecter) AdminDeleteUser(ctx interface{}, userPoolId interface{}, username interface{}) *MockCognitoUserAdmin_AdminDeleteUser_Call {
+	return &MockCognitoUserAdmin_AdminDeleteUser_Call{Call: _e.mock.On("AdminDeleteUser", ctx, userPoolId, username)}
+}
+
+func (_c *MockCognitoUserAdmin_AdminDeleteUser_Call) Run(run func(ctx context.Context, userPoolId string, username string)) *MockCognitoUserAdmin_AdminDeleteUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminDeleteUser_Call) Return(_a0 error) *MockCognitoUserAdmin_AdminDeleteUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminDeleteUser_Call) RunAndReturn(run func(context.Context, string, string) error) *MockCognitoUserAdmin_AdminDeleteUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AdminDeleteUserAttributes provides a mock function with given fields: ctx, userPoolId, username, names
+func (_m *MockCognitoUserAdmin) AdminDeleteUserAttributes(ctx context.Context, userPoolId string, username string, names ...string) error {
+	_va := make([]interface{}, len(names))
+	for _i := range names {
+		_va[_i] = names[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, userPoolId, username)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminDeleteUserAttributes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ...string) error); ok {
+		r0 = rf(ctx, userPoolId, username, names...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCognitoUserAdmin_AdminDeleteUserAttributes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdminDeleteUserAttributes'
+type MockCognitoUserAdmin_AdminDeleteUserAttributes_Call struct {
+	*mock.Call
+}
+
+// AdminDeleteUserAttributes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userPoolId string
+//   - username string
+//   - names ...string
+func (_e *MockCognitoUserAdmin_Expecter) AdminDeleteUserAttributes(ctx interface{}, userPoolId interface{}, username interface{}, names ...interface{}) *MockCognitoUserAdmin_AdminDeleteUserAttributes_Call {
+	return &MockCognitoUserAdmin_AdminDeleteUserAttributes_Call{Call: _e.mock.On("AdminDeleteUserAttributes",
+		append([]interface{}{ctx, userPoolId, username}, names...)...)}
+}
+
+func (_c *MockCognitoUserAdmin_AdminDeleteUserAttributes_Call) Run(run func(ctx context.Context, userPoolId string, username string, names ...string)) *MockCognitoUserAdmin_AdminDeleteUserAttributes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]string, len(args)-3)
+		for i, a := range args[3:] {
+			if a != nil {
+				variadicArgs[i] = a.(string)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), args[2].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminDeleteUserAttributes_Call) Return(_a0 error) *MockCognitoUserAdmin_AdminDeleteUserAttributes_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminDeleteUserAttributes_Call) RunAndReturn(run func(context.Context, string, string, ...string) error) *MockCognitoUserAdmin_AdminDeleteUserAttributes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AdminDisableProviderForUser provides a mock function with given fields: ctx, userPoolId, identity
+func (_m *MockCognitoUserAdmin) AdminDisableProviderForUser(ctx context.Context, userPoolId string, identity CognitoIdentity) error {
+	ret := _m.Called(ctx, userPoolId, identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminDisableProviderForUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, CognitoIdentity) error); ok {
+		r0 = rf(ctx, userPoolId, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCognitoUserAdmin_AdminDisableProviderForUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdminDisableProviderForUser'
+type MockCognitoUserAdmin_AdminDisableProviderForUser_Call struct {
+	*mock.Call
+}
+
+// AdminDisableProviderForUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userPoolId string
+//   - identity CognitoIdentity
+func (_e *MockCognitoUserAdmin_Expecter) AdminDisableProviderForUser(ctx interface{}, userPoolId interface{}, identity interface{}) *MockCognitoUserAdmin_AdminDisableProviderForUser_Call {
+	return &MockCognitoUserAdmin_AdminDisableProviderForUser_Call{Call: _e.mock.On("AdminDisableProviderForUser", ctx, userPoolId, identity)}
+}
+
+func (_c *MockCognitoUserAdmin_AdminDisableProviderForUser_Call) Run(run func(ctx context.Context, userPoolId string, identity CognitoIdentity)) *MockCognitoUserAdmin_AdminDisableProviderForUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(CognitoIdentity))
+	})
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminDisableProviderForUser_Call) Return(_a0 error) *MockCognitoUserAdmin_AdminDisableProviderForUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminDisableProviderForUser_Call) RunAndReturn(run func(context.Context, string, CognitoIdentity) error) *MockCognitoUserAdmin_AdminDisableProviderForUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AdminGetUser provides a mock function with given fields: ctx, userPoolId, username
+func (_m *MockCognitoUserAdmin) AdminGetUser(ctx context.Context, userPoolId string, username string) (CognitoUser, error) {
+	ret := _m.Called(ctx, userPoolId, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminGetUser")
+	}
+
+	var r0 CognitoUser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (CognitoUser, error)); ok {
+		return rf(ctx, userPoolId, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) CognitoUser); ok {
+		r0 = rf(ctx, userPoolId, username)
+	} else {
+		r0 = ret.Get(0).(CognitoUser)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userPoolId, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCognitoUserAdmin_AdminGetUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdminGetUser'
+type MockCognitoUserAdmin_AdminGetUser_Call struct {
+	*mock.Call
+}
+
+// AdminGetUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userPoolId string
+//   - username string
+func (_e *MockCognitoUserAdmin_Expecter) AdminGetUser(ctx interface{}, userPoolId interface{}, username interface{}) *MockCognitoUserAdmin_AdminGetUser_Call {
+	return &MockCognitoUserAdmin_AdminGetUser_Call{Call: _e.mock.On("AdminGetUser", ctx, userPoolId, username)}
+}
+
+func (_c *MockCognitoUserAdmin_AdminGetUser_Call) Run(run func(ctx context.Context, userPoolId string, username string)) *MockCognitoUserAdmin_AdminGetUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminGetUser_Call) Return(_a0 CognitoUser, _a1 error) *MockCognitoUserAdmin_AdminGetUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminGetUser_Call) RunAndReturn(run func(context.Context, string, string) (CognitoUser, error)) *MockCognitoUserAdmin_AdminGetUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AdminLinkProviderForUser provides a mock function with given fields: ctx, userPoolId, username, identity
+func (_m *MockCognitoUserAdmin) AdminLinkProviderForUser(ctx context.Context, userPoolId string, username string, identity CognitoIdentity) error {
+	ret := _m.Called(ctx, userPoolId, username, identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminLinkProviderForUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, CognitoIdentity) error); ok {
+		r0 = rf(ctx, userPoolId, username, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCognitoUserAdmin_AdminLinkProviderForUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdminLinkProviderForUser'
+type MockCognitoUserAdmin_AdminLinkProviderForUser_Call struct {
+	*mock.Call
+}
+
+// AdminLinkProviderForUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userPoolId string
+//   - username string
+//   - identity CognitoIdentity
+func (_e *MockCognitoUserAdmin_Expecter) AdminLinkProviderForUser(ctx interface{}, userPoolId interface{}, username interface{}, identity interface{}) *MockCognitoUserAdmin_AdminLinkProviderForUser_Call {
+	return &MockCognitoUserAdmin_AdminLinkProviderForUser_Call{Call: _e.mock.On("AdminLinkProviderForUser", ctx, userPoolId, username, identity)}
+}
+
+func (_c *MockCognitoUserAdmin_AdminLinkProviderForUser_Call) Run(run func(ctx context.Context, userPoolId string, username string, identity CognitoIdentity)) *MockCognitoUserAdmin_AdminLinkProviderForUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(CognitoIdentity))
+	})
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminLinkProviderForUser_Call) Return(_a0 error) *MockCognitoUserAdmin_AdminLinkProviderForUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminLinkProviderForUser_Call) RunAndReturn(run func(context.Context, string, string, CognitoIdentity) error) *MockCognitoUserAdmin_AdminLinkProviderForUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AdminRemoveUserFromGroup provides a mock function with given fields: ctx, userPoolId, username, group
+func (_m *MockCognitoUserAdmin) AdminRemoveUserFromGroup(ctx context.Context, userPoolId string, username string, group string) error {
+	ret := _m.Called(ctx, userPoolId, username, group)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminRemoveUserFromGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, userPoolId, username, group)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCognitoUserAdmin_AdminRemoveUserFromGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdminRemoveUserFromGroup'
+type MockCognitoUserAdmin_AdminRemoveUserFromGroup_Call struct {
+	*mock.Call
+}
+
+// AdminRemoveUserFromGroup is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userPoolId string
+//   - username string
+//   - group string
+func (_e *MockCognitoUserAdmin_Expecter) AdminRemoveUserFromGroup(ctx interface{}, userPoolId interface{}, username interface{}, group interface{}) *MockCognitoUserAdmin_AdminRemoveUserFromGroup_Call {
+	return &MockCognitoUserAdmin_AdminRemoveUserFromGroup_Call{Call: _e.mock.On("AdminRemoveUserFromGroup", ctx, userPoolId, username, group)}
+}
+
+func (_c *MockCognitoUserAdmin_AdminRemoveUserFromGroup_Call) Run(run func(ctx context.Context, userPoolId string, username string, group string)) *MockCognitoUserAdmin_AdminRemoveUserFromGroup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminRemoveUserFromGroup_Call) Return(_a0 error) *MockCognitoUserAdmin_AdminRemoveUserFromGroup_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminRemoveUserFromGroup_Call) RunAndReturn(run func(context.Context, string, string, string) error) *MockCognitoUserAdmin_AdminRemoveUserFromGroup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AdminSetUserPassword provides a mock function with given fields: ctx, userPoolId, username, password, permanent
+func (_m *MockCognitoUserAdmin) AdminSetUserPassword(ctx context.Context, userPoolId string, username string, password string, permanent bool) error {
+	ret := _m.Called(ctx, userPoolId, username, password, permanent)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminSetUserPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool) error); ok {
+		r0 = rf(ctx, userPoolId, username, password, permanent)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCognitoUserAdmin_AdminSetUserPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdminSetUserPassword'
+type MockCognitoUserAdmin_AdminSetUserPassword_Call struct {
+	*mock.Call
+}
+
+// AdminSetUserPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userPoolId string
+//   - username string
+//   - password string
+//   - permanent bool
+func (_e *MockCognitoUserAdmin_Expecter) AdminSetUserPassword(ctx interface{}, userPoolId interface{}, username interface{}, password interface{}, permanent interface{}) *MockCognitoUserAdmin_AdminSetUserPassword_Call {
+	return &MockCognitoUserAdmin_AdminSetUserPassword_Call{Call: _e.mock.On("AdminSetUserPassword", ctx, userPoolId, username, password, permanent)}
+}
+
+func (_c *MockCognitoUserAdmin_AdminSetUserPassword_Call) Run(run func(ctx context.Context, userPoolId string, username string, password string, permanent bool)) *MockCognitoUserAdmin_AdminSetUserPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(bool))
+	})
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminSetUserPassword_Call) Return(_a0 error) *MockCognitoUserAdmin_AdminSetUserPassword_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminSetUserPassword_Call) RunAndReturn(run func(context.Context, string, string, string, bool) error) *MockCognitoUserAdmin_AdminSetUserPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AdminUpdateUserAttributes provides a mock function with given fields: ctx, userPoolId, username, attributes
+func (_m *MockCognitoUserAdmin) AdminUpdateUserAttributes(ctx context.Context, userPoolId string, username string, attributes map[string]string) error {
+	ret := _m.Called(ctx, userPoolId, username, attributes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdminUpdateUserAttributes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, map[string]string) error); ok {
+		r0 = rf(ctx, userPoolId, username, attributes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCognitoUserAdmin_AdminUpdateUserAttributes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdminUpdateUserAttributes'
+type MockCognitoUserAdmin_AdminUpdateUserAttributes_Call struct {
+	*mock.Call
+}
+
+// AdminUpdateUserAttributes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userPoolId string
+//   - username string
+//   - attributes map[string]string
+func (_e *MockCognitoUserAdmin_Expecter) AdminUpdateUserAttributes(ctx interface{}, userPoolId interface{}, username interface{}, attributes interface{}) *MockCognitoUserAdmin_AdminUpdateUserAttributes_Call {
+	return &MockCognitoUserAdmin_AdminUpdateUserAttributes_Call{Call: _e.mock.On("AdminUpdateUserAttributes", ctx, userPoolId, username, attributes)}
+}
+
+func (_c *MockCognitoUserAdmin_AdminUpdateUserAttributes_Call) Run(run func(ctx context.Context, userPoolId string, username string, attributes map[string]string)) *MockCognitoUserAdmin_AdminUpdateUserAttributes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(map[string]string))
+	})
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminUpdateUserAttributes_Call) Return(_a0 error) *MockCognitoUserAdmin_AdminUpdateUserAttributes_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_AdminUpdateUserAttributes_Call) RunAndReturn(run func(context.Context, string, string, map[string]string) error) *MockCognitoUserAdmin_AdminUpdateUserAttributes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListUsers provides a mock function with given fields: ctx, userPoolId, fn
+func (_m *MockCognitoUserAdmin) ListUsers(ctx context.Context, userPoolId string, fn func(CognitoUser) error) error {
+	ret := _m.Called(ctx, userPoolId, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, func(CognitoUser) error) error); ok {
+		r0 = rf(ctx, userPoolId, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCognitoUserAdmin_ListUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUsers'
+type MockCognitoUserAdmin_ListUsers_Call struct {
+	*mock.Call
+}
+
+// ListUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userPoolId string
+//   - fn func(CognitoUser) error
+func (_e *MockCognitoUserAdmin_Expecter) ListUsers(ctx interface{}, userPoolId interface{}, fn interface{}) *MockCognitoUserAdmin_ListUsers_Call {
+	return &MockCognitoUserAdmin_ListUsers_Call{Call: _e.mock.On("ListUsers", ctx, userPoolId, fn)}
+}
+
+func (_c *MockCognitoUserAdmin_ListUsers_Call) Run(run func(ctx context.Context, userPoolId string, fn func(CognitoUser) error)) *MockCognitoUserAdmin_ListUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(func(CognitoUser) error))
+	})
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_ListUsers_Call) Return(_a0 error) *MockCognitoUserAdmin_ListUsers_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_ListUsers_Call) RunAndReturn(run func(context.Context, string, func(CognitoUser) error) error) *MockCognitoUserAdmin_ListUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListUsersInGroup provides a mock function with given fields: ctx, userPoolId, group, fn
+func (_m *MockCognitoUserAdmin) ListUsersInGroup(ctx context.Context, userPoolId string, group string, fn func(CognitoUser) error) error {
+	ret := _m.Called(ctx, userPoolId, group, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsersInGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, func(CognitoUser) error) error); ok {
+		r0 = rf(ctx, userPoolId, group, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCognitoUserAdmin_ListUsersInGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUsersInGroup'
+type MockCognitoUserAdmin_ListUsersInGroup_Call struct {
+	*mock.Call
+}
+
+// ListUsersInGroup is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userPoolId string
+//   - group string
+//   - fn func(CognitoUser) error
+func (_e *MockCognitoUserAdmin_Expecter) ListUsersInGroup(ctx interface{}, userPoolId interface{}, group interface{}, fn interface{}) *MockCognitoUserAdmin_ListUsersInGroup_Call {
+	return &MockCognitoUserAdmin_ListUsersInGroup_Call{Call: _e.mock.On("ListUsersInGroup", ctx, userPoolId, group, fn)}
+}
+
+func (_c *MockCognitoUserAdmin_ListUsersInGroup_Call) Run(run func(ctx context.Context, userPoolId string, group string, fn func(CognitoUser) error)) *MockCognitoUserAdmin_ListUsersInGroup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(func(CognitoUser) error))
+	})
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_ListUsersInGroup_Call) Return(_a0 error) *MockCognitoUserAdmin_ListUsersInGroup_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCognitoUserAdmin_ListUsersInGroup_Call) RunAndReturn(run func(context.Context, string, string, func(CognitoUser) error) error) *MockCognitoUserAdmin_ListUsersInGroup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockCognitoUserAdmin creates a new instance of MockCognitoUserAdmin. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCognitoUserAdmin(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCognitoUserAdmin {
+	mock := &MockCognitoUserAdmin{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,159 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCognitoService_ListUsers(t *testing.T) {
+	svc := newTestCognitoService(t, func(target string, body map[string]interface{}) interface{} {
+		assert.Equal(t, "AWSCognitoIdentityProviderService.ListUsers", target)
+		assert.Equal(t, "us-east-1_example", body["UserPoolId"])
+
+		if body["PaginationToken"] == nil {
+			return map[string]interface{}{
+				"Users": []interface{}{map[string]interface{}{
+					"Username":       "first",
+					"UserStatus":     "CONFIRMED",
+					"Enabled":        true,
+					"UserCreateDate": 1704067200,
+					"Attributes":     []interface{}{map[string]string{"Name": "email", "Value": "first@example.com"}},
+				}},
+				"PaginationToken": "fake_token",
+			}
+		}
+		assert.Equal(t, "fake_token", body["PaginationToken"])
+		return map[string]interface{}{
+			"Users": []interface{}{map[string]interface{}{
+				"Username":   "second",
+				"UserStatus": "FORCE_CHANGE_PASSWORD",
+				"Enabled":    false,
+			}},
+		}
+	})
+
+	var got []CognitoUser
+	err := svc.ListUsers(context.Background(), "us-east-1_example", func(u CognitoUser) error {
+		got = append(got, u)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, got, 2)
+	assert.Equal(t, "first", got[0].Username)
+	assert.Equal(t, "CONFIRMED", got[0].Status)
+	assert.True(t, got[0].Enabled)
+	assert.True(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Equal(got[0].Created))
+	assert.Equal(t, map[string]string{"email": "first@example.com"}, got[0].Attributes)
+	assert.Equal(t, "second", got[1].Username)
+	assert.False(t, got[1].Enabled)
+}
+
+func TestCognitoService_AdminCreateUser(t *testing.T) {
+	var got map[string]interface{}
+	svc := newTestCognitoService(t, func(target string, body map[string]interface{}) interface{} {
+		assert.Equal(t, "AWSCognitoIdentityProviderService.AdminCreateUser", target)
+		got = body
+		return map[string]interface{}{}
+	})
+
+	err := svc.AdminCreateUser(context.Background(), "us-east-1_example", NewCognitoUser{
+		Username:        "test",
+		Attributes:      map[string]string{"email": "test@example.com"},
+		SuppressMessage: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "us-east-1_example", got["UserPoolId"])
+	assert.Equal(t, "test", got["Username"])
+	assert.Equal(t, "SUPPRESS", got["MessageAction"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"Name": "email", "Value": "test@example.com"}}, got["UserAttributes"])
+	assert.NotContains(t, got, "TemporaryPassword")
+}
+
+func TestCognitoService_AdminSetUserPassword(t *testing.T) {
+	var got map[string]interface{}
+	svc := newTestCognitoService(t, func(target string, body map[string]interface{}) interface{} {
+		assert.Equal(t, "AWSCognitoIdentityProviderService.AdminSetUserPassword", target)
+		got = body
+		return map[string]interface{}{}
+	})
+
+	err := svc.AdminSetUserPassword(context.Background(), "us-east-1_example", "test", "test123456A", true)
+	require.NoError(t, err)
+
+	assert.Equal(t, "us-east-1_example", got["UserPoolId"])
+	assert.Equal(t, "test", got["Username"])
+	assert.Equal(t, "test123456A", got["Password"])
+	assert.Equal(t, true, got["Permanent"])
+}
+
+func TestCognitoService_ListUsersInGroup(t *testing.T) {
+	svc := newTestCognitoService(t, func(target string, body map[string]interface{}) interface{} {
+		assert.Equal(t, "AWSCognitoIdentityProviderService.ListUsersInGroup", target)
+		assert.Equal(t, "guests", body["GroupName"])
+
+		if body["NextToken"] == nil {
+			return map[string]interface{}{
+				"Users":     []interface{}{map[string]interface{}{"Username": "first"}},
+				"NextToken": "fake_token",
+			}
+		}
+		assert.Equal(t, "fake_token", body["NextToken"])
+		return map[string]interface{}{
+			"Users": []interface{}{map[string]interface{}{"Username": "second"}},
+		}
+	})
+
+	var got []string
+	err := svc.ListUsersInGroup(context.Background(), "us-east-1_example", "guests", func(u CognitoUser) error {
+		got = append(got, u.Username)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, got)
+}
+
+func TestCognitoService_AdminGetUser(t *testing.T) {
+	svc := newTestCognitoService(t, func(target string, body map[string]interface{}) interface{} {
+		assert.Equal(t, "AWSCognitoIdentityProviderService.AdminGetUser", target)
+		assert.Equal(t, "test", body["Username"])
+		return map[string]interface{}{
+			"Username":   "test",
+			"UserStatus": "CONFIRMED",
+			"Enabled":    true,
+			"UserAttributes": []interface{}{
+				map[string]string{"Name": "sub", "Value": "fake_sub"},
+				map[string]string{"Name": "identities", "Value": `[{"userId":"1234","providerName":"Google","providerType":"Google","primary":false,"dateCreated":1704067200000}]`},
+			},
+		}
+	})
+
+	got, err := svc.AdminGetUser(context.Background(), "us-east-1_example", "test")
+	require.NoError(t, err)
+	assert.Equal(t, "CONFIRMED", got.Status)
+	assert.Equal(t, "fake_sub", got.Attributes["sub"])
+
+	identities, err := got.Identities()
+	require.NoError(t, err)
+	assert.Equal(t, []CognitoIdentity{{UserID: "1234", ProviderName: "Google", ProviderType: "Google", DateCreated: 1704067200000}}, identities)
+}
+
+func TestCognitoService_AdminLinkProviderForUser(t *testing.T) {
+	var got map[string]interface{}
+	svc := newTestCognitoService(t, func(target string, body map[string]interface{}) interface{} {
+		assert.Equal(t, "AWSCognitoIdentityProviderService.AdminLinkProviderForUser", target)
+		got = body
+		return map[string]interface{}{}
+	})
+
+	err := svc.AdminLinkProviderForUser(context.Background(), "us-east-1_example", "test", CognitoIdentity{UserID: "1234", ProviderName: "Google"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{"ProviderName": "Cognito", "ProviderAttributeValue": "test"}, got["DestinationUser"])
+	assert.Equal(t, map[string]interface{}{"ProviderName": "Google", "ProviderAttributeName": "Cognito_Subject", "ProviderAttributeValue": "1234"}, got["SourceUser"])
+}
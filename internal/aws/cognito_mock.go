@@ -22,6 +22,202 @@ func (_m *MockCognitoAuthService) EXPECT() *MockCognitoAuthService_Expecter {
 	return &MockCognitoAuthService_Expecter{mock: &_m.Mock}
 }
 
+// ChangePassword provides a mock function with given fields: ctx, accessToken, previousPassword, proposedPassword
+func (_m *MockCognitoAuthService) ChangePassword(ctx context.Context, accessToken string, previousPassword string, proposedPassword string) error {
+	ret := _m.Called(ctx, accessToken, previousPassword, proposedPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, accessToken, previousPassword, proposedPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCognitoAuthService_ChangePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangePassword'
+type MockCognitoAuthService_ChangePassword_Call struct {
+	*mock.Call
+}
+
+// ChangePassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accessToken string
+//   - previousPassword string
+//   - proposedPassword string
+func (_e *MockCognitoAuthService_Expecter) ChangePassword(ctx interface{}, accessToken interface{}, previousPassword interface{}, proposedPassword interface{}) *MockCognitoAuthService_ChangePassword_Call {
+	return &MockCognitoAuthService_ChangePassword_Call{Call: _e.mock.On("ChangePassword", ctx, accessToken, previousPassword, proposedPassword)}
+}
+
+func (_c *MockCognitoAuthService_ChangePassword_Call) Run(run func(ctx context.Context, accessToken string, previousPassword string, proposedPassword string)) *MockCognitoAuthService_ChangePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockCognitoAuthService_ChangePassword_Call) Return(_a0 error) *MockCognitoAuthService_ChangePassword_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCognitoAuthService_ChangePassword_Call) RunAndReturn(run func(context.Context, string, string, string) error) *MockCognitoAuthService_ChangePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConfirmForgotPassword provides a mock function with given fields: ctx, clientId, clientSecret, username, code, password
+func (_m *MockCognitoAuthService) ConfirmForgotPassword(ctx context.Context, clientId string, clientSecret string, username string, code string, password string) error {
+	ret := _m.Called(ctx, clientId, clientSecret, username, code, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmForgotPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string) error); ok {
+		r0 = rf(ctx, clientId, clientSecret, username, code, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCognitoAuthService_ConfirmForgotPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmForgotPassword'
+type MockCognitoAuthService_ConfirmForgotPassword_Call struct {
+	*mock.Call
+}
+
+// ConfirmForgotPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clientId string
+//   - clientSecret string
+//   - username string
+//   - code string
+//   - password string
+func (_e *MockCognitoAuthService_Expecter) ConfirmForgotPassword(ctx interface{}, clientId interface{}, clientSecret interface{}, username interface{}, code interface{}, password interface{}) *MockCognitoAuthService_ConfirmForgotPassword_Call {
+	return &MockCognitoAuthService_ConfirmForgotPassword_Call{Call: _e.mock.On("ConfirmForgotPassword", ctx, clientId, clientSecret, username, code, password)}
+}
+
+func (_c *MockCognitoAuthService_ConfirmForgotPassword_Call) Run(run func(ctx context.Context, clientId string, clientSecret string, username string, code string, password string)) *MockCognitoAuthService_ConfirmForgotPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string), args[5].(string))
+	})
+	return _c
+}
+
+func (_c *MockCognitoAuthService_ConfirmForgotPassword_Call) Return(_a0 error) *MockCognitoAuthService_ConfirmForgotPassword_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCognitoAuthService_ConfirmForgotPassword_Call) RunAndReturn(run func(context.Context, string, string, string, string, string) error) *MockCognitoAuthService_ConfirmForgotPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUser provides a mock function with given fields: ctx, accessToken
+func (_m *MockCognitoAuthService) DeleteUser(ctx context.Context, accessToken string) error {
+	ret := _m.Called(ctx, accessToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCognitoAuthService_DeleteUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUser'
+type MockCognitoAuthService_DeleteUser_Call struct {
+	*mock.Call
+}
+
+// DeleteUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accessToken string
+func (_e *MockCognitoAuthService_Expecter) DeleteUser(ctx interface{}, accessToken interface{}) *MockCognitoAuthService_DeleteUser_Call {
+	return &MockCognitoAuthService_DeleteUser_Call{Call: _e.mock.On("DeleteUser", ctx, accessToken)}
+}
+
+func (_c *MockCognitoAuthService_DeleteUser_Call) Run(run func(ctx context.Context, accessToken string)) *MockCognitoAuthService_DeleteUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCognitoAuthService_DeleteUser_Call) Return(_a0 error) *MockCognitoAuthService_DeleteUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCognitoAuthService_DeleteUser_Call) RunAndReturn(run func(context.Context, string) error) *MockCognitoAuthService_DeleteUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ForgotPassword provides a mock function with given fields: ctx, clientId, clientSecret, username
+func (_m *MockCognitoAuthService) ForgotPassword(ctx context.Context, clientId string, clientSecret string, username string) error {
+	ret := _m.Called(ctx, clientId, clientSecret, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForgotPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, clientId, clientSecret, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCognitoAuthService_ForgotPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ForgotPassword'
+type MockCognitoAuthService_ForgotPassword_Call struct {
+	*mock.Call
+}
+
+// ForgotPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clientId string
+//   - clientSecret string
+//   - username string
+func (_e *MockCognitoAuthService_Expecter) ForgotPassword(ctx interface{}, clientId interface{}, clientSecret interface{}, username interface{}) *MockCognitoAuthService_ForgotPassword_Call {
+	return &MockCognitoAuthService_ForgotPassword_Call{Call: _e.mock.On("ForgotPassword", ctx, clientId, clientSecret, username)}
+}
+
+func (_c *MockCognitoAuthService_ForgotPassword_Call) Run(run func(ctx context.Context, clientId string, clientSecret string, username string)) *MockCognitoAuthService_ForgotPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockCognitoAuthService_ForgotPassword_Call) Return(_a0 error) *MockCognitoAuthService_ForgotPassword_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCognitoAuthService_ForgotPassword_Call) RunAndReturn(run func(context.Context, string, string, string) error) *MockCognitoAuthService_ForgotPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Login provides a mock function with given fields: ctx, clientId, clientSecret, username, password
 func (_m *MockCognitoAuthService) Login(ctx context.Context, clientId string, clientSecret string, username string, password string) (*CognitoToken, error) {
 	ret := _m.Called(ctx, clientId, clientSecret, username, password)
@@ -193,6 +389,64 @@ func (_c *MockCognitoAuthService_SignUp_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
+// UserExists provides a mock function with given fields: ctx, userPoolId, username
+func (_m *MockCognitoAuthService) UserExists(ctx context.Context, userPoolId string, username string) (bool, error) {
+	ret := _m.Called(ctx, userPoolId, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UserExists")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return rf(ctx, userPoolId, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(ctx, userPoolId, username)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userPoolId, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCognitoAuthService_UserExists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UserExists'
+type MockCognitoAuthService_UserExists_Call struct {
+	*mock.Call
+}
+
+// UserExists is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userPoolId string
+//   - username string
+func (_e *MockCognitoAuthService_Expecter) UserExists(ctx interface{}, userPoolId interface{}, username interface{}) *MockCognitoAuthService_UserExists_Call {
+	return &MockCognitoAuthService_UserExists_Call{Call: _e.mock.On("UserExists", ctx, userPoolId, username)}
+}
+
+func (_c *MockCognitoAuthService_UserExists_Call) Run(run func(ctx context.Context, userPoolId string, username string)) *MockCognitoAuthService_UserExists_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockCognitoAuthService_UserExists_Call) Return(_a0 bool, _a1 error) *MockCognitoAuthService_UserExists_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCognitoAuthService_UserExists_Call) RunAndReturn(run func(context.Context, string, string) (bool, error)) *MockCognitoAuthService_UserExists_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ValidateToken provides a mock function with given fields: ctx, userPoolId, tokenString
 func (_m *MockCognitoAuthService) ValidateToken(ctx context.Context, userPoolId string, tokenString string) (*jwt.Token, error) {
 	ret := _m.Called(ctx, userPoolId, tokenString)
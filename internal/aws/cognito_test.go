@@ -0,0 +1,297 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/whatisusername/toon-tank-user-service/internal/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+var spanRecorder = tracetest.NewSpanRecorder()
+
+func init() {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+}
+
+func TestCognitoService_SignUp(t *testing.T) {
+	type args struct {
+		clientSecret string
+	}
+	tests := []struct {
+		name           string
+		args           args
+		wantSecretHash string
+	}{
+		{
+			name:           "Confidential Client",
+			args:           args{clientSecret: "fake_client_secret"},
+			wantSecretHash: "u7KaAX9WNZZbHioScl3b/LDcYmlXeGukgY37LobMwm0=",
+		},
+		{
+			name:           "Public Client",
+			args:           args{clientSecret: ""},
+			wantSecretHash: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]interface{}
+			svc := newTestCognitoService(t, func(target string, body map[string]interface{}) interface{} {
+				assert.Equal(t, "AWSCognitoIdentityProviderService.SignUp", target)
+				got = body
+				return map[string]interface{}{"UserConfirmed": false, "UserSub": "fake_sub"}
+			})
+
+			err := svc.SignUp(context.Background(), "fake_client_id", tt.args.clientSecret, "test", "test123456A", "test@example.com")
+			require.NoError(t, err)
+
+			if tt.wantSecretHash == "" {
+				assert.NotContains(t, got, "SecretHash")
+			} else {
+				assert.Equal(t, tt.wantSecretHash, got["SecretHash"])
+			}
+		})
+	}
+}
+
+func TestCognitoService_Login(t *testing.T) {
+	type args struct {
+		clientSecret string
+	}
+	tests := []struct {
+		name           string
+		args           args
+		wantSecretHash string
+	}{
+		{
+			name:           "Confidential Client",
+			args:           args{clientSecret: "fake_client_secret"},
+			wantSecretHash: "u7KaAX9WNZZbHioScl3b/LDcYmlXeGukgY37LobMwm0=",
+		},
+		{
+			name:           "Public Client",
+			args:           args{clientSecret: ""},
+			wantSecretHash: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]interface{}
+			svc := newTestCognitoService(t, func(target string, body map[string]interface{}) interface{} {
+				assert.Equal(t, "AWSCognitoIdentityProviderService.InitiateAuth", target)
+				got = body
+				return map[string]interface{}{
+					"AuthenticationResult": map[string]interface{}{
+						"IdToken":      "fake_id_token",
+						"AccessToken":  "fake_access_token",
+						"RefreshToken": "fake_refresh_token",
+						"TokenType":    "Bearer",
+						"ExpiresIn":    3600,
+					},
+				}
+			})
+
+			cgToken, err := svc.Login(context.Background(), "fake_client_id", tt.args.clientSecret, "test", "test123456A")
+			require.NoError(t, err)
+			assert.Equal(t, &CognitoToken{
+				IdToken:      "fake_id_token",
+				AccessToken:  "fake_access_token",
+				RefreshToken: "fake_refresh_token",
+			}, cgToken)
+
+			authParameters, ok := got["AuthParameters"].(map[string]interface{})
+			require.True(t, ok, "AuthParameters missing from request")
+			assert.Equal(t, "test", authParameters["USERNAME"])
+			assert.Equal(t, "test123456A", authParameters["PASSWORD"])
+
+			if tt.wantSecretHash == "" {
+				assert.NotContains(t, authParameters, "SECRET_HASH")
+			} else {
+				assert.Equal(t, tt.wantSecretHash, authParameters["SECRET_HASH"])
+			}
+		})
+	}
+}
+
+func TestCognitoService_Login_challenge(t *testing.T) {
+	svc := newTestCognitoService(t, func(target string, body map[string]interface{}) interface{} {
+		return map[string]interface{}{
+			"ChallengeName":       "NEW_PASSWORD_REQUIRED",
+			"Session":             "fake_session",
+			"ChallengeParameters": map[string]interface{}{"USER_ID_FOR_SRP": "test"},
+		}
+	})
+
+	_, err := svc.Login(context.Background(), "fake_client_id", "", "test", "test123456A")
+	var challengeErr *ChallengeError
+	require.ErrorAs(t, err, &challengeErr)
+	assert.Equal(t, ChallengeNewPasswordRequired, challengeErr.Name)
+}
+
+func TestCognitoService_ConfirmForgotPassword(t *testing.T) {
+	type args struct {
+		clientSecret string
+	}
+	tests := []struct {
+		name           string
+		args           args
+		wantSecretHash string
+	}{
+		{
+			name:           "Confidential Client",
+			args:           args{clientSecret: "fake_client_secret"},
+			wantSecretHash: "u7KaAX9WNZZbHioScl3b/LDcYmlXeGukgY37LobMwm0=",
+		},
+		{
+			name:           "Public Client",
+			args:           args{clientSecret: ""},
+			wantSecretHash: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]interface{}
+			svc := newTestCognitoService(t, func(target string, body map[string]interface{}) interface{} {
+				assert.Equal(t, "AWSCognitoIdentityProviderService.ConfirmForgotPassword", target)
+				got = body
+				return map[string]interface{}{}
+			})
+
+			err := svc.ConfirmForgotPassword(context.Background(), "fake_client_id", tt.args.clientSecret, "test", "123456", "test123456B")
+			require.NoError(t, err)
+
+			assert.Equal(t, "test", got["Username"])
+			assert.Equal(t, "123456", got["ConfirmationCode"])
+			assert.Equal(t, "test123456B", got["Password"])
+			if tt.wantSecretHash == "" {
+				assert.NotContains(t, got, "SecretHash")
+			} else {
+				assert.Equal(t, tt.wantSecretHash, got["SecretHash"])
+			}
+		})
+	}
+}
+
+func TestCognitoService_UserExists(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    interface{}
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "Exists",
+			resp: map[string]interface{}{"Username": "test", "Enabled": true},
+			want: true,
+		},
+		{
+			name: "Not Found",
+			resp: "UserNotFoundException",
+			want: false,
+		},
+		{
+			name:    "Error",
+			resp:    "InvalidParameterException",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestCognitoService(t, func(target string, body map[string]interface{}) interface{} {
+				assert.Equal(t, "AWSCognitoIdentityProviderService.AdminGetUser", target)
+				assert.Equal(t, "us-east-1_example", body["UserPoolId"])
+				assert.Equal(t, "test", body["Username"])
+				return tt.resp
+			})
+
+			got, err := svc.UserExists(context.Background(), "us-east-1_example", "test")
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCognitoService_DeleteUser(t *testing.T) {
+	svc := newTestCognitoService(t, func(target string, body map[string]interface{}) interface{} {
+		assert.Equal(t, "AWSCognitoIdentityProviderService.DeleteUser", target)
+		assert.Equal(t, "fake_access_token", body["AccessToken"])
+		return map[string]interface{}{}
+	})
+
+	require.NoError(t, svc.DeleteUser(context.Background(), "fake_access_token"))
+}
+
+func TestCognitoService_tracing(t *testing.T) {
+	svc := newTestCognitoService(t, func(target string, body map[string]interface{}) interface{} {
+		return "UsernameExistsException"
+	})
+
+	before := len(spanRecorder.Ended())
+	err := svc.SignUp(context.Background(), "fake_client_id", "", "test", "test123456A", "test@example.com")
+	require.Error(t, err)
+
+	spans := spanRecorder.Ended()[before:]
+	require.Len(t, spans, 2, "expected the SignUp span and the AWS SDK span")
+
+	sdkSpan, span := spans[0], spans[1]
+	assert.Equal(t, "Cognito.SignUp", span.Name())
+	assert.Equal(t, span.SpanContext().SpanID(), sdkSpan.Parent().SpanID(), "AWS SDK span is not a child of the SignUp span")
+	assert.Equal(t, codes.Error, span.Status().Code)
+
+	attrs := attribute.NewSet(span.Attributes()...)
+	method, _ := attrs.Value(semconv.RPCMethodKey)
+	assert.Equal(t, "SignUp", method.AsString())
+	errorType, _ := attrs.Value(telemetry.ErrorTypeKey)
+	assert.Equal(t, "UsernameExistsException", errorType.AsString())
+}
+
+// newTestCognitoService points the Cognito client at a local server that hands
+// each decoded request to handle and encodes whatever it returns as the response.
+func newTestCognitoService(t *testing.T, handle func(target string, body map[string]interface{}) interface{}) *CognitoService {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		resp := handle(r.Header.Get("X-Amz-Target"), body)
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		if errType, ok := resp.(string); ok && strings.HasSuffix(errType, "Exception") {
+			w.WriteHeader(http.StatusBadRequest)
+			resp = map[string]string{"__type": errType, "message": errType}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(server.Close)
+
+	svc, err := NewCognitoService(context.Background(),
+		config.WithRegion("us-east-1"),
+		config.WithCredentialsProvider(aws.AnonymousCredentials{}),
+		config.WithBaseEndpoint(server.URL),
+	)
+	require.NoError(t, err)
+
+	return svc
+}
@@ -0,0 +1,22 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+)
+
+// NewDynamoDBClient returns a traced DynamoDB client shared by the stores that
+// keep their state in DynamoDB tables.
+func NewDynamoDBClient(ctx context.Context, optFns ...func(*dynamodb.Options)) (*dynamodb.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	otelaws.AppendMiddlewares(&cfg.APIOptions)
+
+	return dynamodb.NewFromConfig(cfg, optFns...), nil
+}
@@ -0,0 +1,48 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+)
+
+// NewEventBridgeClient returns a traced EventBridge client for the event
+// publisher.
+func NewEventBridgeClient(ctx context.Context, optFns ...func(*eventbridge.Options)) (*eventbridge.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	otelaws.AppendMiddlewares(&cfg.APIOptions)
+
+	return eventbridge.NewFromConfig(cfg, optFns...), nil
+}
+
+// NewSNSClient returns a traced SNS client for the event publisher.
+func NewSNSClient(ctx context.Context, optFns ...func(*sns.Options)) (*sns.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	otelaws.AppendMiddlewares(&cfg.APIOptions)
+
+	return sns.NewFromConfig(cfg, optFns...), nil
+}
+
+// NewSQSClient returns a traced SQS client for the event publisher.
+func NewSQSClient(ctx context.Context, optFns ...func(*sqs.Options)) (*sqs.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	otelaws.AppendMiddlewares(&cfg.APIOptions)
+
+	return sqs.NewFromConfig(cfg, optFns...), nil
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/whatisusername/toon-tank-user-service/internal/telemetry"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
 )
 
 type SecretStore interface {
@@ -24,6 +26,7 @@ func NewSecretsService(ctx context.Context, optFns ...func(*secretsmanager.Optio
 		return nil, err
 	}
 
+	otelaws.AppendMiddlewares(&cfg.APIOptions)
 	client := secretsmanager.NewFromConfig(cfg, optFns...)
 
 	return &SecretsService{
@@ -31,7 +34,10 @@ func NewSecretsService(ctx context.Context, optFns ...func(*secretsmanager.Optio
 	}, nil
 }
 
-func (s *SecretsService) GetSecretValue(ctx context.Context, key string) (*string, error) {
+func (s *SecretsService) GetSecretValue(ctx context.Context, key string) (_ *string, err error) {
+	ctx, span := startSpan(ctx, "SecretsManager", "GetSecretValue")
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	output, err := s.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
 		SecretId: aws.String(key),
 	})
@@ -0,0 +1,20 @@
+package aws
+
+import (
+	"context"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/whatisusername/toon-tank-user-service/internal/aws")
+
+func startSpan(ctx context.Context, service, operation string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, service+"."+operation, trace.WithAttributes(
+		semconv.RPCSystemKey.String("aws-api"),
+		semconv.RPCService(service),
+		semconv.RPCMethod(operation),
+	))
+}
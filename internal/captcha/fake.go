@@ -0,0 +1,16 @@
+package captcha
+
+import "context"
+
+// FakeVerifier accepts exactly one token without calling out to a provider.
+// It is meant for tests and local development.
+type FakeVerifier struct {
+	Token string
+}
+
+func (v FakeVerifier) Verify(_ context.Context, token, _ string) error {
+	if token == "" || token != v.Token {
+		return ErrVerificationFailed
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+)
+
+const HCaptchaVerifyURL = "https://api.hcaptcha.com/siteverify"
+
+type HCaptchaVerifier struct {
+	secret    string
+	verifyURL string
+	client    *http.Client
+}
+
+// NewHCaptchaVerifier verifies tokens against verifyURL, falling back to the
+// public hCaptcha endpoint when it is empty.
+func NewHCaptchaVerifier(secret, verifyURL string) *HCaptchaVerifier {
+	if verifyURL == "" {
+		verifyURL = HCaptchaVerifyURL
+	}
+	return &HCaptchaVerifier{
+		secret:    secret,
+		verifyURL: verifyURL,
+		client:    &http.Client{Timeout: verifyTimeout},
+	}
+}
+
+func (v *HCaptchaVerifier) Verify(ctx context.Context, token, remoteIP string) error {
+	result, err := siteVerify(ctx, v.client, v.verifyURL, v.secret, token, remoteIP)
+	if err != nil {
+		return err
+	}
+	if !result.Success {
+		return rejected(result)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHCaptchaVerifier_Verify(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		response   interface{}
+		wantErr    bool
+		wantReject bool
+	}{
+		{
+			name:     "OK",
+			status:   http.StatusOK,
+			response: map[string]interface{}{"success": true},
+		},
+		{
+			name:       "Rejected",
+			status:     http.StatusOK,
+			response:   map[string]interface{}{"success": false, "error-codes": []string{"invalid-input-response"}},
+			wantErr:    true,
+			wantReject: true,
+		},
+		{
+			name:     "Provider Error",
+			status:   http.StatusInternalServerError,
+			response: map[string]interface{}{},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifyURL := newTestProvider(t, func(form map[string]string) {
+				assert.Equal(t, "fake_secret", form["secret"])
+				assert.Equal(t, "fake_token", form["response"])
+				assert.Equal(t, "10.0.0.1", form["remoteip"])
+			}, tt.status, tt.response)
+
+			err := NewHCaptchaVerifier("fake_secret", verifyURL).Verify(context.Background(), "fake_token", "10.0.0.1")
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.wantReject, errors.Is(err, ErrVerificationFailed))
+		})
+	}
+}
+
+// newTestProvider starts a siteverify endpoint that hands the submitted form to
+// check and replies with status and response.
+func newTestProvider(t *testing.T, check func(form map[string]string), status int, response interface{}) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		if !assert.NoError(t, r.ParseForm()) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		form := make(map[string]string)
+		for k := range r.PostForm {
+			form[k] = r.PostForm.Get(k)
+		}
+		check(form)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		assert.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
@@ -0,0 +1,46 @@
+package captcha
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const ReCaptchaVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
+
+type ReCaptchaVerifier struct {
+	secret    string
+	verifyURL string
+	minScore  float64
+	client    *http.Client
+}
+
+// NewReCaptchaVerifier verifies tokens against verifyURL, falling back to the
+// public reCAPTCHA endpoint when it is empty. For reCAPTCHA v3 tokens, scores
+// below minScore are rejected; v2 responses carry no score and only need to
+// succeed.
+func NewReCaptchaVerifier(secret, verifyURL string, minScore float64) *ReCaptchaVerifier {
+	if verifyURL == "" {
+		verifyURL = ReCaptchaVerifyURL
+	}
+	return &ReCaptchaVerifier{
+		secret:    secret,
+		verifyURL: verifyURL,
+		minScore:  minScore,
+		client:    &http.Client{Timeout: verifyTimeout},
+	}
+}
+
+func (v *ReCaptchaVerifier) Verify(ctx context.Context, token, remoteIP string) error {
+	result, err := siteVerify(ctx, v.client, v.verifyURL, v.secret, token, remoteIP)
+	if err != nil {
+		return err
+	}
+	if !result.Success {
+		return rejected(result)
+	}
+	if result.Score != nil && *result.Score < v.minScore {
+		return fmt.Errorf("%w: score %.2f below %.2f", ErrVerificationFailed, *result.Score, v.minScore)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package captcha
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReCaptchaVerifier_Verify(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		response   interface{}
+		wantErr    bool
+		wantReject bool
+	}{
+		{
+			name:     "OK v2",
+			status:   http.StatusOK,
+			response: map[string]interface{}{"success": true},
+		},
+		{
+			name:     "OK v3",
+			status:   http.StatusOK,
+			response: map[string]interface{}{"success": true, "score": 0.9},
+		},
+		{
+			name:       "Low Score",
+			status:     http.StatusOK,
+			response:   map[string]interface{}{"success": true, "score": 0.1},
+			wantErr:    true,
+			wantReject: true,
+		},
+		{
+			name:       "Rejected",
+			status:     http.StatusOK,
+			response:   map[string]interface{}{"success": false, "error-codes": []string{"timeout-or-duplicate"}},
+			wantErr:    true,
+			wantReject: true,
+		},
+		{
+			name:     "Provider Error",
+			status:   http.StatusServiceUnavailable,
+			response: map[string]interface{}{},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifyURL := newTestProvider(t, func(form map[string]string) {
+				assert.Equal(t, "fake_secret", form["secret"])
+				assert.Equal(t, "fake_token", form["response"])
+				assert.NotContains(t, form, "remoteip")
+			}, tt.status, tt.response)
+
+			err := NewReCaptchaVerifier("fake_secret", verifyURL, 0.5).Verify(context.Background(), "fake_token", "")
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.wantReject, errors.Is(err, ErrVerificationFailed))
+		})
+	}
+}
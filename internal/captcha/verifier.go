@@ -0,0 +1,71 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// verifyTimeout bounds a siteverify call, so a slow provider fails the request
+// instead of holding the function until it times out.
+const verifyTimeout = 5 * time.Second
+
+// ErrVerificationFailed is returned when the provider rejects the token, as
+// opposed to when the provider could not be reached.
+var ErrVerificationFailed = errors.New("captcha verification failed")
+
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	Score      *float64 `json:"score,omitempty"`
+	ErrorCodes []string `json:"error-codes,omitempty"`
+}
+
+// siteVerify posts token to a siteverify endpoint. hCaptcha and reCAPTCHA
+// share the same form parameters and response shape.
+func siteVerify(ctx context.Context, client *http.Client, verifyURL, secret, token, remoteIP string) (*siteVerifyResponse, error) {
+	form := url.Values{
+		"secret":   {secret},
+		"response": {token},
+	}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status from captcha provider: %d", resp.StatusCode)
+	}
+
+	var result siteVerifyResponse
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode captcha response: %w", err)
+	}
+	return &result, nil
+}
+
+func rejected(result *siteVerifyResponse) error {
+	if len(result.ErrorCodes) == 0 {
+		return ErrVerificationFailed
+	}
+	return fmt.Errorf("%w: %s", ErrVerificationFailed, strings.Join(result.ErrorCodes, ", "))
+}
@@ -3,19 +3,85 @@ package config
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 
 	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
 	"github.com/whatisusername/toon-tank-user-service/internal/env"
+	"github.com/whatisusername/toon-tank-user-service/internal/password"
 )
 
-type CognitoConfig struct {
-	UserPoolID    string `json:"userPoolId"`
+type ClientConfig struct {
 	ClientID      string `json:"clientId"`
-	ClientSecrets string `json:"clientSecrets"`
+	ClientSecrets string `json:"clientSecrets,omitempty"`
+}
+
+type CognitoConfig struct {
+	UserPoolID    string                  `json:"userPoolId"`
+	ClientID      string                  `json:"clientId"`
+	ClientSecrets string                  `json:"clientSecrets,omitempty"`
+	Clients       map[string]ClientConfig `json:"clients,omitempty"`
+}
+
+// Client returns the app client registered for platform. An empty platform
+// selects the default client defined by ClientID and ClientSecrets.
+func (c CognitoConfig) Client(platform string) (ClientConfig, error) {
+	if platform == "" {
+		if c.ClientID == "" {
+			return ClientConfig{}, errors.New("no default app client configured")
+		}
+		return ClientConfig{ClientID: c.ClientID, ClientSecrets: c.ClientSecrets}, nil
+	}
+
+	client, ok := c.Clients[strings.ToLower(platform)]
+	if !ok {
+		return ClientConfig{}, fmt.Errorf("unknown client platform %q", platform)
+	}
+	return client, nil
+}
+
+// CaptchaConfig selects the CAPTCHA provider. An empty Provider disables
+// CAPTCHA checks; VerifyURL overrides the provider's public endpoint.
+type CaptchaConfig struct {
+	Provider  string  `json:"provider"`
+	SecretKey string  `json:"secretKey"`
+	VerifyURL string  `json:"verifyUrl,omitempty"`
+	MinScore  float64 `json:"minScore,omitempty"`
+}
+
+// EmailConfig controls address normalisation and adds BlockedDomains to any
+// disposable-domain list loaded from a file.
+type EmailConfig struct {
+	StripTags      bool     `json:"stripTags,omitempty"`
+	BlockedDomains []string `json:"blockedDomains,omitempty"`
+}
+
+// UsernameConfig adds ReservedNames to username.DefaultReserved.
+type UsernameConfig struct {
+	ReservedNames []string `json:"reservedNames,omitempty"`
+}
+
+// OAuthConfig enables sign-in through the user pool's hosted UI. Domain is the
+// base URL of the user pool domain and RedirectURI the callback registered on
+// the app clients. StateKey is a base64 AES key that seals the state parameter.
+// A non-empty Providers limits which identity providers may be requested.
+type OAuthConfig struct {
+	Domain      string   `json:"domain"`
+	RedirectURI string   `json:"redirectUri"`
+	StateKey    string   `json:"stateKey"`
+	Scopes      []string `json:"scopes,omitempty"`
+	Providers   []string `json:"providers,omitempty"`
 }
 
 type Config struct {
-	Cognito CognitoConfig `json:"cognito"`
+	Cognito  CognitoConfig  `json:"cognito"`
+	Captcha  CaptchaConfig  `json:"captcha"`
+	Email    EmailConfig    `json:"email"`
+	Username UsernameConfig `json:"username"`
+	OAuth    OAuthConfig    `json:"oauth"`
+	// PasswordPolicy overrides password.DefaultPolicy when set.
+	PasswordPolicy *password.Policy `json:"passwordPolicy,omitempty"`
 }
 
 func LoadConfig(ctx context.Context, secretStore caws.SecretStore) (*Config, error) {
@@ -29,12 +95,40 @@ func LoadConfig(ctx context.Context, secretStore caws.SecretStore) (*Config, err
 		return nil, err
 	}
 
-	var cc CognitoConfig
-	if err = json.Unmarshal([]byte(*secrets), &cc); err != nil {
+	// The Cognito settings sit at the top level of the secret for backwards
+	// compatibility; everything else lives under its own key.
+	var sc struct {
+		CognitoConfig
+		Captcha        CaptchaConfig    `json:"captcha"`
+		Email          EmailConfig      `json:"email"`
+		Username       UsernameConfig   `json:"username"`
+		OAuth          OAuthConfig      `json:"oauth"`
+		PasswordPolicy *password.Policy `json:"passwordPolicy"`
+	}
+	if err = json.Unmarshal([]byte(*secrets), &sc); err != nil {
 		return nil, err
 	}
 
+	// Client looks platforms up in lower case, so the keys are stored that way
+	// whatever case the secret uses.
+	if sc.Clients != nil {
+		clients := make(map[string]ClientConfig, len(sc.Clients))
+		for platform, client := range sc.Clients {
+			key := strings.ToLower(platform)
+			if _, ok := clients[key]; ok {
+				return nil, fmt.Errorf("client platform %q is configured more than once", key)
+			}
+			clients[key] = client
+		}
+		sc.Clients = clients
+	}
+
 	return &Config{
-		Cognito: cc,
+		Cognito:        sc.CognitoConfig,
+		Captcha:        sc.Captcha,
+		Email:          sc.Email,
+		Username:       sc.Username,
+		OAuth:          sc.OAuth,
+		PasswordPolicy: sc.PasswordPolicy,
 	}, nil
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/password"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -37,6 +38,172 @@ func TestLoadConfig(t *testing.T) {
 			want:    &Config{Cognito: *cognitoCfg},
 			wantErr: false,
 		},
+		{
+			name: "Public Client",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("SECRET_NAME", "test")
+			},
+			mockSecretStoreResponse: func(secretStore *caws.MockSecretStore) {
+				secretStore.EXPECT().
+					GetSecretValue(mock.Anything, mock.AnythingOfType("string")).
+					Return(stringPtr(`{"userPoolId":"us-east-1_example","clientId":"fake_client_id"}`), nil).
+					Once()
+			},
+			want: &Config{Cognito: CognitoConfig{
+				UserPoolID: "us-east-1_example",
+				ClientID:   "fake_client_id",
+			}},
+			wantErr: false,
+		},
+		{
+			name: "Platform Clients",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("SECRET_NAME", "test")
+			},
+			mockSecretStoreResponse: func(secretStore *caws.MockSecretStore) {
+				secretStore.EXPECT().
+					GetSecretValue(mock.Anything, mock.AnythingOfType("string")).
+					Return(stringPtr(`{"userPoolId":"us-east-1_example","clients":{"pc":{"clientId":"fake_pc_client_id","clientSecrets":"fake_pc_client_secret"}}}`), nil).
+					Once()
+			},
+			want: &Config{Cognito: CognitoConfig{
+				UserPoolID: "us-east-1_example",
+				Clients: map[string]ClientConfig{
+					"pc": {ClientID: "fake_pc_client_id", ClientSecrets: "fake_pc_client_secret"},
+				},
+			}},
+			wantErr: false,
+		},
+		{
+			name: "Mixed Case Platform Clients",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("SECRET_NAME", "test")
+			},
+			mockSecretStoreResponse: func(secretStore *caws.MockSecretStore) {
+				secretStore.EXPECT().
+					GetSecretValue(mock.Anything, mock.AnythingOfType("string")).
+					Return(stringPtr(`{"userPoolId":"us-east-1_example","clients":{"PC":{"clientId":"fake_pc_client_id"},"Mobile":{"clientId":"fake_mobile_client_id"}}}`), nil).
+					Once()
+			},
+			want: &Config{Cognito: CognitoConfig{
+				UserPoolID: "us-east-1_example",
+				Clients: map[string]ClientConfig{
+					"pc":     {ClientID: "fake_pc_client_id"},
+					"mobile": {ClientID: "fake_mobile_client_id"},
+				},
+			}},
+			wantErr: false,
+		},
+		{
+			name: "Platform Clients Differing Only In Case",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("SECRET_NAME", "test")
+			},
+			mockSecretStoreResponse: func(secretStore *caws.MockSecretStore) {
+				secretStore.EXPECT().
+					GetSecretValue(mock.Anything, mock.AnythingOfType("string")).
+					Return(stringPtr(`{"userPoolId":"us-east-1_example","clients":{"PC":{"clientId":"fake_pc_client_id"},"pc":{"clientId":"other_pc_client_id"}}}`), nil).
+					Once()
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Captcha",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("SECRET_NAME", "test")
+			},
+			mockSecretStoreResponse: func(secretStore *caws.MockSecretStore) {
+				secretStore.EXPECT().
+					GetSecretValue(mock.Anything, mock.AnythingOfType("string")).
+					Return(stringPtr(`{"userPoolId":"us-east-1_example","clientId":"fake_client_id","captcha":{"provider":"recaptcha","secretKey":"fake_captcha_secret","minScore":0.5}}`), nil).
+					Once()
+			},
+			want: &Config{
+				Cognito: CognitoConfig{
+					UserPoolID: "us-east-1_example",
+					ClientID:   "fake_client_id",
+				},
+				Captcha: CaptchaConfig{
+					Provider:  "recaptcha",
+					SecretKey: "fake_captcha_secret",
+					MinScore:  0.5,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Password Policy",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("SECRET_NAME", "test")
+			},
+			mockSecretStoreResponse: func(secretStore *caws.MockSecretStore) {
+				secretStore.EXPECT().
+					GetSecretValue(mock.Anything, mock.AnythingOfType("string")).
+					Return(stringPtr(`{"userPoolId":"us-east-1_example","clientId":"fake_client_id","passwordPolicy":{"minLength":12,"requireUppercase":true,"requireSymbol":true}}`), nil).
+					Once()
+			},
+			want: &Config{
+				Cognito: CognitoConfig{
+					UserPoolID: "us-east-1_example",
+					ClientID:   "fake_client_id",
+				},
+				PasswordPolicy: &password.Policy{
+					MinLength:     12,
+					RequireUpper:  true,
+					RequireSymbol: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Email",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("SECRET_NAME", "test")
+			},
+			mockSecretStoreResponse: func(secretStore *caws.MockSecretStore) {
+				secretStore.EXPECT().
+					GetSecretValue(mock.Anything, mock.AnythingOfType("string")).
+					Return(stringPtr(`{"userPoolId":"us-east-1_example","clientId":"fake_client_id","email":{"stripTags":true,"blockedDomains":["mailinator.com"]}}`), nil).
+					Once()
+			},
+			want: &Config{
+				Cognito: CognitoConfig{
+					UserPoolID: "us-east-1_example",
+					ClientID:   "fake_client_id",
+				},
+				Email: EmailConfig{
+					StripTags:      true,
+					BlockedDomains: []string{"mailinator.com"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OAuth",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("SECRET_NAME", "test")
+			},
+			mockSecretStoreResponse: func(secretStore *caws.MockSecretStore) {
+				secretStore.EXPECT().
+					GetSecretValue(mock.Anything, mock.AnythingOfType("string")).
+					Return(stringPtr(`{"userPoolId":"us-east-1_example","clientId":"fake_client_id","oauth":{"domain":"https://example.auth.us-east-1.amazoncognito.com","redirectUri":"https://example.com/callback","stateKey":"fake_state_key","providers":["Google"]}}`), nil).
+					Once()
+			},
+			want: &Config{
+				Cognito: CognitoConfig{
+					UserPoolID: "us-east-1_example",
+					ClientID:   "fake_client_id",
+				},
+				OAuth: OAuthConfig{
+					Domain:      "https://example.auth.us-east-1.amazoncognito.com",
+					RedirectURI: "https://example.com/callback",
+					StateKey:    "fake_state_key",
+					Providers:   []string{"Google"},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name:     "Missing Env Variable",
 			setupEnv: func(t *testing.T) {},
@@ -94,6 +261,70 @@ func TestLoadConfig(t *testing.T) {
 	}
 }
 
+func TestCognitoConfig_Client(t *testing.T) {
+	cognitoCfg := fakeCognitoConfig()
+	cognitoCfg.Clients = map[string]ClientConfig{
+		"pc":     {ClientID: "fake_pc_client_id", ClientSecrets: "fake_pc_client_secret"},
+		"mobile": {ClientID: "fake_mobile_client_id"},
+	}
+
+	tests := []struct {
+		name     string
+		cfg      CognitoConfig
+		platform string
+		want     ClientConfig
+		wantErr  bool
+	}{
+		{
+			name:     "Default Client",
+			cfg:      *cognitoCfg,
+			platform: "",
+			want:     ClientConfig{ClientID: "fake_client_id", ClientSecrets: "fake_client_secret"},
+			wantErr:  false,
+		},
+		{
+			name:     "Platform Client",
+			cfg:      *cognitoCfg,
+			platform: "pc",
+			want:     ClientConfig{ClientID: "fake_pc_client_id", ClientSecrets: "fake_pc_client_secret"},
+			wantErr:  false,
+		},
+		{
+			name:     "Platform Is Case Insensitive",
+			cfg:      *cognitoCfg,
+			platform: "Mobile",
+			want:     ClientConfig{ClientID: "fake_mobile_client_id"},
+			wantErr:  false,
+		},
+		{
+			name:     "Unknown Platform",
+			cfg:      *cognitoCfg,
+			platform: "console",
+			want:     ClientConfig{},
+			wantErr:  true,
+		},
+		{
+			name:     "No Default Client",
+			cfg:      CognitoConfig{UserPoolID: "us-east-1_example", Clients: cognitoCfg.Clients},
+			platform: "",
+			want:     ClientConfig{},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cfg.Client(tt.platform)
+
+			if tt.wantErr {
+				assert.Error(t, err, "expected an error but got none")
+			} else {
+				assert.NoError(t, err, "unexpected error: %v", err)
+			}
+			assert.Equal(t, tt.want, got, "Client() returned unexpected result")
+		})
+	}
+}
+
 func fakeCognitoConfig() *CognitoConfig {
 	return &CognitoConfig{
 		UserPoolID:    "us-east-1_example",
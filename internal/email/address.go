@@ -0,0 +1,49 @@
+package email
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+)
+
+var (
+	ErrInvalidAddress = errors.New("invalid email address")
+	ErrDisplayName    = errors.New("email address must not include a display name")
+	ErrNoDomainDot    = errors.New("email domain must be fully qualified")
+)
+
+// Normalize parses address as a bare RFC 5322 addr-spec and returns it with
+// the domain lowercased. The local part is case-sensitive by the RFC and is
+// kept as is, except that a "+tag" suffix is removed when stripTag is set.
+func Normalize(address string, stripTag bool) (string, error) {
+	address = strings.TrimSpace(address)
+
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return "", ErrInvalidAddress
+	}
+	if parsed.Name != "" || strings.ContainsAny(address, "<>") {
+		return "", ErrDisplayName
+	}
+
+	at := strings.LastIndex(parsed.Address, "@")
+	local, domain := parsed.Address[:at], strings.ToLower(parsed.Address[at+1:])
+	if !strings.Contains(strings.Trim(domain, "."), ".") || strings.HasPrefix(domain, "[") {
+		return "", ErrNoDomainDot
+	}
+
+	if stripTag {
+		if i := strings.Index(local, "+"); i > 0 {
+			local = local[:i]
+		}
+	}
+
+	// net/mail unquotes the local part; String quotes it again where needed.
+	formatted := (&mail.Address{Address: local + "@" + domain}).String()
+	return strings.TrimSuffix(strings.TrimPrefix(formatted, "<"), ">"), nil
+}
+
+// Domain returns the part after the last "@" of a normalised address.
+func Domain(address string) string {
+	return address[strings.LastIndex(address, "@")+1:]
+}
@@ -0,0 +1,88 @@
+package email
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	type args struct {
+		address  string
+		stripTag bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr error
+	}{
+		{
+			name: "Valid",
+			args: args{address: "test@example.com"},
+			want: "test@example.com",
+		},
+		{
+			name: "Lowercases Domain Only",
+			args: args{address: " Test.User@Example.COM "},
+			want: "Test.User@example.com",
+		},
+		{
+			name: "Keeps Tag",
+			args: args{address: "test+toontank@example.com"},
+			want: "test+toontank@example.com",
+		},
+		{
+			name: "Strips Tag",
+			args: args{address: "test+toontank@example.com", stripTag: true},
+			want: "test@example.com",
+		},
+		{
+			name: "Quoted Local Part",
+			args: args{address: `"test user"@example.com`},
+			want: `"test user"@example.com`,
+		},
+		{
+			name:    "Missing At",
+			args:    args{address: "abc"},
+			wantErr: ErrInvalidAddress,
+		},
+		{
+			name:    "Missing Local Part",
+			args:    args{address: "@example.com"},
+			wantErr: ErrInvalidAddress,
+		},
+		{
+			name:    "Display Name",
+			args:    args{address: "Test <test@example.com>"},
+			wantErr: ErrDisplayName,
+		},
+		{
+			name:    "Angle Brackets",
+			args:    args{address: "<test@example.com>"},
+			wantErr: ErrDisplayName,
+		},
+		{
+			name:    "Unqualified Domain",
+			args:    args{address: "test@localhost"},
+			wantErr: ErrNoDomainDot,
+		},
+		{
+			name:    "Address Literal",
+			args:    args{address: "test@[127.0.0.1]"},
+			wantErr: ErrNoDomainDot,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.args.address, tt.args.stripTag)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
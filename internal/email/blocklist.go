@@ -0,0 +1,85 @@
+package email
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// Blocklist holds disposable email domains. A listed domain also blocks all
+// of its subdomains.
+type Blocklist struct {
+	domains map[string]struct{}
+}
+
+func NewBlocklist(domains ...string) *Blocklist {
+	b := &Blocklist{domains: make(map[string]struct{}, len(domains))}
+	b.Add(domains...)
+	return b
+}
+
+// LoadBlocklist reads one domain per line. Blank lines and lines starting with
+// "#" are ignored, which matches the common disposable-domain list formats.
+func LoadBlocklist(r io.Reader) (*Blocklist, error) {
+	b := NewBlocklist()
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		b.Add(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func LoadBlocklistFile(path string) (*Blocklist, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadBlocklist(f)
+}
+
+func (b *Blocklist) Add(domains ...string) {
+	for _, d := range domains {
+		d = strings.Trim(strings.ToLower(strings.TrimSpace(d)), ".")
+		if d != "" {
+			b.domains[d] = struct{}{}
+		}
+	}
+}
+
+func (b *Blocklist) Merge(other *Blocklist) {
+	for d := range other.domains {
+		b.domains[d] = struct{}{}
+	}
+}
+
+func (b *Blocklist) Blocked(domain string) bool {
+	domain = strings.Trim(strings.ToLower(domain), ".")
+	for domain != "" {
+		if _, ok := b.domains[domain]; ok {
+			return true
+		}
+
+		i := strings.Index(domain, ".")
+		if i < 0 {
+			break
+		}
+		domain = domain[i+1:]
+	}
+	return false
+}
+
+func (b *Blocklist) Len() int {
+	return len(b.domains)
+}
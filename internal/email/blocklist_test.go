@@ -0,0 +1,52 @@
+package email
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocklist_Blocked(t *testing.T) {
+	blocklist, err := LoadBlocklist(strings.NewReader("# disposable domains\n\nMailinator.com\n  10minutemail.com  \n"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, blocklist.Len())
+
+	tests := []struct {
+		name   string
+		domain string
+		want   bool
+	}{
+		{
+			name:   "Listed",
+			domain: "mailinator.com",
+			want:   true,
+		},
+		{
+			name:   "Case Insensitive",
+			domain: "10MinuteMail.com",
+			want:   true,
+		},
+		{
+			name:   "Subdomain",
+			domain: "eu.mailinator.com",
+			want:   true,
+		},
+		{
+			name:   "Not Listed",
+			domain: "example.com",
+			want:   false,
+		},
+		{
+			name:   "Suffix Is Not A Subdomain",
+			domain: "notmailinator.com",
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, blocklist.Blocked(tt.domain))
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package email
+
+import (
+	"errors"
+
+	"github.com/whatisusername/toon-tank-user-service/internal/validation"
+)
+
+const (
+	CodeInvalid       = "EmailInvalid"
+	CodeDomainBlocked = "EmailDomainBlocked"
+)
+
+type Validator struct {
+	stripTags bool
+	blocklist *Blocklist
+}
+
+// NewValidator returns a validator that optionally strips "+tags" and rejects
+// domains on blocklist, which may be nil.
+func NewValidator(stripTags bool, blocklist *Blocklist) *Validator {
+	return &Validator{
+		stripTags: stripTags,
+		blocklist: blocklist,
+	}
+}
+
+// Validate returns the normalised address, or the violations that prevent it
+// from being used.
+func (v *Validator) Validate(field, address string) (string, []validation.Violation) {
+	normalized, err := Normalize(address, v.stripTags)
+	if err != nil {
+		message := "must be a valid email address"
+		if errors.Is(err, ErrDisplayName) || errors.Is(err, ErrNoDomainDot) {
+			message = err.Error()
+		}
+		return "", []validation.Violation{{Field: field, Code: CodeInvalid, Message: message}}
+	}
+
+	if v.blocklist != nil && v.blocklist.Blocked(Domain(normalized)) {
+		return "", []validation.Violation{{
+			Field:   field,
+			Code:    CodeDomainBlocked,
+			Message: "disposable email addresses are not allowed",
+		}}
+	}
+
+	return normalized, nil
+}
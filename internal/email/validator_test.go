@@ -0,0 +1,47 @@
+package email
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidator_Validate(t *testing.T) {
+	validator := NewValidator(true, NewBlocklist("mailinator.com"))
+
+	tests := []struct {
+		name      string
+		address   string
+		want      string
+		wantCodes []string
+	}{
+		{
+			name:    "Valid",
+			address: "Test+Spam@Example.com",
+			want:    "Test@example.com",
+		},
+		{
+			name:      "Invalid",
+			address:   "abc",
+			wantCodes: []string{CodeInvalid},
+		},
+		{
+			name:      "Disposable",
+			address:   "test@Mailinator.com",
+			wantCodes: []string{CodeDomainBlocked},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, violations := validator.Validate("email", tt.address)
+			assert.Equal(t, tt.want, got)
+
+			var codes []string
+			for _, v := range violations {
+				assert.Equal(t, "email", v.Field)
+				codes = append(codes, v.Code)
+			}
+			assert.Equal(t, tt.wantCodes, codes)
+		})
+	}
+}
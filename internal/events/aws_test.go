@@ -0,0 +1,119 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	ebtypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/whatisusername/toon-tank-user-service/internal/testutil"
+)
+
+func TestAWSPublishers(t *testing.T) {
+	ctx := context.Background()
+
+	endpoint := testutil.LocalStackEndpoint(t)
+	sqsClient := testutil.NewSQSClient(t, endpoint)
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, queueURL, queueArn string) EventPublisher
+		// unwrap extracts the envelope from a message delivered to the queue.
+		unwrap func(t *testing.T, body string) string
+	}{
+		{
+			name: "SQS",
+			setup: func(t *testing.T, queueURL, queueArn string) EventPublisher {
+				return NewSQSPublisher(sqsClient, queueURL)
+			},
+			unwrap: func(t *testing.T, body string) string { return body },
+		},
+		{
+			name: "SNS",
+			setup: func(t *testing.T, queueURL, queueArn string) EventPublisher {
+				client := testutil.NewSNSClient(t, endpoint)
+
+				topic, err := client.CreateTopic(ctx, &sns.CreateTopicInput{Name: aws.String("user-events")})
+				require.NoError(t, err)
+				_, err = client.Subscribe(ctx, &sns.SubscribeInput{
+					TopicArn:   topic.TopicArn,
+					Protocol:   aws.String("sqs"),
+					Endpoint:   aws.String(queueArn),
+					Attributes: map[string]string{"RawMessageDelivery": "true"},
+				})
+				require.NoError(t, err)
+
+				return NewSNSPublisher(client, aws.ToString(topic.TopicArn))
+			},
+			unwrap: func(t *testing.T, body string) string { return body },
+		},
+		{
+			name: "EventBridge",
+			setup: func(t *testing.T, queueURL, queueArn string) EventPublisher {
+				client := testutil.NewEventBridgeClient(t, endpoint)
+
+				_, err := client.CreateEventBus(ctx, &eventbridge.CreateEventBusInput{Name: aws.String("user-events")})
+				require.NoError(t, err)
+				_, err = client.PutRule(ctx, &eventbridge.PutRuleInput{
+					Name:         aws.String("all-user-events"),
+					EventBusName: aws.String("user-events"),
+					EventPattern: aws.String(fmt.Sprintf(`{"source":[%q]}`, Source)),
+				})
+				require.NoError(t, err)
+				_, err = client.PutTargets(ctx, &eventbridge.PutTargetsInput{
+					Rule:         aws.String("all-user-events"),
+					EventBusName: aws.String("user-events"),
+					Targets:      []ebtypes.Target{{Id: aws.String("queue"), Arn: aws.String(queueArn)}},
+				})
+				require.NoError(t, err)
+
+				return NewEventBridgePublisher(client, "user-events")
+			},
+			unwrap: func(t *testing.T, body string) string {
+				var envelope struct {
+					DetailType string          `json:"detail-type"`
+					Detail     json.RawMessage `json:"detail"`
+				}
+				require.NoError(t, json.Unmarshal([]byte(body), &envelope))
+				assert.Equal(t, TypeUserSignedUp, envelope.DetailType)
+				return string(envelope.Detail)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queueURL, queueArn := testutil.CreateQueue(t, sqsClient, "events-"+tt.name)
+			publisher := tt.setup(t, queueURL, queueArn)
+
+			var published []Event
+			for i := 0; i < 12; i++ {
+				e, err := New(TypeUserSignedUp, 1, "fake_request_id", UserSignedUp{Username: fmt.Sprintf("test%d", i)})
+				require.NoError(t, err)
+				published = append(published, e)
+			}
+			require.NoError(t, publisher.Publish(ctx, published...))
+
+			bodies := testutil.ReceiveMessages(t, sqsClient, queueURL, len(published))
+
+			received := make(map[string]Event)
+			for _, body := range bodies {
+				var e Event
+				require.NoError(t, json.Unmarshal([]byte(tt.unwrap(t, body)), &e))
+				received[e.ID] = e
+			}
+			for _, e := range published {
+				got, ok := received[e.ID]
+				require.True(t, ok, "event %s was not delivered", e.ID)
+				assert.Equal(t, e.Type, got.Type)
+				assert.Equal(t, e.CorrelationID, got.CorrelationID)
+				assert.JSONEq(t, string(e.Data), string(got.Data))
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package events
+
+import "context"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID tags ctx with the ID of the request it serves, for events
+// built further down that can't see the request itself.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationID returns the ID stored in ctx, or "" outside of a request.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,111 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const Source = "toon-tank-user-service"
+
+const (
+	TypeUserSignedUp  = "user.signed_up"
+	TypeUserLoggedIn  = "user.logged_in"
+	TypeUserDeleted   = "user.deleted"
+	TypeUserConfirmed = "user.confirmed"
+	TypeGuestCreated  = "user.guest_created"
+	TypeUserUpgraded  = "user.upgraded"
+
+	TypeIdentityLinked   = "user.identity_linked"
+	TypeIdentityUnlinked = "user.identity_unlinked"
+
+	TypeFriendRequested        = "friend.requested"
+	TypeFriendRequestCancelled = "friend.request_cancelled"
+	TypeFriendRequestDeclined  = "friend.request_declined"
+	TypeFriendAdded            = "friend.added"
+	TypeFriendRemoved          = "friend.removed"
+	TypePlayerBlocked          = "player.blocked"
+	TypePlayerUnblocked        = "player.unblocked"
+)
+
+// Event is the envelope every event is published in. Version is bumped
+// whenever Data changes incompatibly, so consumers can keep handling the
+// versions they know. CorrelationID ties the event to the request that caused
+// it.
+type Event struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	Version       int             `json:"version"`
+	Source        string          `json:"source"`
+	Time          time.Time       `json:"time"`
+	CorrelationID string          `json:"correlationId,omitempty"`
+	Data          json.RawMessage `json:"data"`
+}
+
+type UserSignedUp struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	ClientID string `json:"clientId"`
+}
+
+type UserLoggedIn struct {
+	Username string `json:"username"`
+	ClientID string `json:"clientId"`
+}
+
+type UserDeleted struct {
+	Username string `json:"username"`
+}
+
+type UserConfirmed struct {
+	Username string `json:"username"`
+	PlayerID string `json:"playerId"`
+	Email    string `json:"email,omitempty"`
+}
+
+type GuestCreated struct {
+	Username string `json:"username"`
+	PlayerID string `json:"playerId"`
+	ClientID string `json:"clientId"`
+}
+
+// UserUpgraded is published when a guest becomes a full account. Username is
+// still the generated guest username; the chosen one is PreferredUsername.
+type UserUpgraded struct {
+	Username          string `json:"username"`
+	PreferredUsername string `json:"preferredUsername"`
+	PlayerID          string `json:"playerId"`
+	Email             string `json:"email"`
+}
+
+// IdentityLink is the payload of the identity events. Provider is the name of
+// the identity provider, e.g. Google.
+type IdentityLink struct {
+	Username string `json:"username"`
+	Provider string `json:"provider"`
+}
+
+// Friendship is the payload of the friend and block events. PlayerID is the
+// player who made the change and OtherPlayerID the one it was made to.
+type Friendship struct {
+	PlayerID      string `json:"playerId"`
+	OtherPlayerID string `json:"otherPlayerId"`
+}
+
+func New(eventType string, version int, correlationID string, data interface{}) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		ID:            uuid.NewString(),
+		Type:          eventType,
+		Version:       version,
+		Source:        Source,
+		Time:          time.Now().UTC(),
+		CorrelationID: correlationID,
+		Data:          raw,
+	}, nil
+}
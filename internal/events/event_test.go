@@ -0,0 +1,46 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	e, err := New(TypeUserSignedUp, 1, "fake_request_id", UserSignedUp{Username: "test", Email: "test@example.com", ClientID: "fake_client_id"})
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, e.ID)
+	assert.Equal(t, TypeUserSignedUp, e.Type)
+	assert.Equal(t, 1, e.Version)
+	assert.Equal(t, Source, e.Source)
+	assert.Equal(t, "fake_request_id", e.CorrelationID)
+	assert.JSONEq(t, `{"username":"test","email":"test@example.com","clientId":"fake_client_id"}`, string(e.Data))
+
+	encoded, err := json.Marshal(e)
+	require.NoError(t, err)
+
+	var decoded Event
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.True(t, e.Time.Equal(decoded.Time))
+	decoded.Time = e.Time
+	assert.Equal(t, e, decoded)
+}
+
+func Test_batches(t *testing.T) {
+	events := make([]Event, 23)
+	for i := range events {
+		events[i].ID = string(rune('a' + i))
+	}
+
+	got := batches(events, 10)
+	require.Len(t, got, 3)
+	assert.Len(t, got[0], 10)
+	assert.Len(t, got[1], 10)
+	assert.Len(t, got[2], 3)
+	assert.Equal(t, events[22], got[2][2])
+
+	assert.Empty(t, batches(nil, 10))
+}
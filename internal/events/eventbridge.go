@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+)
+
+const eventBridgeBatchSize = 10
+
+// EventBridgePublisher puts events on a bus with the event type as the
+// detail-type, so rules can route on it. The detail is the full envelope.
+type EventBridgePublisher struct {
+	client  *eventbridge.Client
+	busName string
+}
+
+func NewEventBridgePublisher(client *eventbridge.Client, busName string) *EventBridgePublisher {
+	return &EventBridgePublisher{
+		client:  client,
+		busName: busName,
+	}
+}
+
+func (p *EventBridgePublisher) Publish(ctx context.Context, events ...Event) error {
+	for _, batch := range batches(events, eventBridgeBatchSize) {
+		entries := make([]types.PutEventsRequestEntry, len(batch))
+		for i, e := range batch {
+			detail, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			entries[i] = types.PutEventsRequestEntry{
+				EventBusName: aws.String(p.busName),
+				Source:       aws.String(e.Source),
+				DetailType:   aws.String(e.Type),
+				Detail:       aws.String(string(detail)),
+				Time:         aws.Time(e.Time),
+			}
+		}
+
+		output, err := p.client.PutEvents(ctx, &eventbridge.PutEventsInput{Entries: entries})
+		if err != nil {
+			return err
+		}
+		if output.FailedEntryCount > 0 {
+			for _, entry := range output.Entries {
+				if entry.ErrorCode != nil {
+					return fmt.Errorf("failed to put %d events: %s: %s", output.FailedEntryCount, aws.ToString(entry.ErrorCode), aws.ToString(entry.ErrorMessage))
+				}
+			}
+			return fmt.Errorf("failed to put %d events", output.FailedEntryCount)
+		}
+	}
+	return nil
+}
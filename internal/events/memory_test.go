@@ -0,0 +1,27 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryPublisher(t *testing.T) {
+	publisher := NewMemoryPublisher()
+
+	first, err := New(TypeUserSignedUp, 1, "", UserSignedUp{Username: "test"})
+	require.NoError(t, err)
+	second, err := New(TypeUserLoggedIn, 1, "", UserLoggedIn{Username: "test"})
+	require.NoError(t, err)
+
+	require.NoError(t, publisher.Publish(context.Background(), first))
+	require.NoError(t, publisher.Publish(context.Background(), second))
+
+	got := publisher.Events()
+	assert.Equal(t, []Event{first, second}, got)
+
+	got[0].Type = "changed"
+	assert.Equal(t, TypeUserSignedUp, publisher.Events()[0].Type, "Events must return a copy")
+}
@@ -0,0 +1,28 @@
+package events
+
+import "context"
+
+// EventPublisher delivers events to other services. Publish either delivers
+// every event or returns an error; on error some events may still have been
+// delivered, so consumers must tolerate duplicates by event ID.
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...Event) error
+}
+
+type NoopPublisher struct{}
+
+func (NoopPublisher) Publish(context.Context, ...Event) error { return nil }
+
+// batches splits events into chunks of at most size, the per-call limit of
+// the AWS batch APIs.
+func batches(events []Event, size int) [][]Event {
+	var out [][]Event
+	for len(events) > size {
+		out = append(out, events[:size])
+		events = events[size:]
+	}
+	if len(events) > 0 {
+		out = append(out, events)
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+)
+
+const snsBatchSize = 10
+
+// SNSPublisher publishes the envelope as the message body, with the type and
+// version repeated as message attributes for subscription filter policies.
+type SNSPublisher struct {
+	client   *sns.Client
+	topicArn string
+}
+
+func NewSNSPublisher(client *sns.Client, topicArn string) *SNSPublisher {
+	return &SNSPublisher{
+		client:   client,
+		topicArn: topicArn,
+	}
+}
+
+func (p *SNSPublisher) Publish(ctx context.Context, events ...Event) error {
+	for _, batch := range batches(events, snsBatchSize) {
+		entries := make([]types.PublishBatchRequestEntry, len(batch))
+		for i, e := range batch {
+			body, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			entries[i] = types.PublishBatchRequestEntry{
+				Id:      aws.String(strconv.Itoa(i)),
+				Message: aws.String(string(body)),
+				MessageAttributes: map[string]types.MessageAttributeValue{
+					"type":    {DataType: aws.String("String"), StringValue: aws.String(e.Type)},
+					"version": {DataType: aws.String("Number"), StringValue: aws.String(strconv.Itoa(e.Version))},
+				},
+			}
+		}
+
+		output, err := p.client.PublishBatch(ctx, &sns.PublishBatchInput{
+			TopicArn:                   aws.String(p.topicArn),
+			PublishBatchRequestEntries: entries,
+		})
+		if err != nil {
+			return err
+		}
+		if len(output.Failed) > 0 {
+			f := output.Failed[0]
+			return fmt.Errorf("failed to publish %d events: %s: %s", len(output.Failed), aws.ToString(f.Code), aws.ToString(f.Message))
+		}
+	}
+	return nil
+}
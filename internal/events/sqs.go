@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const sqsBatchSize = 10
+
+// SQSPublisher sends the envelope as the message body, with the type and
+// version repeated as message attributes.
+type SQSPublisher struct {
+	client   *sqs.Client
+	queueURL string
+}
+
+func NewSQSPublisher(client *sqs.Client, queueURL string) *SQSPublisher {
+	return &SQSPublisher{
+		client:   client,
+		queueURL: queueURL,
+	}
+}
+
+func (p *SQSPublisher) Publish(ctx context.Context, events ...Event) error {
+	for _, batch := range batches(events, sqsBatchSize) {
+		entries := make([]types.SendMessageBatchRequestEntry, len(batch))
+		for i, e := range batch {
+			body, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			entries[i] = types.SendMessageBatchRequestEntry{
+				Id:          aws.String(strconv.Itoa(i)),
+				MessageBody: aws.String(string(body)),
+				MessageAttributes: map[string]types.MessageAttributeValue{
+					"type":    {DataType: aws.String("String"), StringValue: aws.String(e.Type)},
+					"version": {DataType: aws.String("Number"), StringValue: aws.String(strconv.Itoa(e.Version))},
+				},
+			}
+		}
+
+		output, err := p.client.SendMessageBatch(ctx, &sqs.SendMessageBatchInput{
+			QueueUrl: aws.String(p.queueURL),
+			Entries:  entries,
+		})
+		if err != nil {
+			return err
+		}
+		if len(output.Failed) > 0 {
+			f := output.Failed[0]
+			return fmt.Errorf("failed to send %d events: %s: %s", len(output.Failed), aws.ToString(f.Code), aws.ToString(f.Message))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package friends
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/outbox"
+)
+
+// StateIndex is the global secondary index List queries, keyed by
+// "playerState" and "otherId".
+const StateIndex = "state"
+
+type item struct {
+	PlayerID    string    `dynamodbav:"playerId"`
+	OtherID     string    `dynamodbav:"otherId"`
+	State       State     `dynamodbav:"state"`
+	PlayerState string    `dynamodbav:"playerState"`
+	Since       time.Time `dynamodbav:"since"`
+}
+
+// DynamoDBRepository keeps one item per edge in a table keyed by "playerId"
+// and "otherId". Both edges of a relationship are written in one transaction,
+// together with the change's events, which go to the outbox.
+type DynamoDBRepository struct {
+	client *dynamodb.Client
+	table  string
+	outbox *outbox.DynamoDBStore
+	now    func() time.Time
+}
+
+func NewDynamoDBRepository(client *dynamodb.Client, table string, outbox *outbox.DynamoDBStore) *DynamoDBRepository {
+	return &DynamoDBRepository{
+		client: client,
+		table:  table,
+		outbox: outbox,
+		now:    time.Now,
+	}
+}
+
+func (r *DynamoDBRepository) Get(ctx context.Context, playerID, otherID string) (Pair, error) {
+	output, err := r.client.TransactGetItems(ctx, &dynamodb.TransactGetItemsInput{
+		TransactItems: []types.TransactGetItem{
+			{Get: &types.Get{TableName: aws.String(r.table), Key: r.key(playerID, otherID)}},
+			{Get: &types.Get{TableName: aws.String(r.table), Key: r.key(otherID, playerID)}},
+		},
+	})
+	if err != nil {
+		return Pair{}, err
+	}
+
+	var mine, theirs item
+	if err = attributevalue.UnmarshalMap(output.Responses[0].Item, &mine); err != nil {
+		return Pair{}, err
+	}
+	if err = attributevalue.UnmarshalMap(output.Responses[1].Item, &theirs); err != nil {
+		return Pair{}, err
+	}
+	return Pair{Mine: mine.State, Theirs: theirs.State}, nil
+}
+
+func (r *DynamoDBRepository) Update(ctx context.Context, playerID, otherID string, expected, next Pair, events ...events.Event) error {
+	now := r.now().UTC()
+
+	mine, err := r.write(playerID, otherID, expected.Mine, next.Mine, now)
+	if err != nil {
+		return err
+	}
+	theirs, err := r.write(otherID, playerID, expected.Theirs, next.Theirs, now)
+	if err != nil {
+		return err
+	}
+	items := []types.TransactWriteItem{mine, theirs}
+
+	for _, e := range events {
+		put, err := r.outbox.TransactItem(e)
+		if err != nil {
+			return err
+		}
+		items = append(items, put)
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		return ErrConflict
+	}
+	return err
+}
+
+// write moves one edge from expected to next. An edge that stays in the same
+// state is only checked, so that its Since is kept.
+func (r *DynamoDBRepository) write(playerID, otherID string, expected, next State, now time.Time) (types.TransactWriteItem, error) {
+	condition := "attribute_not_exists(playerId)"
+	names := map[string]string(nil)
+	values := map[string]types.AttributeValue(nil)
+	if expected != StateNone {
+		condition = "#state = :expected"
+		names = map[string]string{"#state": "state"}
+		values = map[string]types.AttributeValue{":expected": &types.AttributeValueMemberS{Value: string(expected)}}
+	}
+
+	switch {
+	case next == expected:
+		return types.TransactWriteItem{ConditionCheck: &types.ConditionCheck{
+			TableName:                 aws.String(r.table),
+			Key:                       r.key(playerID, otherID),
+			ConditionExpression:       aws.String(condition),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		}}, nil
+	case next == StateNone:
+		return types.TransactWriteItem{Delete: &types.Delete{
+			TableName:                 aws.String(r.table),
+			Key:                       r.key(playerID, otherID),
+			ConditionExpression:       aws.String(condition),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		}}, nil
+	}
+
+	av, err := attributevalue.MarshalMap(item{
+		PlayerID:    playerID,
+		OtherID:     otherID,
+		State:       next,
+		PlayerState: playerState(playerID, next),
+		Since:       now,
+	})
+	if err != nil {
+		return types.TransactWriteItem{}, err
+	}
+	return types.TransactWriteItem{Put: &types.Put{
+		TableName:                 aws.String(r.table),
+		Item:                      av,
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}}, nil
+}
+
+func (r *DynamoDBRepository) List(ctx context.Context, playerID string, state State, limit int, cursor string) (Page, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.table),
+		IndexName:              aws.String(StateIndex),
+		KeyConditionExpression: aws.String("playerState = :playerState"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":playerState": &types.AttributeValueMemberS{Value: playerState(playerID, state)},
+		},
+		Limit: aws.Int32(int32(pageSize(limit))),
+	}
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		input.ExclusiveStartKey = r.key(playerID, after)
+		input.ExclusiveStartKey["playerState"] = &types.AttributeValueMemberS{Value: playerState(playerID, state)}
+	}
+
+	output, err := r.client.Query(ctx, input)
+	if err != nil {
+		return Page{}, err
+	}
+
+	var items []item
+	if err = attributevalue.UnmarshalListOfMaps(output.Items, &items); err != nil {
+		return Page{}, err
+	}
+
+	page := Page{Edges: make([]Edge, len(items))}
+	for i, it := range items {
+		page.Edges[i] = Edge{OtherID: it.OtherID, State: it.State, Since: it.Since}
+	}
+	if len(output.LastEvaluatedKey) > 0 && len(items) > 0 {
+		page.Cursor = encodeCursor(items[len(items)-1].OtherID)
+	}
+	return page, nil
+}
+
+func (r *DynamoDBRepository) key(playerID, otherID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"playerId": &types.AttributeValueMemberS{Value: playerID},
+		"otherId":  &types.AttributeValueMemberS{Value: otherID},
+	}
+}
+
+func playerState(playerID string, state State) string {
+	return playerID + "#" + string(state)
+}
@@ -0,0 +1,58 @@
+package friends
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/whatisusername/toon-tank-user-service/internal/outbox"
+	"github.com/whatisusername/toon-tank-user-service/internal/testutil"
+)
+
+func TestDynamoDBRepository(t *testing.T) {
+	endpoint := testutil.LocalStackEndpoint(t)
+	client := testutil.NewDynamoDBClient(t, endpoint)
+	createFriendsTable(t, client, "friends")
+	testutil.CreateTable(t, client, "outbox", "id", "")
+
+	repo := NewDynamoDBRepository(client, "friends", outbox.NewDynamoDBStore(client, "outbox"))
+	testRepository(t, repo, func(id string) bool {
+		output, err := client.GetItem(context.Background(), &dynamodb.GetItemInput{
+			TableName:      aws.String("outbox"),
+			Key:            map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}},
+			ConsistentRead: aws.Bool(true),
+		})
+		require.NoError(t, err)
+		return len(output.Item) > 0
+	})
+}
+
+func createFriendsTable(t *testing.T, client *dynamodb.Client, name string) {
+	t.Helper()
+
+	_, err := client.CreateTable(context.Background(), &dynamodb.CreateTableInput{
+		TableName: aws.String(name),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("playerId"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("otherId"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("playerState"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("playerId"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("otherId"), KeyType: types.KeyTypeRange},
+		},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{{
+			IndexName: aws.String(StateIndex),
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: aws.String("playerState"), KeyType: types.KeyTypeHash},
+				{AttributeName: aws.String("otherId"), KeyType: types.KeyTypeRange},
+			},
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		}},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+	require.NoError(t, err)
+}
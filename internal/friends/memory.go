@@ -0,0 +1,102 @@
+package friends
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/whatisusername/toon-tank-user-service/internal/events"
+)
+
+// MemoryRepository keeps the graph in memory and publishes the events of each
+// change before making it, so a failed publish leaves the graph untouched.
+type MemoryRepository struct {
+	mu        sync.Mutex
+	edges     map[string]map[string]Edge
+	publisher events.EventPublisher
+	now       func() time.Time
+}
+
+func NewMemoryRepository(publisher events.EventPublisher) *MemoryRepository {
+	return &MemoryRepository{
+		edges:     make(map[string]map[string]Edge),
+		publisher: publisher,
+		now:       time.Now,
+	}
+}
+
+func (r *MemoryRepository) Get(_ context.Context, playerID, otherID string) (Pair, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.pair(playerID, otherID), nil
+}
+
+func (r *MemoryRepository) Update(ctx context.Context, playerID, otherID string, expected, next Pair, events ...events.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.pair(playerID, otherID) != expected {
+		return ErrConflict
+	}
+	if len(events) > 0 {
+		if err := r.publisher.Publish(ctx, events...); err != nil {
+			return err
+		}
+	}
+
+	now := r.now()
+	r.set(playerID, otherID, next.Mine, expected.Mine, now)
+	r.set(otherID, playerID, next.Theirs, expected.Theirs, now)
+	return nil
+}
+
+func (r *MemoryRepository) List(_ context.Context, playerID string, state State, limit int, cursor string) (Page, error) {
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return Page{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var edges []Edge
+	for _, e := range r.edges[playerID] {
+		if e.State == state && e.OtherID > after {
+			edges = append(edges, e)
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].OtherID < edges[j].OtherID })
+
+	page := Page{Edges: edges}
+	if limit = pageSize(limit); len(edges) > limit {
+		page.Edges = edges[:limit]
+		page.Cursor = encodeCursor(edges[limit-1].OtherID)
+	}
+	return page, nil
+}
+
+// pair must be called with r.mu held.
+func (r *MemoryRepository) pair(playerID, otherID string) Pair {
+	return Pair{
+		Mine:   r.edges[playerID][otherID].State,
+		Theirs: r.edges[otherID][playerID].State,
+	}
+}
+
+// set must be called with r.mu held. An edge keeps its Since while its state
+// does not change.
+func (r *MemoryRepository) set(playerID, otherID string, state, previous State, now time.Time) {
+	if state == StateNone {
+		delete(r.edges[playerID], otherID)
+		return
+	}
+	if state == previous {
+		return
+	}
+	if r.edges[playerID] == nil {
+		r.edges[playerID] = make(map[string]Edge)
+	}
+	r.edges[playerID][otherID] = Edge{OtherID: otherID, State: state, Since: now}
+}
@@ -0,0 +1,15 @@
+package friends
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/whatisusername/toon-tank-user-service/internal/events"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	publisher := events.NewMemoryPublisher()
+	testRepository(t, NewMemoryRepository(publisher), func(id string) bool {
+		return slices.ContainsFunc(publisher.Events(), func(e events.Event) bool { return e.ID == id })
+	})
+}
@@ -0,0 +1,90 @@
+package friends
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/whatisusername/toon-tank-user-service/internal/events"
+)
+
+// State is how one player sees their relationship with another.
+type State string
+
+const (
+	StateNone     State = ""
+	StateFriends  State = "FRIENDS"
+	StateOutgoing State = "OUTGOING"
+	StateIncoming State = "INCOMING"
+	StateBlocked  State = "BLOCKED"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 100
+)
+
+var (
+	// ErrConflict is returned by Update when either side of the relationship
+	// is no longer in the expected state.
+	ErrConflict      = errors.New("relationship was modified concurrently")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// Pair is the relationship between two players as each of them sees it. A
+// friendship is FRIENDS on both sides and a request OUTGOING on the sender's
+// side and INCOMING on the recipient's. A block is only recorded on the
+// blocker's side.
+type Pair struct {
+	Mine   State
+	Theirs State
+}
+
+// Edge is one player's view of another player.
+type Edge struct {
+	OtherID string
+	State   State
+	Since   time.Time
+}
+
+// Page is one page of a list. Cursor is empty on the last page.
+type Page struct {
+	Edges  []Edge
+	Cursor string
+}
+
+// Repository stores the social graph as a pair of edges per relationship.
+//
+// Update moves the pair between playerID and otherID from expected to next
+// atomically, or fails with ErrConflict. The events describing the change are
+// sent on in the same step, so they go out if and only if the change is made.
+// List returns the edges of playerID in state, ordered by the other player's
+// ID, starting after cursor.
+type Repository interface {
+	Get(ctx context.Context, playerID, otherID string) (Pair, error)
+	Update(ctx context.Context, playerID, otherID string, expected, next Pair, events ...events.Event) error
+	List(ctx context.Context, playerID string, state State, limit int, cursor string) (Page, error)
+}
+
+func encodeCursor(otherID string) string {
+	if otherID == "" {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(otherID))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	otherID, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", ErrInvalidCursor
+	}
+	return string(otherID), nil
+}
+
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return DefaultPageSize
+	}
+	return min(limit, MaxPageSize)
+}
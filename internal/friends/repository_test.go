@@ -0,0 +1,114 @@
+package friends
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/whatisusername/toon-tank-user-service/internal/events"
+)
+
+// testRepository runs the behaviour every Repository must share. sent reports
+// whether the event with id went out.
+func testRepository(t *testing.T, repo Repository, sent func(id string) bool) {
+	ctx := context.Background()
+
+	t.Run("Update Both Sides", func(t *testing.T) {
+		require.NoError(t, repo.Update(ctx, "a", "b", Pair{}, Pair{Mine: StateOutgoing, Theirs: StateIncoming}))
+
+		got, err := repo.Get(ctx, "a", "b")
+		require.NoError(t, err)
+		assert.Equal(t, Pair{Mine: StateOutgoing, Theirs: StateIncoming}, got)
+
+		got, err = repo.Get(ctx, "b", "a")
+		require.NoError(t, err)
+		assert.Equal(t, Pair{Mine: StateIncoming, Theirs: StateOutgoing}, got)
+
+		require.NoError(t, repo.Update(ctx, "b", "a", got, Pair{}))
+		got, err = repo.Get(ctx, "a", "b")
+		require.NoError(t, err)
+		assert.Equal(t, Pair{}, got)
+	})
+
+	t.Run("Stale Expectation", func(t *testing.T) {
+		require.NoError(t, repo.Update(ctx, "c", "d", Pair{}, Pair{Mine: StateOutgoing, Theirs: StateIncoming}))
+
+		err := repo.Update(ctx, "c", "d", Pair{}, Pair{Mine: StateBlocked})
+		assert.ErrorIs(t, err, ErrConflict)
+		err = repo.Update(ctx, "c", "d", Pair{Mine: StateOutgoing}, Pair{Mine: StateBlocked})
+		assert.ErrorIs(t, err, ErrConflict, "both sides must match")
+
+		got, err := repo.Get(ctx, "c", "d")
+		require.NoError(t, err)
+		assert.Equal(t, Pair{Mine: StateOutgoing, Theirs: StateIncoming}, got, "a failed update changes nothing")
+	})
+
+	t.Run("Events Go With The Change", func(t *testing.T) {
+		e := newTestEvent(t, events.TypeFriendRequested)
+		require.NoError(t, repo.Update(ctx, "g", "h", Pair{}, Pair{Mine: StateOutgoing, Theirs: StateIncoming}, e))
+		assert.True(t, sent(e.ID))
+
+		stale := newTestEvent(t, events.TypePlayerBlocked)
+		err := repo.Update(ctx, "g", "h", Pair{}, Pair{Mine: StateBlocked}, stale)
+		assert.ErrorIs(t, err, ErrConflict)
+		assert.False(t, sent(stale.ID), "a failed update sends nothing")
+	})
+
+	t.Run("One Sided", func(t *testing.T) {
+		require.NoError(t, repo.Update(ctx, "e", "f", Pair{}, Pair{Mine: StateBlocked}))
+
+		got, err := repo.Get(ctx, "f", "e")
+		require.NoError(t, err)
+		assert.Equal(t, Pair{Theirs: StateBlocked}, got)
+	})
+
+	t.Run("List Pages", func(t *testing.T) {
+		var want []string
+		for i := 0; i < 5; i++ {
+			other := fmt.Sprintf("friend-%d", i)
+			want = append(want, other)
+			require.NoError(t, repo.Update(ctx, "lister", other, Pair{}, Pair{Mine: StateFriends, Theirs: StateFriends}))
+		}
+		require.NoError(t, repo.Update(ctx, "lister", "requested", Pair{}, Pair{Mine: StateOutgoing, Theirs: StateIncoming}))
+
+		var got []string
+		cursor := ""
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 5, "pagination does not terminate")
+
+			page, err := repo.List(ctx, "lister", StateFriends, 2, cursor)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(page.Edges), 2)
+			for _, e := range page.Edges {
+				assert.Equal(t, StateFriends, e.State)
+				assert.False(t, e.Since.IsZero())
+				got = append(got, e.OtherID)
+			}
+			if page.Cursor == "" {
+				break
+			}
+			cursor = page.Cursor
+		}
+		assert.Equal(t, want, got)
+
+		page, err := repo.List(ctx, "lister", StateOutgoing, 0, "")
+		require.NoError(t, err)
+		require.Len(t, page.Edges, 1)
+		assert.Equal(t, "requested", page.Edges[0].OtherID)
+	})
+
+	t.Run("Invalid Cursor", func(t *testing.T) {
+		_, err := repo.List(ctx, "lister", StateFriends, 2, "not base64!")
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
+
+func newTestEvent(t *testing.T, eventType string) events.Event {
+	t.Helper()
+
+	e, err := events.New(eventType, 1, "fake_request_id", events.Friendship{PlayerID: "g", OtherPlayerID: "h"})
+	require.NoError(t, err)
+	return e
+}
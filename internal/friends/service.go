@@ -0,0 +1,208 @@
+package friends
+
+import (
+	"context"
+	"errors"
+
+	"github.com/whatisusername/toon-tank-user-service/internal/events"
+)
+
+var (
+	ErrSelf           = errors.New("cannot be your own friend")
+	ErrBlocked        = errors.New("cannot send a friend request to this player")
+	ErrAlreadyFriends = errors.New("already friends")
+	ErrRequestExists  = errors.New("friend request already sent")
+	ErrNoRequest      = errors.New("no such friend request")
+	ErrNotFriends     = errors.New("not friends")
+	ErrNotBlocked     = errors.New("player is not blocked")
+)
+
+// Service applies the rules of the social graph on top of a Repository. Every
+// method reads the current relationship and writes the next one only if it is
+// still current, so concurrent changes fail with ErrConflict instead of
+// overwriting each other. Each change is written with an event naming it; a
+// method that leaves the relationship as it was writes and announces nothing.
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// SendRequest asks to befriend to. If to had already asked from, the two
+// become friends right away and StateFriends is returned; otherwise the
+// request is left pending and StateOutgoing is returned.
+func (s *Service) SendRequest(ctx context.Context, from, to string) (State, error) {
+	if from == to {
+		return StateNone, ErrSelf
+	}
+
+	return s.transition(ctx, from, to, sendRequestEvent, func(p Pair) (Pair, error) {
+		switch {
+		case p.Mine == StateBlocked || p.Theirs == StateBlocked:
+			return p, ErrBlocked
+		case p.Mine == StateFriends:
+			return p, ErrAlreadyFriends
+		case p.Mine == StateOutgoing:
+			return p, ErrRequestExists
+		case p.Mine == StateIncoming:
+			return Pair{Mine: StateFriends, Theirs: StateFriends}, nil
+		default:
+			return Pair{Mine: StateOutgoing, Theirs: StateIncoming}, nil
+		}
+	})
+}
+
+// Accept accepts the request player received from from.
+func (s *Service) Accept(ctx context.Context, player, from string) error {
+	_, err := s.transition(ctx, player, from, always(events.TypeFriendAdded), func(p Pair) (Pair, error) {
+		if p.Mine != StateIncoming {
+			return p, ErrNoRequest
+		}
+		return Pair{Mine: StateFriends, Theirs: StateFriends}, nil
+	})
+	return err
+}
+
+// Decline drops the request player received from from. The sender is not
+// told; their request simply disappears.
+func (s *Service) Decline(ctx context.Context, player, from string) error {
+	_, err := s.transition(ctx, player, from, always(events.TypeFriendRequestDeclined), func(p Pair) (Pair, error) {
+		if p.Mine != StateIncoming {
+			return p, ErrNoRequest
+		}
+		return Pair{}, nil
+	})
+	return err
+}
+
+// Cancel withdraws the request player sent to to.
+func (s *Service) Cancel(ctx context.Context, player, to string) error {
+	_, err := s.transition(ctx, player, to, always(events.TypeFriendRequestCancelled), func(p Pair) (Pair, error) {
+		if p.Mine != StateOutgoing {
+			return p, ErrNoRequest
+		}
+		return Pair{}, nil
+	})
+	return err
+}
+
+// Remove ends the friendship between player and friend on both sides.
+func (s *Service) Remove(ctx context.Context, player, friend string) error {
+	_, err := s.transition(ctx, player, friend, always(events.TypeFriendRemoved), func(p Pair) (Pair, error) {
+		if p.Mine != StateFriends {
+			return p, ErrNotFriends
+		}
+		return Pair{}, nil
+	})
+	return err
+}
+
+// Block ends any friendship or request between player and other and stops
+// other from sending player requests. A block other placed on player stays in
+// place.
+func (s *Service) Block(ctx context.Context, player, other string) error {
+	if player == other {
+		return ErrSelf
+	}
+
+	_, err := s.transition(ctx, player, other, always(events.TypePlayerBlocked), func(p Pair) (Pair, error) {
+		next := Pair{Mine: StateBlocked}
+		if p.Theirs == StateBlocked {
+			next.Theirs = StateBlocked
+		}
+		return next, nil
+	})
+	return err
+}
+
+func (s *Service) Unblock(ctx context.Context, player, other string) error {
+	_, err := s.transition(ctx, player, other, always(events.TypePlayerUnblocked), func(p Pair) (Pair, error) {
+		if p.Mine != StateBlocked {
+			return p, ErrNotBlocked
+		}
+		return Pair{Theirs: p.Theirs}, nil
+	})
+	return err
+}
+
+// removalEvents names how each of a removed player's edges ends.
+var removalEvents = map[State]string{
+	StateFriends:  events.TypeFriendRemoved,
+	StateOutgoing: events.TypeFriendRequestCancelled,
+	StateIncoming: events.TypeFriendRequestDeclined,
+	StateBlocked:  events.TypePlayerUnblocked,
+}
+
+// RemovePlayer clears every relationship player has, on both sides, for a
+// player that is going away: friendships end, requests either way are dropped
+// and player's blocks are lifted. Blocks other players placed on player are
+// only stored on their side and stay in their block lists.
+func (s *Service) RemovePlayer(ctx context.Context, player string) error {
+	for _, state := range []State{StateFriends, StateOutgoing, StateIncoming, StateBlocked} {
+		cursor := ""
+		for {
+			page, err := s.repo.List(ctx, player, state, MaxPageSize, cursor)
+			if err != nil {
+				return err
+			}
+			for _, edge := range page.Edges {
+				_, err = s.transition(ctx, player, edge.OtherID, always(removalEvents[state]), func(Pair) (Pair, error) {
+					return Pair{}, nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+			if page.Cursor == "" {
+				break
+			}
+			cursor = page.Cursor
+		}
+	}
+	return nil
+}
+
+func (s *Service) List(ctx context.Context, player string, state State, limit int, cursor string) (Page, error) {
+	return s.repo.List(ctx, player, state, limit, cursor)
+}
+
+// transition moves the relationship between player and other to what next
+// makes of it, written with an event of the type eventType names for the new
+// pair.
+func (s *Service) transition(ctx context.Context, player, other string, eventType func(Pair) string, next func(Pair) (Pair, error)) (State, error) {
+	current, err := s.repo.Get(ctx, player, other)
+	if err != nil {
+		return StateNone, err
+	}
+
+	p, err := next(current)
+	if err != nil {
+		return current.Mine, err
+	}
+	if p == current {
+		return p.Mine, nil
+	}
+
+	e, err := events.New(eventType(p), 1, events.CorrelationID(ctx), events.Friendship{PlayerID: player, OtherPlayerID: other})
+	if err != nil {
+		return current.Mine, err
+	}
+	if err = s.repo.Update(ctx, player, other, current, p, e); err != nil {
+		return current.Mine, err
+	}
+	return p.Mine, nil
+}
+
+// sendRequestEvent tells a new request from one that completed a friendship.
+func sendRequestEvent(p Pair) string {
+	if p.Mine == StateFriends {
+		return events.TypeFriendAdded
+	}
+	return events.TypeFriendRequested
+}
+
+func always(eventType string) func(Pair) string {
+	return func(Pair) string { return eventType }
+}
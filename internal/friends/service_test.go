@@ -0,0 +1,200 @@
+package friends
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/whatisusername/toon-tank-user-service/internal/events"
+)
+
+func TestService(t *testing.T) {
+	type step struct {
+		action  func(s *Service) error
+		wantErr error
+	}
+	send := func(from, to string) func(s *Service) error {
+		return func(s *Service) error {
+			_, err := s.SendRequest(context.Background(), from, to)
+			return err
+		}
+	}
+	accept := func(player, from string) func(s *Service) error {
+		return func(s *Service) error { return s.Accept(context.Background(), player, from) }
+	}
+	decline := func(player, from string) func(s *Service) error {
+		return func(s *Service) error { return s.Decline(context.Background(), player, from) }
+	}
+	cancel := func(player, to string) func(s *Service) error {
+		return func(s *Service) error { return s.Cancel(context.Background(), player, to) }
+	}
+	remove := func(player, friend string) func(s *Service) error {
+		return func(s *Service) error { return s.Remove(context.Background(), player, friend) }
+	}
+	block := func(player, other string) func(s *Service) error {
+		return func(s *Service) error { return s.Block(context.Background(), player, other) }
+	}
+	unblock := func(player, other string) func(s *Service) error {
+		return func(s *Service) error { return s.Unblock(context.Background(), player, other) }
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+		want  Pair
+	}{
+		{
+			name:  "Request",
+			steps: []step{{action: send("a", "b")}, {action: send("a", "b"), wantErr: ErrRequestExists}},
+			want:  Pair{Mine: StateOutgoing, Theirs: StateIncoming},
+		},
+		{
+			name:  "Accept",
+			steps: []step{{action: send("a", "b")}, {action: accept("a", "b"), wantErr: ErrNoRequest}, {action: accept("b", "a")}},
+			want:  Pair{Mine: StateFriends, Theirs: StateFriends},
+		},
+		{
+			name:  "Crossed Requests Become Friends",
+			steps: []step{{action: send("a", "b")}, {action: send("b", "a")}, {action: send("a", "b"), wantErr: ErrAlreadyFriends}},
+			want:  Pair{Mine: StateFriends, Theirs: StateFriends},
+		},
+		{
+			name:  "Decline",
+			steps: []step{{action: send("a", "b")}, {action: decline("b", "a")}, {action: decline("b", "a"), wantErr: ErrNoRequest}},
+			want:  Pair{},
+		},
+		{
+			name:  "Cancel",
+			steps: []step{{action: send("a", "b")}, {action: cancel("b", "a"), wantErr: ErrNoRequest}, {action: cancel("a", "b")}},
+			want:  Pair{},
+		},
+		{
+			name:  "Remove",
+			steps: []step{{action: remove("a", "b"), wantErr: ErrNotFriends}, {action: send("a", "b")}, {action: accept("b", "a")}, {action: remove("b", "a")}},
+			want:  Pair{},
+		},
+		{
+			name:  "Block Ends Friendship",
+			steps: []step{{action: send("a", "b")}, {action: accept("b", "a")}, {action: block("b", "a")}},
+			want:  Pair{Theirs: StateBlocked},
+		},
+		{
+			name:  "Blocked Cannot Request",
+			steps: []step{{action: block("b", "a")}, {action: send("a", "b"), wantErr: ErrBlocked}, {action: send("b", "a"), wantErr: ErrBlocked}},
+			want:  Pair{Theirs: StateBlocked},
+		},
+		{
+			name:  "Mutual Block",
+			steps: []step{{action: block("b", "a")}, {action: block("a", "b")}, {action: unblock("b", "a")}},
+			want:  Pair{Mine: StateBlocked},
+		},
+		{
+			name:  "Unblock",
+			steps: []step{{action: unblock("a", "b"), wantErr: ErrNotBlocked}, {action: block("a", "b")}, {action: unblock("a", "b")}, {action: send("a", "b")}},
+			want:  Pair{Mine: StateOutgoing, Theirs: StateIncoming},
+		},
+		{
+			name:  "Self",
+			steps: []step{{action: send("a", "a"), wantErr: ErrSelf}, {action: block("a", "a"), wantErr: ErrSelf}},
+			want:  Pair{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewMemoryRepository(events.NoopPublisher{})
+			s := NewService(repo)
+
+			for i, st := range tt.steps {
+				err := st.action(s)
+				if st.wantErr != nil {
+					assert.ErrorIs(t, err, st.wantErr, "step %d", i)
+				} else {
+					require.NoError(t, err, "step %d", i)
+				}
+			}
+
+			got, err := repo.Get(context.Background(), "a", "b")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_SendRequestState(t *testing.T) {
+	s := NewService(NewMemoryRepository(events.NoopPublisher{}))
+
+	state, err := s.SendRequest(context.Background(), "a", "b")
+	require.NoError(t, err)
+	assert.Equal(t, StateOutgoing, state)
+
+	state, err = s.SendRequest(context.Background(), "b", "a")
+	require.NoError(t, err)
+	assert.Equal(t, StateFriends, state)
+}
+
+func TestService_events(t *testing.T) {
+	ctx := context.Background()
+	publisher := events.NewMemoryPublisher()
+	s := NewService(NewMemoryRepository(publisher))
+
+	_, err := s.SendRequest(ctx, "a", "b")
+	require.NoError(t, err)
+	_, err = s.SendRequest(ctx, "b", "a")
+	require.NoError(t, err)
+	require.NoError(t, s.Block(ctx, "a", "b"))
+	require.NoError(t, s.Block(ctx, "a", "b"), "blocking twice is allowed")
+	assert.ErrorIs(t, s.Unblock(ctx, "b", "a"), ErrNotBlocked)
+
+	var types []string
+	for _, e := range publisher.Events() {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []string{events.TypeFriendRequested, events.TypeFriendAdded, events.TypePlayerBlocked}, types,
+		"only changes are announced")
+
+	var data events.Friendship
+	require.NoError(t, json.Unmarshal(publisher.Events()[1].Data, &data))
+	assert.Equal(t, events.Friendship{PlayerID: "b", OtherPlayerID: "a"}, data)
+}
+
+func TestService_RemovePlayer(t *testing.T) {
+	ctx := context.Background()
+	publisher := events.NewMemoryPublisher()
+	repo := NewMemoryRepository(publisher)
+	s := NewService(repo)
+
+	_, err := s.SendRequest(ctx, "gone", "friend")
+	require.NoError(t, err)
+	require.NoError(t, s.Accept(ctx, "friend", "gone"))
+	_, err = s.SendRequest(ctx, "gone", "asked")
+	require.NoError(t, err)
+	_, err = s.SendRequest(ctx, "asker", "gone")
+	require.NoError(t, err)
+	require.NoError(t, s.Block(ctx, "gone", "blocked"))
+	require.NoError(t, s.Block(ctx, "blocker", "gone"))
+
+	before := len(publisher.Events())
+	require.NoError(t, s.RemovePlayer(ctx, "gone"))
+
+	for _, other := range []string{"friend", "asked", "asker", "blocked"} {
+		p, err := repo.Get(ctx, "gone", other)
+		require.NoError(t, err)
+		assert.Equal(t, Pair{}, p, other)
+	}
+	p, err := repo.Get(ctx, "blocker", "gone")
+	require.NoError(t, err)
+	assert.Equal(t, Pair{Mine: StateBlocked}, p, "blocks placed on the player stay")
+
+	var types []string
+	for _, e := range publisher.Events()[before:] {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []string{
+		events.TypeFriendRemoved,
+		events.TypeFriendRequestCancelled,
+		events.TypeFriendRequestDeclined,
+		events.TypePlayerUnblocked,
+	}, types)
+}
@@ -0,0 +1,114 @@
+package guest
+
+import (
+	"context"
+	"time"
+
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+)
+
+// DefaultMaxAge is how long a guest account is kept after it was last used.
+const DefaultMaxAge = 30 * 24 * time.Hour
+
+// Result summarises one Clean run. Upgraded counts accounts that had been
+// upgraded but were still in the group and were taken out of it.
+type Result struct {
+	Deleted  int `json:"deleted"`
+	Upgraded int `json:"upgraded"`
+	Failed   int `json:"failed"`
+}
+
+// Cleaner deletes guest accounts that were never upgraded and have not been
+// signed in to for maxAge. Age is measured from LastSeenAttribute, or from
+// when the account was created if it has never been signed in to since.
+type Cleaner struct {
+	admin      caws.CognitoUserAdmin
+	userPoolID string
+	maxAge     time.Duration
+	publisher  events.EventPublisher
+	now        func() time.Time
+}
+
+func NewCleaner(admin caws.CognitoUserAdmin, userPoolID string, maxAge time.Duration, publisher events.EventPublisher) *Cleaner {
+	return &Cleaner{
+		admin:      admin,
+		userPoolID: userPoolID,
+		maxAge:     maxAge,
+		publisher:  publisher,
+		now:        time.Now,
+	}
+}
+
+// Clean deletes every stale guest. The group is listed in full before anything
+// is deleted so the deletions can't shift the pages. Members with
+// UpgradedAttribute set were upgraded by an upgrade that failed to leave the
+// group; they are taken out of it instead of deleted. A username or email on
+// its own does not count, since an upgrade in progress sets those first. A
+// user that fails either way is counted and left for the next run.
+func (c *Cleaner) Clean(ctx context.Context) (Result, error) {
+	logger := logging.FromContext(ctx)
+	cutoff := c.now().Add(-c.maxAge)
+
+	var stale, upgraded []string
+	err := c.admin.ListUsersInGroup(ctx, c.userPoolID, Group, func(u caws.CognitoUser) error {
+		switch {
+		case u.Attributes[UpgradedAttribute] != "":
+			upgraded = append(upgraded, u.Username)
+		case lastSeen(u).Before(cutoff):
+			stale = append(stale, u.Username)
+		}
+		return nil
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for _, username := range upgraded {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if err := c.admin.AdminRemoveUserFromGroup(ctx, c.userPoolID, username, Group); err != nil {
+			logger.Error("Failed to remove upgraded user from guests", "username", username, "error", err)
+			result.Failed++
+			continue
+		}
+		result.Upgraded++
+	}
+
+	for _, username := range stale {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if err := c.admin.AdminDeleteUser(ctx, c.userPoolID, username); err != nil {
+			logger.Error("Failed to delete guest", "username", username, "error", err)
+			result.Failed++
+			continue
+		}
+		result.Deleted++
+
+		e, err := events.New(events.TypeUserDeleted, 1, "", events.UserDeleted{Username: username})
+		if err == nil {
+			err = c.publisher.Publish(ctx, e)
+		}
+		if err != nil {
+			logger.Error("Failed to publish event", "type", events.TypeUserDeleted, "error", err)
+		}
+	}
+
+	logger.Info("Cleaned up guests", "deleted", result.Deleted, "upgraded", result.Upgraded, "failed", result.Failed)
+	return result, nil
+}
+
+// lastSeen is the last guest sign-in recorded on u, or when u was created.
+func lastSeen(u caws.CognitoUser) time.Time {
+	seen, err := ParseTime(u.Attributes[LastSeenAttribute])
+	if err != nil || seen.Before(u.Created) {
+		return u.Created
+	}
+	return seen
+}
@@ -0,0 +1,64 @@
+package guest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+	"github.com/whatisusername/toon-tank-user-service/internal/events"
+)
+
+func TestCleaner_Clean(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	users := []caws.CognitoUser{
+		{Username: "stale", Created: now.Add(-31 * 24 * time.Hour)},
+		{Username: "fresh", Created: now.Add(-time.Hour)},
+		{Username: "broken", Created: now.Add(-60 * 24 * time.Hour)},
+		{Username: "active", Created: now.Add(-60 * 24 * time.Hour), Attributes: map[string]string{LastSeenAttribute: FormatTime(now.Add(-24 * time.Hour))}},
+		{Username: "lapsed", Created: now.Add(-90 * 24 * time.Hour), Attributes: map[string]string{LastSeenAttribute: FormatTime(now.Add(-31 * 24 * time.Hour))}},
+		{Username: "upgrading", Created: now.Add(-60 * 24 * time.Hour), Attributes: map[string]string{"preferred_username": "commander", LastSeenAttribute: FormatTime(now.Add(-time.Hour))}},
+		{Username: "upgraded", Created: now.Add(-60 * 24 * time.Hour), Attributes: map[string]string{"preferred_username": "commander", UpgradedAttribute: FormatTime(now.Add(-59 * 24 * time.Hour))}},
+	}
+
+	admin := caws.NewMockCognitoUserAdmin(t)
+	admin.EXPECT().ListUsersInGroup(mock.Anything, "us-east-1_example", Group, mock.Anything).
+		RunAndReturn(func(_ context.Context, _, _ string, fn func(caws.CognitoUser) error) error {
+			for _, u := range users {
+				if err := fn(u); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	admin.EXPECT().AdminDeleteUser(mock.Anything, "us-east-1_example", "stale").Return(nil)
+	admin.EXPECT().AdminDeleteUser(mock.Anything, "us-east-1_example", "broken").Return(errors.New("throttled"))
+	admin.EXPECT().AdminRemoveUserFromGroup(mock.Anything, "us-east-1_example", "upgraded", Group).Return(nil)
+	admin.EXPECT().AdminDeleteUser(mock.Anything, "us-east-1_example", "lapsed").Return(nil)
+
+	publisher := events.NewMemoryPublisher()
+	c := NewCleaner(admin, "us-east-1_example", DefaultMaxAge, publisher)
+	c.now = func() time.Time { return now }
+
+	result, err := c.Clean(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Result{Deleted: 2, Upgraded: 1, Failed: 1}, result)
+
+	published := publisher.Events()
+	require.Len(t, published, 2)
+	assert.Equal(t, events.TypeUserDeleted, published[0].Type)
+	assert.JSONEq(t, `{"username":"stale"}`, string(published[0].Data))
+	assert.JSONEq(t, `{"username":"lapsed"}`, string(published[1].Data))
+}
+
+func TestCleaner_Clean_listFails(t *testing.T) {
+	admin := caws.NewMockCognitoUserAdmin(t)
+	admin.EXPECT().ListUsersInGroup(mock.Anything, "us-east-1_example", Group, mock.Anything).Return(errors.New("throttled"))
+
+	_, err := NewCleaner(admin, "us-east-1_example", DefaultMaxAge, events.NewMemoryPublisher()).Clean(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+package guest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Group is the Cognito group guest accounts belong to until they are upgraded.
+const Group = "guests"
+
+// Custom attributes the user pool must define for guest accounts. An upgrade
+// writes UpgradedAttribute as its last step, so a guest is only upgraded once
+// it is set. LastSeenAttribute is written at every guest sign-in, since
+// Cognito does not record sign-ins itself.
+const (
+	UpgradedAttribute = "custom:upgraded_at"
+	LastSeenAttribute = "custom:last_seen"
+)
+
+const usernamePrefix = "guest"
+
+// Credentials sign a guest in through the regular login. The client keeps them
+// on the device; there is no other way back into the account until it is
+// upgraded.
+type Credentials struct {
+	Username string
+	Secret   string
+}
+
+// NewCredentials generates a guest username and secret. The username is
+// alphanumeric so it passes login binding. The secret's fixed prefix covers any
+// character classes the pool's password policy asks for; its strength is in
+// the random part.
+func NewCredentials() (Credentials, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return Credentials{}, err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return Credentials{}, err
+	}
+
+	return Credentials{
+		Username: usernamePrefix + hex.EncodeToString(id),
+		Secret:   "Gg1-" + base64.RawURLEncoding.EncodeToString(secret),
+	}, nil
+}
+
+// IsUsername reports whether username has the shape NewCredentials generates.
+func IsUsername(username string) bool {
+	id, ok := strings.CutPrefix(username, usernamePrefix)
+	if !ok || len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil && strings.ToLower(id) == id
+}
+
+// FormatTime is how the guest attributes store a time: Unix seconds.
+func FormatTime(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+// ParseTime reads a time written by FormatTime.
+func ParseTime(s string) (time.Time, error) {
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(sec, 0).UTC(), nil
+}
@@ -0,0 +1,52 @@
+package guest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCredentials(t *testing.T) {
+	first, err := NewCredentials()
+	require.NoError(t, err)
+	second, err := NewCredentials()
+	require.NoError(t, err)
+
+	assert.True(t, IsUsername(first.Username))
+	assert.NotEqual(t, first.Username, second.Username)
+	assert.NotEqual(t, first.Secret, second.Secret)
+	assert.Len(t, first.Secret, 47)
+}
+
+func TestIsUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		want     bool
+	}{
+		{name: "Guest", username: "guest0123456789abcdef0123456789abcdef", want: true},
+		{name: "Player", username: "test"},
+		{name: "Short", username: "guest0123"},
+		{name: "Not Hex", username: "guestzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz"},
+		{name: "Upper Case", username: "guest0123456789ABCDEF0123456789ABCDEF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsUsername(tt.username))
+		})
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	assert.Equal(t, "1709296200", FormatTime(now))
+
+	got, err := ParseTime(FormatTime(now))
+	require.NoError(t, err)
+	assert.Equal(t, now, got)
+
+	_, err = ParseTime("")
+	assert.Error(t, err)
+}
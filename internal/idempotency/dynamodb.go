@@ -0,0 +1,169 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type item struct {
+	Key         string `dynamodbav:"key"`
+	RequestHash string `dynamodbav:"requestHash"`
+	Claim       string `dynamodbav:"claim,omitempty"`
+	State       State  `dynamodbav:"state"`
+	StatusCode  int    `dynamodbav:"statusCode,omitempty"`
+	Body        []byte `dynamodbav:"body,omitempty"`
+	ExpiresAt   int64  `dynamodbav:"expiresAt"`
+	PurgeAt     int64  `dynamodbav:"purgeAt"`
+}
+
+// DynamoDBStore shares idempotency records across Lambda instances. The table
+// needs a string partition key named "key" and should have TTL enabled on
+// "purgeAt". Records past expiresAt, in Unix milliseconds so that leases of a
+// few seconds are honoured exactly, are treated as gone even before TTL
+// removes them; purgeAt is the same time in the seconds TTL expects.
+type DynamoDBStore struct {
+	client *dynamodb.Client
+	table  string
+	lease  time.Duration
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewDynamoDBStore(client *dynamodb.Client, table string, lease, ttl time.Duration) *DynamoDBStore {
+	return &DynamoDBStore{
+		client: client,
+		table:  table,
+		lease:  lease,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+func (s *DynamoDBStore) Start(ctx context.Context, key, requestHash string) (string, *Record, error) {
+	claim, err := newClaim()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := s.now()
+	expiresAt := now.Add(s.lease)
+	av, err := attributevalue.MarshalMap(item{
+		Key:         key,
+		RequestHash: requestHash,
+		Claim:       claim,
+		State:       StateInProgress,
+		ExpiresAt:   expiresAt.UnixMilli(),
+		PurgeAt:     expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.table),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(#key) OR expiresAt <= :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#key": "key",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.UnixMilli(), 10)},
+		},
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+
+	if err == nil {
+		return claim, nil, nil
+	}
+	var condErr *types.ConditionalCheckFailedException
+	if !errors.As(err, &condErr) {
+		return "", nil, err
+	}
+
+	var existing item
+	if err = attributevalue.UnmarshalMap(condErr.Item, &existing); err != nil {
+		return "", nil, err
+	}
+	return "", existing.record(), nil
+}
+
+func (s *DynamoDBStore) Complete(ctx context.Context, key, claim string, statusCode int, body []byte) error {
+	expiresAt := s.now().Add(s.ttl)
+	values := map[string]types.AttributeValue{
+		":completed":  &types.AttributeValueMemberS{Value: string(StateCompleted)},
+		":statusCode": &types.AttributeValueMemberN{Value: strconv.Itoa(statusCode)},
+		":expiresAt":  &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.UnixMilli(), 10)},
+		":purgeAt":    &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)},
+	}
+	update := "SET #state = :completed, statusCode = :statusCode, expiresAt = :expiresAt, purgeAt = :purgeAt"
+	if len(body) > 0 {
+		values[":body"] = &types.AttributeValueMemberB{Value: body}
+		update += ", body = :body"
+	}
+
+	return s.updateOwned(ctx, key, claim, update, values)
+}
+
+func (s *DynamoDBStore) Release(ctx context.Context, key, claim string) error {
+	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:                 aws.String(s.table),
+		Key:                       s.key(key),
+		ConditionExpression:       aws.String(ownedCondition),
+		ExpressionAttributeNames:  map[string]string{"#state": "state"},
+		ExpressionAttributeValues: ownedValues(claim, nil),
+	})
+	return ownership(err)
+}
+
+const ownedCondition = "#state = :inProgress AND claim = :claim"
+
+func (s *DynamoDBStore) updateOwned(ctx context.Context, key, claim, update string, values map[string]types.AttributeValue) error {
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(s.table),
+		Key:                       s.key(key),
+		UpdateExpression:          aws.String(update),
+		ConditionExpression:       aws.String(ownedCondition),
+		ExpressionAttributeNames:  map[string]string{"#state": "state"},
+		ExpressionAttributeValues: ownedValues(claim, values),
+	})
+	return ownership(err)
+}
+
+func (s *DynamoDBStore) key(key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{"key": &types.AttributeValueMemberS{Value: key}}
+}
+
+func ownedValues(claim string, values map[string]types.AttributeValue) map[string]types.AttributeValue {
+	if values == nil {
+		values = make(map[string]types.AttributeValue, 2)
+	}
+	values[":inProgress"] = &types.AttributeValueMemberS{Value: string(StateInProgress)}
+	values[":claim"] = &types.AttributeValueMemberS{Value: claim}
+	return values
+}
+
+func ownership(err error) error {
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return ErrNotOwned
+	}
+	return err
+}
+
+func (i item) record() *Record {
+	return &Record{
+		Key:         i.Key,
+		RequestHash: i.RequestHash,
+		State:       i.State,
+		StatusCode:  i.StatusCode,
+		Body:        i.Body,
+		ExpiresAt:   time.UnixMilli(i.ExpiresAt),
+	}
+}
@@ -0,0 +1,20 @@
+package idempotency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/whatisusername/toon-tank-user-service/internal/testutil"
+)
+
+func TestDynamoDBStore(t *testing.T) {
+	endpoint := testutil.LocalStackEndpoint(t)
+	client := testutil.NewDynamoDBClient(t, endpoint)
+	testutil.CreateTable(t, client, "idempotency", "key", "")
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewDynamoDBStore(client, "idempotency", time.Minute, time.Hour)
+	store.now = func() time.Time { return now }
+
+	testStore(t, store, time.Minute, time.Hour, func(d time.Duration) { now = now.Add(d) })
+}
@@ -0,0 +1,92 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type MemoryStore struct {
+	lease   time.Duration
+	ttl     time.Duration
+	mu      sync.Mutex
+	records map[string]*memoryRecord
+	now     func() time.Time
+}
+
+type memoryRecord struct {
+	Record
+	claim string
+}
+
+func NewMemoryStore(lease, ttl time.Duration) *MemoryStore {
+	return &MemoryStore{
+		lease:   lease,
+		ttl:     ttl,
+		records: make(map[string]*memoryRecord),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Start(_ context.Context, key, requestHash string) (string, *Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if r, ok := s.records[key]; ok && now.Before(r.ExpiresAt) {
+		existing := r.Record
+		return "", &existing, nil
+	}
+
+	claim, err := newClaim()
+	if err != nil {
+		return "", nil, err
+	}
+	s.records[key] = &memoryRecord{
+		Record: Record{
+			Key:         key,
+			RequestHash: requestHash,
+			State:       StateInProgress,
+			ExpiresAt:   now.Add(s.lease),
+		},
+		claim: claim,
+	}
+	return claim, nil, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, key, claim string, statusCode int, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, err := s.owned(key, claim)
+	if err != nil {
+		return err
+	}
+
+	r.State = StateCompleted
+	r.StatusCode = statusCode
+	r.Body = append([]byte(nil), body...)
+	r.ExpiresAt = s.now().Add(s.ttl)
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, key, claim string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.owned(key, claim); err != nil {
+		return err
+	}
+	delete(s.records, key)
+	return nil
+}
+
+// owned returns the in-progress record for key if claim still holds it. The
+// caller must hold s.mu.
+func (s *MemoryStore) owned(key, claim string) (*memoryRecord, error) {
+	r, ok := s.records[key]
+	if !ok || r.State != StateInProgress || r.claim != claim {
+		return nil, ErrNotOwned
+	}
+	return r, nil
+}
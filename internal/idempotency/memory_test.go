@@ -0,0 +1,14 @@
+package idempotency
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(time.Minute, time.Hour)
+	store.now = func() time.Time { return now }
+
+	testStore(t, store, time.Minute, time.Hour, func(d time.Duration) { now = now.Add(d) })
+}
@@ -0,0 +1,58 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+type State string
+
+const (
+	StateInProgress State = "IN_PROGRESS"
+	StateCompleted  State = "COMPLETED"
+)
+
+// ErrNotOwned is returned when a record being completed or released was
+// reclaimed by another request, e.g. because it expired in the meantime.
+var ErrNotOwned = errors.New("idempotency record is not owned by this request")
+
+// Record is what is remembered about one idempotency key: a fingerprint of
+// the request that first used it and, once handled, the response to replay.
+type Record struct {
+	Key         string
+	RequestHash string
+	State       State
+	StatusCode  int
+	Body        []byte
+	ExpiresAt   time.Time
+}
+
+// Store remembers the outcome of requests by idempotency key.
+//
+// Start claims key for a request. If the caller now owns the key and must
+// handle the request, it returns a claim token and a nil record; otherwise it
+// returns the existing record. Complete stores the response to replay and
+// Release gives up the claim so that a retry can handle the request afresh.
+// Both take the claim token, so that only the request holding the claim can
+// settle it; a retry of the same request does not share its token.
+//
+// A claim only lasts for a short lease, so a request that dies without
+// completing or releasing holds its key up no longer than that; once the lease
+// runs out the next Start takes the key over. Completed records are kept for
+// the much longer TTL.
+type Store interface {
+	Start(ctx context.Context, key, requestHash string) (string, *Record, error)
+	Complete(ctx context.Context, key, claim string, statusCode int, body []byte) error
+	Release(ctx context.Context, key, claim string) error
+}
+
+func newClaim() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
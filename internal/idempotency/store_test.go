@@ -0,0 +1,127 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore runs the behaviour every Store must share. advance moves the
+// store's clock forward.
+func testStore(t *testing.T, store Store, lease, ttl time.Duration, advance func(d time.Duration)) {
+	ctx := context.Background()
+
+	start := func(t *testing.T, key, requestHash string) string {
+		t.Helper()
+		claim, existing, err := store.Start(ctx, key, requestHash)
+		require.NoError(t, err)
+		require.Nil(t, existing)
+		require.NotEmpty(t, claim)
+		return claim
+	}
+
+	t.Run("First Use Is Claimed", func(t *testing.T) {
+		claim, existing, err := store.Start(ctx, "first", "hash")
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+		assert.NotEmpty(t, claim)
+	})
+
+	t.Run("Concurrent Use Sees In Progress", func(t *testing.T) {
+		start(t, "concurrent", "hash")
+
+		claim, existing, err := store.Start(ctx, "concurrent", "hash")
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Empty(t, claim)
+		assert.Equal(t, StateInProgress, existing.State)
+		assert.Equal(t, "hash", existing.RequestHash)
+	})
+
+	t.Run("Completed Is Replayed", func(t *testing.T) {
+		claim := start(t, "completed", "hash")
+		require.NoError(t, store.Complete(ctx, "completed", claim, 201, []byte(`{"success":true}`)))
+
+		_, existing, err := store.Start(ctx, "completed", "other")
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Equal(t, StateCompleted, existing.State)
+		assert.Equal(t, "hash", existing.RequestHash)
+		assert.Equal(t, 201, existing.StatusCode)
+		assert.Equal(t, []byte(`{"success":true}`), existing.Body)
+	})
+
+	t.Run("Released Can Be Claimed Again", func(t *testing.T) {
+		claim := start(t, "released", "hash")
+		require.NoError(t, store.Release(ctx, "released", claim))
+
+		start(t, "released", "hash")
+	})
+
+	t.Run("Only Owner Completes", func(t *testing.T) {
+		claim := start(t, "owner", "hash")
+
+		assert.ErrorIs(t, store.Complete(ctx, "owner", "other", 201, nil), ErrNotOwned)
+		assert.ErrorIs(t, store.Release(ctx, "owner", "other"), ErrNotOwned)
+		require.NoError(t, store.Complete(ctx, "owner", claim, 201, nil))
+		assert.ErrorIs(t, store.Complete(ctx, "owner", claim, 201, nil), ErrNotOwned)
+	})
+
+	t.Run("Claims Are Not Shared By Identical Requests", func(t *testing.T) {
+		first := start(t, "identical", "hash")
+
+		advance(lease + time.Second)
+
+		second := start(t, "identical", "hash")
+		assert.NotEqual(t, first, second)
+		assert.ErrorIs(t, store.Complete(ctx, "identical", first, 201, nil), ErrNotOwned)
+		assert.ErrorIs(t, store.Release(ctx, "identical", first), ErrNotOwned)
+		require.NoError(t, store.Complete(ctx, "identical", second, 201, nil))
+	})
+
+	t.Run("Expired Can Be Claimed Again", func(t *testing.T) {
+		claim := start(t, "expired", "hash")
+		require.NoError(t, store.Complete(ctx, "expired", claim, 201, nil))
+
+		advance(ttl + time.Second)
+
+		start(t, "expired", "other")
+	})
+
+	t.Run("Abandoned In Progress Can Be Taken Over", func(t *testing.T) {
+		abandoned := start(t, "abandoned", "hash")
+
+		advance(lease + time.Second)
+
+		claim := start(t, "abandoned", "other")
+		assert.ErrorIs(t, store.Complete(ctx, "abandoned", abandoned, 201, nil), ErrNotOwned)
+		require.NoError(t, store.Complete(ctx, "abandoned", claim, 201, nil))
+	})
+
+	t.Run("Lease Is Kept To The Millisecond", func(t *testing.T) {
+		advance(500 * time.Millisecond)
+		start(t, "precise", "hash")
+
+		advance(lease - 100*time.Millisecond)
+
+		_, existing, err := store.Start(ctx, "precise", "other")
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Equal(t, StateInProgress, existing.State)
+	})
+
+	t.Run("Completed Outlives Lease", func(t *testing.T) {
+		claim := start(t, "outlives", "hash")
+		require.NoError(t, store.Complete(ctx, "outlives", claim, 201, nil))
+
+		advance(lease + time.Second)
+
+		_, existing, err := store.Start(ctx, "outlives", "other")
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Equal(t, StateCompleted, existing.State)
+	})
+}
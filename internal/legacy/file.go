@@ -0,0 +1,61 @@
+package legacy
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// FileStore serves users from a JSON Lines export of the legacy user table,
+// one User object per line, held in memory.
+type FileStore struct {
+	users map[string]User
+}
+
+func LoadFileStore(r io.Reader) (*FileStore, error) {
+	s := &FileStore{users: make(map[string]User)}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var u User
+		if err := json.Unmarshal([]byte(text), &u); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if u.Username == "" || u.PasswordHash == "" {
+			return nil, fmt.Errorf("line %d: username and passwordHash are required", line)
+		}
+		s.users[strings.ToLower(u.Username)] = u
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func LoadFileStoreFile(path string) (*FileStore, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadFileStore(f)
+}
+
+func (s *FileStore) FindUser(_ context.Context, username string) (*User, error) {
+	u, ok := s.users[strings.ToLower(username)]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return &u, nil
+}
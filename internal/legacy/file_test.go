@@ -0,0 +1,71 @@
+package legacy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	store, err := LoadFileStore(strings.NewReader(`
+{"username":"Test","email":"test@example.com","emailVerified":true,"passwordHash":"$2a$04$fake"}
+
+{"username":"other","email":"other@example.com","passwordHash":"$argon2id$fake"}
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		username string
+		want     *User
+		wantErr  error
+	}{
+		{
+			name:     "Found",
+			username: "Test",
+			want:     &User{Username: "Test", Email: "test@example.com", EmailVerified: true, PasswordHash: "$2a$04$fake"},
+		},
+		{
+			name:     "Case Insensitive",
+			username: "TEST",
+			want:     &User{Username: "Test", Email: "test@example.com", EmailVerified: true, PasswordHash: "$2a$04$fake"},
+		},
+		{
+			name:     "Not Found",
+			username: "missing",
+			wantErr:  ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.FindUser(context.Background(), tt.username)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadFileStore(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "Malformed Line",
+			input: `{"username":"test"`,
+		},
+		{
+			name:  "Missing Hash",
+			input: `{"username":"test","email":"test@example.com"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFileStore(strings.NewReader(tt.input))
+			assert.Error(t, err)
+		})
+	}
+}
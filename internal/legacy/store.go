@@ -0,0 +1,24 @@
+package legacy
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrUserNotFound = errors.New("legacy user not found")
+
+// User is an account from the prototype's user table. PasswordHash is in a
+// format token.VerifyPassword understands.
+type User struct {
+	Username      string `json:"username"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"emailVerified"`
+	PasswordHash  string `json:"passwordHash"`
+}
+
+// LegacyUserStore looks up accounts that have not been migrated to Cognito
+// yet. Usernames are matched case-insensitively, like Cognito does. Unknown
+// users are reported with ErrUserNotFound.
+type LegacyUserStore interface {
+	FindUser(ctx context.Context, username string) (*User, error)
+}
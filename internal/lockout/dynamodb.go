@@ -0,0 +1,148 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type record struct {
+	Key         string `dynamodbav:"key"`
+	Failures    int    `dynamodbav:"failures"`
+	LockedUntil int64  `dynamodbav:"lockedUntil,omitempty"`
+	ExpiresAt   int64  `dynamodbav:"expiresAt"`
+}
+
+// DynamoDBTracker shares failure counts across Lambda instances. The table
+// needs a string partition key named "key" and should have TTL enabled on
+// "expiresAt"; entries past expiresAt are treated as gone even before TTL
+// removes them.
+type DynamoDBTracker struct {
+	policy Policy
+	client *dynamodb.Client
+	table  string
+	now    func() time.Time
+}
+
+func NewDynamoDBTracker(policy Policy, client *dynamodb.Client, table string) *DynamoDBTracker {
+	return &DynamoDBTracker{
+		policy: policy,
+		client: client,
+		table:  table,
+		now:    time.Now,
+	}
+}
+
+func (t *DynamoDBTracker) Status(ctx context.Context, key string) (Status, error) {
+	output, err := t.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(t.table),
+		Key:            t.key(key),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return Status{}, err
+	}
+	if output.Item == nil {
+		return Status{}, nil
+	}
+
+	var r record
+	if err = attributevalue.UnmarshalMap(output.Item, &r); err != nil {
+		return Status{}, err
+	}
+	if r.ExpiresAt <= t.now().Unix() {
+		return Status{}, nil
+	}
+
+	return r.status(), nil
+}
+
+func (t *DynamoDBTracker) RecordFailure(ctx context.Context, key string) (Status, error) {
+	now := t.now()
+	expiresAt := strconv.FormatInt(now.Add(t.policy.Window).Unix(), 10)
+
+	output, err := t.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(t.table),
+		Key:                 t.key(key),
+		UpdateExpression:    aws.String("SET failures = if_not_exists(failures, :zero) + :one, expiresAt = :expiresAt"),
+		ConditionExpression: aws.String("attribute_not_exists(#key) OR expiresAt > :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#key": "key",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":zero":      &types.AttributeValueMemberN{Value: "0"},
+			":one":       &types.AttributeValueMemberN{Value: "1"},
+			":expiresAt": &types.AttributeValueMemberN{Value: expiresAt},
+			":now":       &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+
+	var r record
+	var condErr *types.ConditionalCheckFailedException
+	switch {
+	case errors.As(err, &condErr):
+		// The previous failures aged out of the window; start counting again.
+		r = record{Key: key, Failures: 1}
+		r.ExpiresAt, _ = strconv.ParseInt(expiresAt, 10, 64)
+		item, err := attributevalue.MarshalMap(r)
+		if err != nil {
+			return Status{}, err
+		}
+		if _, err = t.client.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(t.table), Item: item}); err != nil {
+			return Status{}, err
+		}
+	case err != nil:
+		return Status{}, err
+	default:
+		if err = attributevalue.UnmarshalMap(output.Attributes, &r); err != nil {
+			return Status{}, err
+		}
+	}
+
+	d := t.policy.LockDuration(r.Failures)
+	if d == 0 {
+		return r.status(), nil
+	}
+
+	r.LockedUntil = now.Add(d).UnixMilli()
+	_, err = t.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(t.table),
+		Key:              t.key(key),
+		UpdateExpression: aws.String("SET lockedUntil = :lockedUntil"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":lockedUntil": &types.AttributeValueMemberN{Value: strconv.FormatInt(r.LockedUntil, 10)},
+		},
+	})
+	if err != nil {
+		return Status{}, err
+	}
+
+	return r.status(), nil
+}
+
+func (t *DynamoDBTracker) Reset(ctx context.Context, key string) error {
+	_, err := t.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(t.table),
+		Key:       t.key(key),
+	})
+	return err
+}
+
+func (t *DynamoDBTracker) key(key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{"key": &types.AttributeValueMemberS{Value: key}}
+}
+
+func (r record) status() Status {
+	s := Status{Failures: r.Failures}
+	if r.LockedUntil > 0 {
+		s.LockedUntil = time.UnixMilli(r.LockedUntil)
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+package lockout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/whatisusername/toon-tank-user-service/internal/testutil"
+)
+
+func TestDynamoDBTracker(t *testing.T) {
+	ctx := context.Background()
+
+	endpoint := testutil.LocalStackEndpoint(t)
+	client := testutil.NewDynamoDBClient(t, endpoint)
+	testutil.CreateTable(t, client, "login-failures", "key", "")
+
+	policy := Policy{Threshold: 2, BaseLock: time.Minute, MaxLock: time.Hour, Window: 2 * time.Hour}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		action  func(tracker *DynamoDBTracker) (Status, error)
+		want    Status
+	}{
+		{
+			name:    "Unknown Account",
+			elapsed: 0,
+			action:  func(tracker *DynamoDBTracker) (Status, error) { return tracker.Status(ctx, "test") },
+			want:    Status{},
+		},
+		{
+			name:    "First Failure",
+			elapsed: 0,
+			action:  func(tracker *DynamoDBTracker) (Status, error) { return tracker.RecordFailure(ctx, "test") },
+			want:    Status{Failures: 1},
+		},
+		{
+			name:    "Locked At Threshold",
+			elapsed: 0,
+			action:  func(tracker *DynamoDBTracker) (Status, error) { return tracker.RecordFailure(ctx, "test") },
+			want:    Status{Failures: 2, LockedUntil: start.Add(time.Minute)},
+		},
+		{
+			name:    "Lock Grows",
+			elapsed: time.Minute,
+			action:  func(tracker *DynamoDBTracker) (Status, error) { return tracker.RecordFailure(ctx, "test") },
+			want:    Status{Failures: 3, LockedUntil: start.Add(3 * time.Minute)},
+		},
+		{
+			name:    "Status Reports Lock",
+			elapsed: 0,
+			action:  func(tracker *DynamoDBTracker) (Status, error) { return tracker.Status(ctx, "test") },
+			want:    Status{Failures: 3, LockedUntil: start.Add(3 * time.Minute)},
+		},
+		{
+			name:    "Forgotten After Window",
+			elapsed: 3 * time.Hour,
+			action:  func(tracker *DynamoDBTracker) (Status, error) { return tracker.RecordFailure(ctx, "test") },
+			want:    Status{Failures: 1},
+		},
+		{
+			name:    "Reset",
+			elapsed: 0,
+			action: func(tracker *DynamoDBTracker) (Status, error) {
+				if err := tracker.Reset(ctx, "test"); err != nil {
+					return Status{}, err
+				}
+				return tracker.Status(ctx, "test")
+			},
+			want: Status{},
+		},
+	}
+
+	now := start
+	tracker := NewDynamoDBTracker(policy, client, "login-failures")
+	tracker.now = func() time.Time { return now }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.elapsed)
+
+			got, err := tt.action(tracker)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.Failures, got.Failures)
+			assert.True(t, tt.want.LockedUntil.Equal(got.LockedUntil), "expected lock until %v, got %v", tt.want.LockedUntil, got.LockedUntil)
+		})
+	}
+}
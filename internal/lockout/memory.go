@@ -0,0 +1,79 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	status      Status
+	lastFailure time.Time
+}
+
+type MemoryTracker struct {
+	policy  Policy
+	mu      sync.Mutex
+	entries map[string]*entry
+	now     func() time.Time
+}
+
+func NewMemoryTracker(policy Policy) *MemoryTracker {
+	return &MemoryTracker{
+		policy:  policy,
+		entries: make(map[string]*entry),
+		now:     time.Now,
+	}
+}
+
+func (t *MemoryTracker) Status(_ context.Context, key string) (Status, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if e := t.current(key); e != nil {
+		return e.status, nil
+	}
+	return Status{}, nil
+}
+
+func (t *MemoryTracker) RecordFailure(_ context.Context, key string) (Status, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	e := t.current(key)
+	if e == nil {
+		e = &entry{}
+		t.entries[key] = e
+	}
+
+	e.status.Failures++
+	e.lastFailure = now
+	if d := t.policy.LockDuration(e.status.Failures); d > 0 {
+		e.status.LockedUntil = now.Add(d)
+	}
+
+	return e.status, nil
+}
+
+func (t *MemoryTracker) Reset(_ context.Context, key string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.entries, key)
+	return nil
+}
+
+// current returns the entry for key unless it has aged out of the window.
+// The caller must hold t.mu.
+func (t *MemoryTracker) current(key string) *entry {
+	e, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	if t.now().Sub(e.lastFailure) > t.policy.Window {
+		delete(t.entries, key)
+		return nil
+	}
+	return e
+}
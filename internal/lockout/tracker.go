@@ -0,0 +1,57 @@
+package lockout
+
+import (
+	"context"
+	"time"
+)
+
+// Policy locks an account once Threshold consecutive failures have been
+// recorded. The first lock lasts BaseLock and every further failure doubles
+// it, up to MaxLock. Failures are forgotten after Window without new ones.
+type Policy struct {
+	Threshold int
+	BaseLock  time.Duration
+	MaxLock   time.Duration
+	Window    time.Duration
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		Threshold: 5,
+		BaseLock:  time.Minute,
+		MaxLock:   24 * time.Hour,
+		Window:    24 * time.Hour,
+	}
+}
+
+func (p Policy) LockDuration(failures int) time.Duration {
+	if failures < p.Threshold {
+		return 0
+	}
+
+	d := p.BaseLock
+	for i := p.Threshold; i < failures; i++ {
+		d *= 2
+		if d >= p.MaxLock {
+			return p.MaxLock
+		}
+	}
+	return d
+}
+
+type Status struct {
+	Failures    int
+	LockedUntil time.Time
+}
+
+func (s Status) Locked(now time.Time) bool {
+	return now.Before(s.LockedUntil)
+}
+
+// Tracker counts failed logins per account key. Reset clears the count and any
+// active lock, both after a successful login and when an admin unlocks early.
+type Tracker interface {
+	Status(ctx context.Context, key string) (Status, error)
+	RecordFailure(ctx context.Context, key string) (Status, error)
+	Reset(ctx context.Context, key string) error
+}
@@ -0,0 +1,55 @@
+package lockout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_LockDuration(t *testing.T) {
+	policy := Policy{Threshold: 5, BaseLock: time.Minute, MaxLock: 10 * time.Minute, Window: time.Hour}
+
+	tests := []struct {
+		name     string
+		failures int
+		want     time.Duration
+	}{
+		{
+			name:     "Below Threshold",
+			failures: 4,
+			want:     0,
+		},
+		{
+			name:     "At Threshold",
+			failures: 5,
+			want:     time.Minute,
+		},
+		{
+			name:     "Doubles",
+			failures: 6,
+			want:     2 * time.Minute,
+		},
+		{
+			name:     "Doubles Again",
+			failures: 8,
+			want:     8 * time.Minute,
+		},
+		{
+			name:     "Capped",
+			failures: 9,
+			want:     10 * time.Minute,
+		},
+		{
+			name:     "Stays Capped",
+			failures: 100,
+			want:     10 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.LockDuration(tt.failures)
+			assert.Equal(t, tt.want, got, "LockDuration() returned unexpected result")
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type ctxKey struct{}
+
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger stored in ctx, falling back to
+// the default logger outside of a request.
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
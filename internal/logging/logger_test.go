@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	scoped := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)).With("requestId", "fake_request_id")
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want *slog.Logger
+	}{
+		{
+			name: "Scoped Logger",
+			ctx:  WithLogger(context.Background(), scoped),
+			want: scoped,
+		},
+		{
+			name: "Default Logger",
+			ctx:  context.Background(),
+			want: slog.Default(),
+		},
+		{
+			name: "Nil Context",
+			ctx:  nil,
+			want: slog.Default(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromContext(tt.ctx)
+			assert.Same(t, tt.want, got, "FromContext() returned unexpected result")
+		})
+	}
+}
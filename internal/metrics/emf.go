@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EMFRecorder writes each sample as a CloudWatch Embedded Metric Format log
+// line. Lambda ships stdout to CloudWatch Logs, which extracts the metrics
+// without a separate agent.
+type EMFRecorder struct {
+	namespace string
+	mu        sync.Mutex
+	w         io.Writer
+	now       func() time.Time
+}
+
+func NewEMFRecorder(namespace string, w io.Writer) *EMFRecorder {
+	return &EMFRecorder{
+		namespace: namespace,
+		w:         w,
+		now:       time.Now,
+	}
+}
+
+type emfMetricDefinition struct {
+	Name string `json:"Name"`
+	Unit Unit   `json:"Unit"`
+}
+
+type emfDirective struct {
+	Namespace  string                `json:"Namespace"`
+	Dimensions [][]string            `json:"Dimensions"`
+	Metrics    []emfMetricDefinition `json:"Metrics"`
+}
+
+type emfMetadata struct {
+	Timestamp         int64          `json:"Timestamp"`
+	CloudWatchMetrics []emfDirective `json:"CloudWatchMetrics"`
+}
+
+func (r *EMFRecorder) Count(ctx context.Context, name string, value float64, dims Dimensions) {
+	r.write(ctx, name, value, UnitCount, dims)
+}
+
+func (r *EMFRecorder) Observe(ctx context.Context, name string, value float64, unit Unit, dims Dimensions) {
+	r.write(ctx, name, value, unit, dims)
+}
+
+func (r *EMFRecorder) write(ctx context.Context, name string, value float64, unit Unit, dims Dimensions) {
+	keys := make([]string, 0, len(dims))
+	for k := range dims {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	line := make(map[string]interface{}, len(dims)+2)
+	for k, v := range dims {
+		line[k] = v
+	}
+	line[name] = value
+	line["_aws"] = emfMetadata{
+		Timestamp: r.now().UnixMilli(),
+		CloudWatchMetrics: []emfDirective{
+			{
+				Namespace:  r.namespace,
+				Dimensions: [][]string{keys},
+				Metrics:    []emfMetricDefinition{{Name: name, Unit: unit}},
+			},
+		},
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to encode metric", "metric", name, "error", err)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err = r.w.Write(append(data, '\n')); err != nil {
+		slog.ErrorContext(ctx, "Failed to write metric", "metric", name, "error", err)
+	}
+}
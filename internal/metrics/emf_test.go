@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEMFRecorder(t *testing.T) {
+	type args struct {
+		name  string
+		value float64
+		unit  Unit
+		dims  Dimensions
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Counter",
+			args: args{
+				name:  "Login",
+				value: 1,
+				unit:  UnitCount,
+				dims:  Dimensions{"Outcome": "failure", "Reason": "NotAuthorizedException"},
+			},
+			want: `{"Login":1,"Outcome":"failure","Reason":"NotAuthorizedException","_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Namespace":"ToonTank/UserService","Dimensions":[["Outcome","Reason"]],"Metrics":[{"Name":"Login","Unit":"Count"}]}]}}` + "\n",
+		},
+		{
+			name: "Histogram",
+			args: args{
+				name:  "LoginLatency",
+				value: 12.5,
+				unit:  UnitMilliseconds,
+				dims:  Dimensions{"Outcome": "success"},
+			},
+			want: `{"LoginLatency":12.5,"Outcome":"success","_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Namespace":"ToonTank/UserService","Dimensions":[["Outcome"]],"Metrics":[{"Name":"LoginLatency","Unit":"Milliseconds"}]}]}}` + "\n",
+		},
+		{
+			name: "No Dimensions",
+			args: args{
+				name:  "SignUp",
+				value: 1,
+				unit:  UnitCount,
+				dims:  nil,
+			},
+			want: `{"SignUp":1,"_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Namespace":"ToonTank/UserService","Dimensions":[[]],"Metrics":[{"Name":"SignUp","Unit":"Count"}]}]}}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			r := NewEMFRecorder("ToonTank/UserService", &buf)
+			r.now = func() time.Time { return time.UnixMilli(1700000000000) }
+
+			if tt.args.unit == UnitCount {
+				r.Count(context.Background(), tt.args.name, tt.args.value, tt.args.dims)
+			} else {
+				r.Observe(context.Background(), tt.args.name, tt.args.value, tt.args.unit, tt.args.dims)
+			}
+
+			assert.Equal(t, tt.want, buf.String(), "EMFRecorder wrote unexpected line")
+		})
+	}
+}
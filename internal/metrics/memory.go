@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+)
+
+type Sample struct {
+	Name       string
+	Value      float64
+	Unit       Unit
+	Dimensions Dimensions
+}
+
+// MemoryRecorder keeps every sample in memory so tests can assert on them.
+type MemoryRecorder struct {
+	mu      sync.Mutex
+	samples []Sample
+}
+
+func NewMemoryRecorder() *MemoryRecorder {
+	return &MemoryRecorder{}
+}
+
+func (r *MemoryRecorder) Count(_ context.Context, name string, value float64, dims Dimensions) {
+	r.add(Sample{Name: name, Value: value, Unit: UnitCount, Dimensions: dims})
+}
+
+func (r *MemoryRecorder) Observe(_ context.Context, name string, value float64, unit Unit, dims Dimensions) {
+	r.add(Sample{Name: name, Value: value, Unit: unit, Dimensions: dims})
+}
+
+func (r *MemoryRecorder) add(s Sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples = append(r.samples, s)
+}
+
+func (r *MemoryRecorder) Samples() []Sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Sample(nil), r.samples...)
+}
+
+// Sum adds up the values of every sample named name whose dimensions include
+// all of dims.
+func (r *MemoryRecorder) Sum(name string, dims Dimensions) float64 {
+	var total float64
+	for _, s := range r.Samples() {
+		if s.Name == name && matches(s.Dimensions, dims) {
+			total += s.Value
+		}
+	}
+	return total
+}
+
+func matches(got, want Dimensions) bool {
+	for k, v := range want {
+		if got[k] != v {
+			return false
+		}
+	}
+	return true
+}
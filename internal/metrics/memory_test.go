@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRecorder_Sum(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryRecorder()
+	r.Count(ctx, "Login", 1, Dimensions{"Outcome": "success"})
+	r.Count(ctx, "Login", 1, Dimensions{"Outcome": "failure", "Reason": "NotAuthorizedException"})
+	r.Count(ctx, "Login", 1, Dimensions{"Outcome": "failure", "Reason": "UserNotConfirmedException"})
+	r.Observe(ctx, "LoginLatency", 12.5, UnitMilliseconds, Dimensions{"Outcome": "success"})
+
+	tests := []struct {
+		name   string
+		metric string
+		dims   Dimensions
+		want   float64
+	}{
+		{
+			name:   "All Samples",
+			metric: "Login",
+			dims:   nil,
+			want:   3,
+		},
+		{
+			name:   "Matching Dimension",
+			metric: "Login",
+			dims:   Dimensions{"Outcome": "failure"},
+			want:   2,
+		},
+		{
+			name:   "Matching Dimensions",
+			metric: "Login",
+			dims:   Dimensions{"Outcome": "failure", "Reason": "NotAuthorizedException"},
+			want:   1,
+		},
+		{
+			name:   "Histogram",
+			metric: "LoginLatency",
+			dims:   Dimensions{"Outcome": "success"},
+			want:   12.5,
+		},
+		{
+			name:   "Unknown Metric",
+			metric: "SignUp",
+			dims:   nil,
+			want:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.Sum(tt.metric, tt.dims)
+			assert.Equal(t, tt.want, got, "Sum() returned unexpected result")
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"context"
+	"time"
+)
+
+type Unit string
+
+const (
+	UnitCount        Unit = "Count"
+	UnitMilliseconds Unit = "Milliseconds"
+)
+
+type Dimensions map[string]string
+
+// Recorder records counters and distributions. Implementations must be safe
+// for concurrent use.
+type Recorder interface {
+	Count(ctx context.Context, name string, value float64, dims Dimensions)
+	Observe(ctx context.Context, name string, value float64, unit Unit, dims Dimensions)
+}
+
+func ObserveDuration(ctx context.Context, r Recorder, name string, start time.Time, dims Dimensions) {
+	r.Observe(ctx, name, float64(time.Since(start).Microseconds())/1000, UnitMilliseconds, dims)
+}
+
+type NoopRecorder struct{}
+
+func (NoopRecorder) Count(context.Context, string, float64, Dimensions) {}
+
+func (NoopRecorder) Observe(context.Context, string, float64, Unit, Dimensions) {}
@@ -0,0 +1,121 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+)
+
+// ErrInvalidGrant is returned when the token endpoint refuses the code, e.g.
+// because it was already used, has expired or does not match the verifier.
+var ErrInvalidGrant = errors.New("authorization code was rejected")
+
+// requestTimeout bounds a call to the token endpoint, so a slow user pool
+// domain fails the callback instead of holding it until the function times out.
+const requestTimeout = 5 * time.Second
+
+// AuthorizeRequest is one sign-in through the hosted UI. An empty
+// IdentityProvider lets the player pick on the hosted UI itself.
+// CodeChallenge is the S256 challenge of a verifier only the game client holds.
+type AuthorizeRequest struct {
+	ClientID         string
+	RedirectURI      string
+	State            string
+	CodeChallenge    string
+	Scopes           []string
+	IdentityProvider string
+}
+
+// Client talks to the OAuth 2.0 endpoints of a user pool domain.
+type Client struct {
+	domain string
+	client *http.Client
+}
+
+// NewClient takes the base URL of the user pool domain, e.g.
+// https://example.auth.us-east-1.amazoncognito.com.
+func NewClient(domain string) *Client {
+	return &Client{
+		domain: strings.TrimSuffix(domain, "/"),
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// AuthorizeURL is where the player's browser is sent to sign in.
+func (c *Client) AuthorizeURL(req AuthorizeRequest) string {
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {req.ClientID},
+		"redirect_uri":          {req.RedirectURI},
+		"state":                 {req.State},
+		"code_challenge":        {req.CodeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+	if len(req.Scopes) > 0 {
+		query.Set("scope", strings.Join(req.Scopes, " "))
+	}
+	if req.IdentityProvider != "" {
+		query.Set("identity_provider", req.IdentityProvider)
+	}
+	return c.domain + "/oauth2/authorize?" + query.Encode()
+}
+
+type tokenResponse struct {
+	IdToken      string `json:"id_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	Error        string `json:"error"`
+}
+
+// Exchange trades an authorization code for tokens. redirectURI must be the
+// one the code was requested with. Confidential clients authenticate with
+// HTTP Basic, as the token endpoint expects.
+func (c *Client) Exchange(ctx context.Context, clientID, clientSecret, code, redirectURI, verifier string) (*caws.CognitoToken, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {clientID},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.domain+"/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result tokenResponse
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest && result.Error == "invalid_grant":
+		return nil, ErrInvalidGrant
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unexpected status from token endpoint: %d %s", resp.StatusCode, result.Error)
+	}
+
+	return &caws.CognitoToken{
+		IdToken:      result.IdToken,
+		AccessToken:  result.AccessToken,
+		RefreshToken: result.RefreshToken,
+	}, nil
+}
@@ -0,0 +1,113 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+)
+
+func TestClient_AuthorizeURL(t *testing.T) {
+	c := NewClient("https://example.auth.us-east-1.amazoncognito.com/")
+	got, err := url.Parse(c.AuthorizeURL(AuthorizeRequest{
+		ClientID:         "fake_client_id",
+		RedirectURI:      "https://example.com/callback",
+		State:            "fake_state",
+		CodeChallenge:    "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		Scopes:           []string{"openid", "email"},
+		IdentityProvider: "Google",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.auth.us-east-1.amazoncognito.com", got.Host)
+	assert.Equal(t, "/oauth2/authorize", got.Path)
+	assert.Equal(t, url.Values{
+		"response_type":         {"code"},
+		"client_id":             {"fake_client_id"},
+		"redirect_uri":          {"https://example.com/callback"},
+		"state":                 {"fake_state"},
+		"code_challenge":        {"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"},
+		"code_challenge_method": {"S256"},
+		"scope":                 {"openid email"},
+		"identity_provider":     {"Google"},
+	}, got.Query())
+}
+
+func TestClient_Exchange(t *testing.T) {
+	tests := []struct {
+		name         string
+		clientSecret string
+		status       int
+		response     interface{}
+		want         *caws.CognitoToken
+		wantErr      error
+	}{
+		{
+			name:     "Public Client",
+			status:   http.StatusOK,
+			response: map[string]string{"id_token": "fake_id_token", "access_token": "fake_access_token", "refresh_token": "fake_refresh_token"},
+			want:     &caws.CognitoToken{IdToken: "fake_id_token", AccessToken: "fake_access_token", RefreshToken: "fake_refresh_token"},
+		},
+		{
+			name:         "Confidential Client",
+			clientSecret: "fake_client_secret",
+			status:       http.StatusOK,
+			response:     map[string]string{"id_token": "fake_id_token", "access_token": "fake_access_token"},
+			want:         &caws.CognitoToken{IdToken: "fake_id_token", AccessToken: "fake_access_token"},
+		},
+		{
+			name:     "Invalid Grant",
+			status:   http.StatusBadRequest,
+			response: map[string]string{"error": "invalid_grant"},
+			wantErr:  ErrInvalidGrant,
+		},
+		{
+			name:     "Server Error",
+			status:   http.StatusInternalServerError,
+			response: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/oauth2/token", r.URL.Path)
+				require.NoError(t, r.ParseForm())
+				assert.Equal(t, "authorization_code", r.PostForm.Get("grant_type"))
+				assert.Equal(t, "fake_client_id", r.PostForm.Get("client_id"))
+				assert.Equal(t, "fake_code", r.PostForm.Get("code"))
+				assert.Equal(t, "https://example.com/callback", r.PostForm.Get("redirect_uri"))
+				assert.Equal(t, "fake_verifier", r.PostForm.Get("code_verifier"))
+
+				user, pass, ok := r.BasicAuth()
+				assert.Equal(t, tt.clientSecret != "", ok)
+				if ok {
+					assert.Equal(t, "fake_client_id", user)
+					assert.Equal(t, tt.clientSecret, pass)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				assert.NoError(t, json.NewEncoder(w).Encode(tt.response))
+			}))
+			t.Cleanup(server.Close)
+
+			got, err := NewClient(server.URL).Exchange(context.Background(), "fake_client_id", tt.clientSecret, "fake_code", "https://example.com/callback", "fake_verifier")
+			if tt.want == nil {
+				require.Error(t, err)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
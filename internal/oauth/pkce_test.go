@@ -0,0 +1,53 @@
+package oauth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChallenge(t *testing.T) {
+	// The example from RFC 7636, appendix B.
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func TestValidChallenge(t *testing.T) {
+	tests := []struct {
+		name      string
+		challenge string
+		want      bool
+	}{
+		{name: "OK", challenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", want: true},
+		{name: "Empty", challenge: ""},
+		{name: "Too Short", challenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw"},
+		{name: "Padded", challenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM="},
+		{name: "Plain Verifier", challenge: "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk~"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidChallenge(tt.challenge))
+		})
+	}
+}
+
+func TestValidVerifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		verifier string
+		want     bool
+	}{
+		{name: "OK", verifier: "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk", want: true},
+		{name: "Unreserved Characters", verifier: strings.Repeat("a", 39) + "-._~", want: true},
+		{name: "Longest", verifier: strings.Repeat("a", 128), want: true},
+		{name: "Empty", verifier: ""},
+		{name: "Too Short", verifier: strings.Repeat("a", 42)},
+		{name: "Too Long", verifier: strings.Repeat("a", 129)},
+		{name: "Reserved Character", verifier: strings.Repeat("a", 42) + "/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidVerifier(tt.verifier))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package oauth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// StateTTL is how long a player has to finish signing in at the hosted UI.
+const StateTTL = 10 * time.Minute
+
+var (
+	ErrInvalidState = errors.New("invalid oauth state")
+	ErrStateExpired = errors.New("oauth state has expired")
+)
+
+// State is what the callback needs to finish a sign-in that the authorize
+// step started. It travels inside the state parameter, so the service keeps
+// nothing between the two requests.
+type State struct {
+	Platform string    `json:"p,omitempty"`
+	Expires  time.Time `json:"e"`
+}
+
+// StateCodec seals State with AES-GCM. The state parameter passes through the
+// browser and the hosted UI, so a forged or altered state must be rejected.
+type StateCodec struct {
+	aead cipher.AEAD
+	now  func() time.Time
+}
+
+// NewStateCodec takes a 16, 24 or 32 byte AES key.
+func NewStateCodec(key []byte) (*StateCodec, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &StateCodec{aead: aead, now: time.Now}, nil
+}
+
+// Seal stamps s with StateTTL and encrypts it.
+func (c *StateCodec) Seal(s State) (string, error) {
+	s.Expires = c.now().Add(StateTTL)
+	plaintext, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(c.aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+func (c *StateCodec) Open(sealed string) (State, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < c.aead.NonceSize() {
+		return State{}, ErrInvalidState
+	}
+
+	nonce, ciphertext := raw[:c.aead.NonceSize()], raw[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return State{}, ErrInvalidState
+	}
+
+	var s State
+	if err = json.Unmarshal(plaintext, &s); err != nil {
+		return State{}, ErrInvalidState
+	}
+	if c.now().After(s.Expires) {
+		return State{}, ErrStateExpired
+	}
+	return s, nil
+}
@@ -0,0 +1,77 @@
+package oauth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateCodec(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	codec, err := NewStateCodec([]byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+	codec.now = func() time.Time { return now }
+
+	sealed, err := codec.Seal(State{Platform: "pc"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		state   string
+		advance time.Duration
+		want    State
+		wantErr error
+	}{
+		{
+			name:  "OK",
+			state: sealed,
+			want:  State{Platform: "pc", Expires: now.Add(StateTTL)},
+		},
+		{
+			name:    "Expired",
+			state:   sealed,
+			advance: StateTTL + time.Second,
+			wantErr: ErrStateExpired,
+		},
+		{
+			name:    "Tampered",
+			state:   sealed[:len(sealed)-2] + "AA",
+			wantErr: ErrInvalidState,
+		},
+		{
+			name:    "Garbage",
+			state:   "not a state",
+			wantErr: ErrInvalidState,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec.now = func() time.Time { return now.Add(tt.advance) }
+
+			got, err := codec.Open(tt.state)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Expires.Equal(got.Expires))
+			got.Expires = tt.want.Expires
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStateCodec_wrongKey(t *testing.T) {
+	codec, err := NewStateCodec([]byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+	other, err := NewStateCodec([]byte("fedcba9876543210fedcba9876543210"))
+	require.NoError(t, err)
+
+	sealed, err := codec.Seal(State{Platform: "pc"})
+	require.NoError(t, err)
+
+	_, err = other.Open(sealed)
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
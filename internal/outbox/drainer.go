@@ -0,0 +1,188 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+)
+
+// Policy controls how the Drainer delivers records. Each claimed record is
+// tried up to Retries times in a row, waiting RetryBackoff and then twice as
+// long after each failure. A record that still fails is released for
+// RetryBackoff times its attempt count, and given up on once it is older than
+// MaxAge, so an outage of the destination shorter than that loses nothing.
+// Lease must outlast one record's retries, or a second drainer may pick the
+// record up while it is still being tried. Drain stops Reserve before its
+// deadline, leaving time to settle the record in flight.
+type Policy struct {
+	BatchSize    int
+	Lease        time.Duration
+	Retries      int
+	RetryBackoff time.Duration
+	MaxAge       time.Duration
+	Reserve      time.Duration
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		BatchSize:    25,
+		Lease:        time.Minute,
+		Retries:      3,
+		RetryBackoff: 200 * time.Millisecond,
+		MaxAge:       24 * time.Hour,
+		Reserve:      time.Second,
+	}
+}
+
+// Result summarises one Drain run.
+type Result struct {
+	Published int `json:"published"`
+	Released  int `json:"released"`
+	Dead      int `json:"dead"`
+}
+
+// ConfirmFunc reports whether the change a held event describes was made,
+// for events whose writer died before settling them.
+type ConfirmFunc func(ctx context.Context, e events.Event) (bool, error)
+
+// errNotConfirmed marks held events whose change never happened.
+var errNotConfirmed = errors.New("the change this event describes was never made")
+
+// Drainer moves events from the outbox to the publisher. Delivery is at least
+// once: a drainer that dies after publishing but before marking the record
+// leaves it to be published again once the lease runs out, so consumers must
+// deduplicate by event ID. The lease keeps concurrent drainers from
+// publishing the same record at the same time. Held records left unsettled
+// are published only if confirm says their change was made; without confirm
+// they are published as if it was.
+type Drainer struct {
+	policy    Policy
+	store     Store
+	publisher events.EventPublisher
+	confirm   ConfirmFunc
+	sleep     func(ctx context.Context, d time.Duration) error
+	now       func() time.Time
+}
+
+func NewDrainer(policy Policy, store Store, publisher events.EventPublisher, confirm ConfirmFunc) *Drainer {
+	return &Drainer{
+		policy:    policy,
+		store:     store,
+		publisher: publisher,
+		confirm:   confirm,
+		sleep:     sleep,
+		now:       time.Now,
+	}
+}
+
+// Drain publishes pending records until none are left to claim or ctx's
+// deadline is less than Policy.Reserve away. Running out of time is how a busy
+// run ends, not a failure: the records published so far are reported and the
+// rest are left for a later run, including claimed ones not yet tried, which
+// wait for their lease to run out.
+func (d *Drainer) Drain(ctx context.Context) (Result, error) {
+	work := ctx
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		work, cancel = context.WithDeadline(ctx, deadline.Add(-d.policy.Reserve))
+		defer cancel()
+	}
+
+	var result Result
+	for work.Err() == nil {
+		records, err := d.store.Claim(ctx, d.policy.BatchSize, d.policy.Lease)
+		if err != nil {
+			return result, err
+		}
+		if len(records) == 0 {
+			return result, nil
+		}
+
+		for _, r := range records {
+			if work.Err() != nil {
+				break
+			}
+			status, err := d.deliver(ctx, work, r)
+			if err != nil {
+				return result, err
+			}
+			switch status {
+			case StatusPublished:
+				result.Published++
+			case StatusDead:
+				result.Dead++
+			default:
+				result.Released++
+			}
+		}
+	}
+	return result, ctx.Err()
+}
+
+// deliver publishes one claimed record and reports where it ended up. The
+// publish runs under work, the store calls under ctx, so a record cut short by
+// work running out is still settled. Only store errors are returned; publish
+// failures are recorded on the record.
+func (d *Drainer) deliver(ctx, work context.Context, r Record) (Status, error) {
+	logger := logging.FromContext(ctx).With("eventId", r.Event.ID, "type", r.Event.Type, "attempts", r.Attempts)
+
+	if age := d.now().Sub(r.CreatedAt); age > d.policy.MaxAge {
+		cause := fmt.Errorf("gave up after %s and %d attempts: %s", age.Round(time.Minute), r.Attempts, r.LastError)
+		logger.Error("Dropping undeliverable event", "error", cause)
+		return StatusDead, d.store.MarkDead(ctx, r.Event.ID, cause)
+	}
+
+	if r.Status == StatusHeld && d.confirm != nil {
+		made, err := d.confirm(work, r.Event)
+		if err != nil {
+			logger.Warn("Failed to confirm held event", "error", err)
+			return StatusPending, d.store.Release(ctx, r.Event.ID, err, time.Duration(r.Attempts)*d.policy.RetryBackoff)
+		}
+		if !made {
+			logger.Info("Dropping event for a change that was never made")
+			return StatusDead, d.store.MarkDead(ctx, r.Event.ID, errNotConfirmed)
+		}
+	}
+
+	err := d.publish(work, r.Event)
+	if err != nil {
+		logger.Warn("Failed to publish event", "error", err)
+		return StatusPending, d.store.Release(ctx, r.Event.ID, err, time.Duration(r.Attempts)*d.policy.RetryBackoff)
+	}
+
+	logger.Info("Published event")
+	return StatusPublished, d.store.MarkPublished(ctx, r.Event.ID)
+}
+
+func (d *Drainer) publish(ctx context.Context, e events.Event) error {
+	backoff := d.policy.RetryBackoff
+	var err error
+	for i := 0; i < max(d.policy.Retries, 1); i++ {
+		if i > 0 {
+			if err := d.sleep(ctx, backoff); err != nil {
+				return err
+			}
+			backoff *= 2
+		}
+		if err = d.publisher.Publish(ctx, e); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,353 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/whatisusername/toon-tank-user-service/internal/events"
+)
+
+// flakyPublisher fails the first failures calls and records what it
+// delivered after that.
+type flakyPublisher struct {
+	events.MemoryPublisher
+	mu       sync.Mutex
+	failures int
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, events ...events.Event) error {
+	p.mu.Lock()
+	if p.failures != 0 {
+		p.failures--
+		p.mu.Unlock()
+		return errors.New("bus unavailable")
+	}
+	p.mu.Unlock()
+	return p.MemoryPublisher.Publish(ctx, events...)
+}
+
+// crashingStore stands in for a drainer that dies right after publishing,
+// before the record is marked.
+type crashingStore struct {
+	Store
+}
+
+func (crashingStore) MarkPublished(context.Context, string) error {
+	return errors.New("process killed")
+}
+
+// newTestDrainer measures record ages on the clock of the memory store behind
+// store.
+func newTestDrainer(store Store, publisher events.EventPublisher) *Drainer {
+	d := NewDrainer(DefaultPolicy(), store, publisher, nil)
+	d.sleep = func(context.Context, time.Duration) error { return nil }
+
+	if c, ok := store.(crashingStore); ok {
+		store = c.Store
+	}
+	if m, ok := store.(*MemoryStore); ok {
+		d.now = func() time.Time { return m.now() }
+	}
+	return d
+}
+
+func TestDrainer_crashBetweenWriteAndPublish(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestMemoryStore()
+
+	// The request handler writes to the outbox and the process dies before
+	// anything is published.
+	e := newTestEvent(t, "test")
+	require.NoError(t, NewPublisher(store).Publish(ctx, e))
+
+	publisher := events.NewMemoryPublisher()
+	result, err := newTestDrainer(store, publisher).Drain(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Published: 1}, result)
+	assert.Equal(t, []events.Event{e}, publisher.Events())
+
+	result, err = newTestDrainer(store, publisher).Drain(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Result{}, result)
+	assert.Len(t, publisher.Events(), 1, "a published event must not be sent again")
+}
+
+func TestDrainer_crashBeforeCommit(t *testing.T) {
+	ctx := context.Background()
+	hold := 15 * time.Minute
+
+	tests := []struct {
+		name       string
+		made       bool
+		confirmErr error
+		wantResult Result
+		wantStatus Status
+	}{
+		{
+			name:       "Change Was Made",
+			made:       true,
+			wantResult: Result{Published: 1},
+			wantStatus: StatusPublished,
+		},
+		{
+			name:       "Change Was Not Made",
+			wantResult: Result{Dead: 1},
+			wantStatus: StatusDead,
+		},
+		{
+			name:       "Check Failed",
+			confirmErr: errors.New("cognito unavailable"),
+			wantResult: Result{Released: 1},
+			wantStatus: StatusHeld,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, advance := newTestMemoryStore()
+
+			// The request handler holds the event, makes the change elsewhere
+			// and dies before committing it.
+			e := newTestEvent(t, "test")
+			require.NoError(t, NewPublisher(store).Hold(ctx, e, hold))
+
+			publisher := events.NewMemoryPublisher()
+			drainer := newTestDrainer(store, publisher)
+			var confirmed []events.Event
+			drainer.confirm = func(_ context.Context, e events.Event) (bool, error) {
+				confirmed = append(confirmed, e)
+				return tt.made, tt.confirmErr
+			}
+
+			result, err := drainer.Drain(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, Result{}, result, "the hold must give the writer time to settle the event")
+
+			advance(hold)
+			result, err = drainer.Drain(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantResult, result)
+			assert.Equal(t, []events.Event{e}, confirmed)
+
+			r, ok := store.Get(e.ID)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantStatus, r.Status)
+			if tt.made {
+				assert.Equal(t, []events.Event{e}, publisher.Events())
+			} else {
+				assert.Empty(t, publisher.Events())
+			}
+		})
+	}
+}
+
+func TestDrainer_committed(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestMemoryStore()
+
+	e := newTestEvent(t, "test")
+	outbox := NewPublisher(store)
+	require.NoError(t, outbox.Hold(ctx, e, time.Hour))
+	require.NoError(t, outbox.Commit(ctx, e.ID))
+
+	publisher := events.NewMemoryPublisher()
+	drainer := newTestDrainer(store, publisher)
+	drainer.confirm = func(context.Context, events.Event) (bool, error) {
+		t.Fatal("committed events must not be checked")
+		return false, nil
+	}
+
+	result, err := drainer.Drain(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Published: 1}, result)
+	assert.Equal(t, []events.Event{e}, publisher.Events())
+}
+
+func TestDrainer_crashAfterClaim(t *testing.T) {
+	ctx := context.Background()
+	store, advance := newTestMemoryStore()
+	e := newTestEvent(t, "test")
+	require.NoError(t, store.Add(ctx, e))
+
+	// A drainer claims the record and dies without publishing it.
+	_, err := store.Claim(ctx, 10, DefaultPolicy().Lease)
+	require.NoError(t, err)
+
+	publisher := events.NewMemoryPublisher()
+	result, err := newTestDrainer(store, publisher).Drain(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Result{}, result, "the lease must keep the record from being published twice at once")
+
+	advance(DefaultPolicy().Lease)
+	result, err = newTestDrainer(store, publisher).Drain(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Published: 1}, result)
+	assert.Equal(t, []events.Event{e}, publisher.Events())
+}
+
+func TestDrainer_crashAfterPublish(t *testing.T) {
+	ctx := context.Background()
+	store, advance := newTestMemoryStore()
+	e := newTestEvent(t, "test")
+	require.NoError(t, store.Add(ctx, e))
+
+	publisher := events.NewMemoryPublisher()
+	_, err := newTestDrainer(crashingStore{store}, publisher).Drain(ctx)
+	require.Error(t, err)
+
+	advance(DefaultPolicy().Lease)
+	result, err := newTestDrainer(store, publisher).Drain(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Published: 1}, result)
+
+	// Delivery is at least once; the repeat carries the same ID so consumers
+	// can drop it.
+	published := publisher.Events()
+	require.Len(t, published, 2)
+	assert.Equal(t, e.ID, published[0].ID)
+	assert.Equal(t, e.ID, published[1].ID)
+}
+
+func TestDrainer_retries(t *testing.T) {
+	ctx := context.Background()
+	policy := DefaultPolicy()
+
+	tests := []struct {
+		name       string
+		failures   int
+		drains     int
+		wantResult Result
+		wantStatus Status
+	}{
+		{
+			name:       "Recovers Within One Run",
+			failures:   policy.Retries - 1,
+			drains:     1,
+			wantResult: Result{Published: 1},
+			wantStatus: StatusPublished,
+		},
+		{
+			name:       "Released For A Later Run",
+			failures:   policy.Retries,
+			drains:     1,
+			wantResult: Result{Released: 1},
+			wantStatus: StatusPending,
+		},
+		{
+			name:       "Published On A Later Run",
+			failures:   policy.Retries,
+			drains:     2,
+			wantResult: Result{Published: 1},
+			wantStatus: StatusPublished,
+		},
+		{
+			// Drains an hour apart, like an outage of the destination.
+			name:       "Kept Through An Outage",
+			failures:   -1,
+			drains:     int(policy.MaxAge / time.Hour),
+			wantResult: Result{Released: 1},
+			wantStatus: StatusPending,
+		},
+		{
+			name:       "Given Up",
+			failures:   -1,
+			drains:     int(policy.MaxAge/time.Hour) + 2,
+			wantResult: Result{Dead: 1},
+			wantStatus: StatusDead,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, advance := newTestMemoryStore()
+			e := newTestEvent(t, "test")
+			require.NoError(t, store.Add(ctx, e))
+
+			publisher := &flakyPublisher{failures: tt.failures}
+			drainer := newTestDrainer(store, publisher)
+
+			var result Result
+			for i := 0; i < tt.drains; i++ {
+				var err error
+				result, err = drainer.Drain(ctx)
+				require.NoError(t, err)
+				advance(time.Hour)
+			}
+			assert.Equal(t, tt.wantResult, result)
+
+			r, ok := store.Get(e.ID)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantStatus, r.Status)
+			if tt.wantStatus != StatusPublished {
+				assert.Contains(t, r.LastError, "bus unavailable")
+			}
+		})
+	}
+}
+
+// blockingPublisher publishes nothing until ctx is done.
+type blockingPublisher struct{}
+
+func (blockingPublisher) Publish(ctx context.Context, _ ...events.Event) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestDrainer_deadline(t *testing.T) {
+	store, _ := newTestMemoryStore()
+	e := newTestEvent(t, "test")
+	require.NoError(t, store.Add(context.Background(), e))
+
+	drainer := newTestDrainer(store, blockingPublisher{})
+	drainer.policy.Reserve = 50 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// Running out of time ends the run normally, with the record in flight
+	// handed back rather than left leased.
+	result, err := drainer.Drain(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Released: 1}, result)
+	assert.NoError(t, ctx.Err(), "the drainer must stop before the deadline")
+
+	r, ok := store.Get(e.ID)
+	require.True(t, ok)
+	assert.Equal(t, StatusPending, r.Status)
+	assert.Contains(t, r.LastError, context.DeadlineExceeded.Error())
+}
+
+func TestDrainer_concurrent(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestMemoryStore()
+
+	var added []events.Event
+	for i := 0; i < 100; i++ {
+		e := newTestEvent(t, "test")
+		added = append(added, e)
+	}
+	require.NoError(t, store.Add(ctx, added...))
+
+	publisher := events.NewMemoryPublisher()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := newTestDrainer(store, publisher).Drain(ctx)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]int)
+	for _, e := range publisher.Events() {
+		seen[e.ID]++
+	}
+	assert.Len(t, seen, len(added))
+	for id, n := range seen {
+		assert.Equal(t, 1, n, "event %s published %d times", id, n)
+	}
+}
@@ -0,0 +1,326 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/whatisusername/toon-tank-user-service/internal/events"
+)
+
+const (
+	// PendingIndex is the sparse index the Claim query runs on. Only pending
+	// records carry its partition key, so finished records drop out of it.
+	PendingIndex = "pending"
+
+	pendingQueue = "pending"
+
+	// finishedTTL is how long published and dead records are kept around for
+	// debugging before TTL removes them.
+	finishedTTL = 7 * 24 * time.Hour
+)
+
+type item struct {
+	ID         string `dynamodbav:"id"`
+	Queue      string `dynamodbav:"queue,omitempty"`
+	Status     Status `dynamodbav:"status"`
+	Event      []byte `dynamodbav:"event"`
+	Attempts   int    `dynamodbav:"attempts"`
+	LastError  string `dynamodbav:"lastError,omitempty"`
+	CreatedAt  int64  `dynamodbav:"createdAt"`
+	LeaseUntil int64  `dynamodbav:"leaseUntil"`
+	ExpiresAt  int64  `dynamodbav:"expiresAt,omitempty"`
+}
+
+// DynamoDBStore keeps the outbox in a table with a string partition key named
+// "id" and a global secondary index named PendingIndex, partitioned on the
+// string "queue" and sorted on the number "createdAt", projecting at least
+// "leaseUntil". TTL should be enabled on "expiresAt".
+type DynamoDBStore struct {
+	client *dynamodb.Client
+	table  string
+	now    func() time.Time
+}
+
+func NewDynamoDBStore(client *dynamodb.Client, table string) *DynamoDBStore {
+	return &DynamoDBStore{
+		client: client,
+		table:  table,
+		now:    time.Now,
+	}
+}
+
+func (s *DynamoDBStore) Add(ctx context.Context, events ...events.Event) error {
+	for _, e := range events {
+		put, err := s.put(e, StatusPending, 0)
+		if err != nil {
+			return err
+		}
+		if err = s.putItem(ctx, put); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *DynamoDBStore) Hold(ctx context.Context, e events.Event, hold time.Duration) error {
+	put, err := s.put(e, StatusHeld, s.now().Add(hold).UnixMilli())
+	if err != nil {
+		return err
+	}
+	return s.putItem(ctx, put)
+}
+
+// Commit queues a held record. Once its hold has run out the record is left
+// to the drainer, which may already have claimed it.
+func (s *DynamoDBStore) Commit(ctx context.Context, id string) error {
+	now := strconv.FormatInt(s.now().UnixMilli(), 10)
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(s.table),
+		Key:                 s.key(id),
+		UpdateExpression:    aws.String("SET #status = :pending, leaseUntil = :now"),
+		ConditionExpression: aws.String("#status = :held AND leaseUntil > :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pending": &types.AttributeValueMemberS{Value: string(StatusPending)},
+			":held":    &types.AttributeValueMemberS{Value: string(StatusHeld)},
+			":now":     &types.AttributeValueMemberN{Value: now},
+		},
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return nil
+	}
+	return err
+}
+
+// putItem writes put outside a transaction, where an event that is already
+// stored is not an error.
+func (s *DynamoDBStore) putItem(ctx context.Context, put *types.Put) error {
+	_, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                put.TableName,
+		Item:                     put.Item,
+		ConditionExpression:      put.ConditionExpression,
+		ExpressionAttributeNames: put.ExpressionAttributeNames,
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return nil
+	}
+	return err
+}
+
+// TransactItem returns the write that adds e to the outbox, for stores that
+// keep their own state in DynamoDB to include in the same TransactWriteItems
+// call as the change the event describes. Unlike Add, the transaction fails
+// if the event is already in the outbox.
+func (s *DynamoDBStore) TransactItem(e events.Event) (types.TransactWriteItem, error) {
+	put, err := s.put(e, StatusPending, 0)
+	if err != nil {
+		return types.TransactWriteItem{}, err
+	}
+	return types.TransactWriteItem{Put: put}, nil
+}
+
+func (s *DynamoDBStore) put(e events.Event, status Status, leaseUntil int64) (*types.Put, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	av, err := attributevalue.MarshalMap(item{
+		ID:         e.ID,
+		Queue:      pendingQueue,
+		Status:     status,
+		Event:      data,
+		CreatedAt:  s.now().UnixMilli(),
+		LeaseUntil: leaseUntil,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Put{
+		TableName:           aws.String(s.table),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	}, nil
+}
+
+func (s *DynamoDBStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]Record, error) {
+	now := s.now()
+	var claimed []Record
+
+	paginator := dynamodb.NewQueryPaginator(s.client, &dynamodb.QueryInput{
+		TableName:              aws.String(s.table),
+		IndexName:              aws.String(PendingIndex),
+		KeyConditionExpression: aws.String("#queue = :queue"),
+		ExpressionAttributeNames: map[string]string{
+			"#queue": "queue",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":queue": &types.AttributeValueMemberS{Value: pendingQueue},
+		},
+	})
+	for paginator.HasMorePages() && len(claimed) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var candidates []item
+		if err = attributevalue.UnmarshalListOfMaps(page.Items, &candidates); err != nil {
+			return nil, err
+		}
+		for _, c := range candidates {
+			if len(claimed) == limit {
+				break
+			}
+			if c.LeaseUntil > now.UnixMilli() {
+				continue
+			}
+
+			r, ok, err := s.claim(ctx, c.ID, now, lease)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				claimed = append(claimed, r)
+			}
+		}
+	}
+	return claimed, nil
+}
+
+// claim leases one record. The index is only eventually consistent, so the
+// condition re-checks that the record is still pending and not leased; false
+// means another drainer got there first.
+func (s *DynamoDBStore) claim(ctx context.Context, id string, now time.Time, lease time.Duration) (Record, bool, error) {
+	output, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(s.table),
+		Key:                 s.key(id),
+		UpdateExpression:    aws.String("SET attempts = attempts + :one, leaseUntil = :leaseUntil"),
+		ConditionExpression: aws.String("#status IN (:pending, :held) AND leaseUntil <= :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one":        &types.AttributeValueMemberN{Value: "1"},
+			":leaseUntil": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(lease).UnixMilli(), 10)},
+			":pending":    &types.AttributeValueMemberS{Value: string(StatusPending)},
+			":held":       &types.AttributeValueMemberS{Value: string(StatusHeld)},
+			":now":        &types.AttributeValueMemberN{Value: strconv.FormatInt(now.UnixMilli(), 10)},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return Record{}, false, nil
+	}
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	var it item
+	if err = attributevalue.UnmarshalMap(output.Attributes, &it); err != nil {
+		return Record{}, false, err
+	}
+	r, err := it.record()
+	if err != nil {
+		return Record{}, false, err
+	}
+	return r, true, nil
+}
+
+func (s *DynamoDBStore) MarkPublished(ctx context.Context, id string) error {
+	return s.finish(ctx, id, StatusPublished, "")
+}
+
+func (s *DynamoDBStore) MarkDead(ctx context.Context, id string, cause error) error {
+	return s.finish(ctx, id, StatusDead, cause.Error())
+}
+
+// finish takes the record out of the pending index. Finishing a record twice,
+// e.g. after two drainers both published it, is not an error.
+func (s *DynamoDBStore) finish(ctx context.Context, id string, status Status, lastError string) error {
+	update := "SET #status = :status, expiresAt = :expiresAt REMOVE #queue"
+	values := map[string]types.AttributeValue{
+		":status":    &types.AttributeValueMemberS{Value: string(status)},
+		":expiresAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(s.now().Add(finishedTTL).Unix(), 10)},
+	}
+	if lastError != "" {
+		update = "SET #status = :status, expiresAt = :expiresAt, lastError = :lastError REMOVE #queue"
+		values[":lastError"] = &types.AttributeValueMemberS{Value: lastError}
+	}
+
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(s.table),
+		Key:                 s.key(id),
+		UpdateExpression:    aws.String(update),
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status",
+			"#queue":  "queue",
+		},
+		ExpressionAttributeValues: values,
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return nil
+	}
+	return err
+}
+
+func (s *DynamoDBStore) Release(ctx context.Context, id string, cause error, retryAfter time.Duration) error {
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(s.table),
+		Key:                 s.key(id),
+		UpdateExpression:    aws.String("SET leaseUntil = :leaseUntil, lastError = :lastError"),
+		ConditionExpression: aws.String("#status IN (:pending, :held)"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":leaseUntil": &types.AttributeValueMemberN{Value: strconv.FormatInt(s.now().Add(retryAfter).UnixMilli(), 10)},
+			":lastError":  &types.AttributeValueMemberS{Value: cause.Error()},
+			":pending":    &types.AttributeValueMemberS{Value: string(StatusPending)},
+			":held":       &types.AttributeValueMemberS{Value: string(StatusHeld)},
+		},
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return nil
+	}
+	return err
+}
+
+func (s *DynamoDBStore) key(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}}
+}
+
+func (it item) record() (Record, error) {
+	var e events.Event
+	if err := json.Unmarshal(it.Event, &e); err != nil {
+		return Record{}, err
+	}
+
+	r := Record{
+		Event:     e,
+		Status:    it.Status,
+		Attempts:  it.Attempts,
+		LastError: it.LastError,
+		CreatedAt: time.UnixMilli(it.CreatedAt),
+	}
+	if it.LeaseUntil > 0 {
+		r.LeaseUntil = time.UnixMilli(it.LeaseUntil)
+	}
+	return r, nil
+}
@@ -0,0 +1,59 @@
+package outbox
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/whatisusername/toon-tank-user-service/internal/testutil"
+)
+
+func TestDynamoDBStore(t *testing.T) {
+	endpoint := testutil.LocalStackEndpoint(t)
+	client := testutil.NewDynamoDBClient(t, endpoint)
+
+	testStore(t, func(t *testing.T) (Store, func(d time.Duration)) {
+		table := strings.NewReplacer("/", "-", " ", "-").Replace(t.Name())
+		createOutboxTable(t, client, table)
+
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := NewDynamoDBStore(client, table)
+		store.now = func() time.Time { return now }
+
+		return store, func(d time.Duration) { now = now.Add(d) }
+	})
+}
+
+func createOutboxTable(t *testing.T, client *dynamodb.Client, name string) {
+	t.Helper()
+
+	_, err := client.CreateTable(context.Background(), &dynamodb.CreateTableInput{
+		TableName: aws.String(name),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("queue"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("createdAt"), AttributeType: types.ScalarAttributeTypeN},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash},
+		},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{{
+			IndexName: aws.String(PendingIndex),
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: aws.String("queue"), KeyType: types.KeyTypeHash},
+				{AttributeName: aws.String("createdAt"), KeyType: types.KeyTypeRange},
+			},
+			Projection: &types.Projection{
+				ProjectionType:   types.ProjectionTypeInclude,
+				NonKeyAttributes: []string{"leaseUntil"},
+			},
+		}},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,148 @@
+package outbox
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/whatisusername/toon-tank-user-service/internal/events"
+)
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]*Record
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string]*Record),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Add(_ context.Context, events ...events.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for _, e := range events {
+		if _, ok := s.records[e.ID]; ok {
+			continue
+		}
+		s.records[e.ID] = &Record{
+			Event:     e,
+			Status:    StatusPending,
+			CreatedAt: now,
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Hold(_ context.Context, e events.Event, hold time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[e.ID]; ok {
+		return nil
+	}
+	now := s.now()
+	s.records[e.ID] = &Record{
+		Event:      e,
+		Status:     StatusHeld,
+		CreatedAt:  now,
+		LeaseUntil: now.Add(hold),
+	}
+	return nil
+}
+
+// Commit queues a held record. Once its hold has run out the record is left
+// to the drainer, which may already have claimed it.
+func (s *MemoryStore) Commit(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.records[id]; ok && r.Status == StatusHeld && s.now().Before(r.LeaseUntil) {
+		r.Status = StatusPending
+		r.LeaseUntil = time.Time{}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Claim(_ context.Context, limit int, lease time.Duration) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var claimable []*Record
+	for _, r := range s.records {
+		if awaitingDelivery(r.Status) && !now.Before(r.LeaseUntil) {
+			claimable = append(claimable, r)
+		}
+	}
+	sort.Slice(claimable, func(i, j int) bool {
+		return claimable[i].CreatedAt.Before(claimable[j].CreatedAt)
+	})
+	if len(claimable) > limit {
+		claimable = claimable[:limit]
+	}
+
+	claimed := make([]Record, len(claimable))
+	for i, r := range claimable {
+		r.Attempts++
+		r.LeaseUntil = now.Add(lease)
+		claimed[i] = *r
+	}
+	return claimed, nil
+}
+
+func (s *MemoryStore) MarkPublished(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.records[id]; ok {
+		r.Status = StatusPublished
+		r.LeaseUntil = time.Time{}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, id string, cause error, retryAfter time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.records[id]; ok && awaitingDelivery(r.Status) {
+		r.LastError = cause.Error()
+		r.LeaseUntil = s.now().Add(retryAfter)
+	}
+	return nil
+}
+
+func (s *MemoryStore) MarkDead(_ context.Context, id string, cause error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.records[id]; ok && awaitingDelivery(r.Status) {
+		r.Status = StatusDead
+		r.LastError = cause.Error()
+		r.LeaseUntil = time.Time{}
+	}
+	return nil
+}
+
+func awaitingDelivery(status Status) bool {
+	return status == StatusPending || status == StatusHeld
+}
+
+// Get returns the record for id, for tests and debugging.
+func (s *MemoryStore) Get(id string) (Record, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[id]
+	if !ok {
+		return Record{}, false
+	}
+	return *r, true
+}
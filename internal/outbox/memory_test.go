@@ -0,0 +1,20 @@
+package outbox
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, func(t *testing.T) (Store, func(d time.Duration)) {
+		return newTestMemoryStore()
+	})
+}
+
+func newTestMemoryStore() (*MemoryStore, func(d time.Duration)) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	return store, func(d time.Duration) { now = now.Add(d) }
+}
@@ -0,0 +1,85 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/whatisusername/toon-tank-user-service/internal/events"
+)
+
+type Status string
+
+const (
+	StatusHeld      Status = "HELD"
+	StatusPending   Status = "PENDING"
+	StatusPublished Status = "PUBLISHED"
+	StatusDead      Status = "DEAD"
+)
+
+// Record is an event waiting in the outbox together with its delivery state.
+// Attempts counts how many times the record has been claimed, including
+// claims by drainers that never reported back.
+type Record struct {
+	Event      events.Event
+	Status     Status
+	Attempts   int
+	LastError  string
+	CreatedAt  time.Time
+	LeaseUntil time.Time
+}
+
+// Store keeps events until they have been published.
+//
+// Add stores events as pending; adding an event ID that is already stored is
+// a no-op, so writers can retry safely. Claim leases up to limit pending
+// records, oldest first, that are not leased by someone else; a lease that
+// runs out makes the record claimable again, which is how records held by a
+// crashed drainer are recovered. MarkPublished and MarkDead take a record out
+// of the outbox for good, and Release hands it back to be claimed again once
+// retryAfter has passed.
+//
+// Hold is for changes that can't be written in the same transaction as the
+// outbox, such as those made in Cognito: the event is stored before the change
+// is made and isn't claimed until hold has passed. Commit queues it once the
+// change has been made and MarkDead drops it if the change failed. A held
+// record that is never settled, because the writer died in between, is
+// claimed with StatusHeld once its hold runs out, for the drainer to check
+// whether the change happened.
+type Store interface {
+	Add(ctx context.Context, events ...events.Event) error
+	Hold(ctx context.Context, e events.Event, hold time.Duration) error
+	Commit(ctx context.Context, id string) error
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]Record, error)
+	MarkPublished(ctx context.Context, id string) error
+	Release(ctx context.Context, id string, cause error, retryAfter time.Duration) error
+	MarkDead(ctx context.Context, id string, cause error) error
+}
+
+// Publisher writes events to the outbox instead of sending them, so an event
+// survives the process dying before it is delivered. The Drainer sends them
+// on.
+type Publisher struct {
+	store Store
+}
+
+func NewPublisher(store Store) *Publisher {
+	return &Publisher{store: store}
+}
+
+func (p *Publisher) Publish(ctx context.Context, events ...events.Event) error {
+	return p.store.Add(ctx, events...)
+}
+
+// Hold writes e before the change it describes is made. Call Commit once the
+// change has been made and Abandon if it failed.
+func (p *Publisher) Hold(ctx context.Context, e events.Event, hold time.Duration) error {
+	return p.store.Hold(ctx, e, hold)
+}
+
+func (p *Publisher) Commit(ctx context.Context, id string) error {
+	return p.store.Commit(ctx, id)
+}
+
+func (p *Publisher) Abandon(ctx context.Context, id string, cause error) error {
+	return p.store.MarkDead(ctx, id, cause)
+}
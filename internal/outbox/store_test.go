@@ -0,0 +1,181 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/whatisusername/toon-tank-user-service/internal/events"
+)
+
+// newStoreFunc returns an empty store and a function that moves its clock
+// forward.
+type newStoreFunc func(t *testing.T) (Store, func(d time.Duration))
+
+// testStore runs the behaviour every Store must share.
+func testStore(t *testing.T, newStore newStoreFunc) {
+	ctx := context.Background()
+	lease := time.Minute
+
+	t.Run("Add Is Idempotent", func(t *testing.T) {
+		store, _ := newStore(t)
+		e := newTestEvent(t, "test")
+
+		require.NoError(t, store.Add(ctx, e))
+		require.NoError(t, store.Add(ctx, e))
+
+		claimed, err := store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, e.ID, claimed[0].Event.ID)
+		assert.JSONEq(t, string(e.Data), string(claimed[0].Event.Data))
+		assert.Equal(t, StatusPending, claimed[0].Status)
+		assert.Equal(t, 1, claimed[0].Attempts)
+	})
+
+	t.Run("Oldest First", func(t *testing.T) {
+		store, advance := newStore(t)
+		first, second := newTestEvent(t, "first"), newTestEvent(t, "second")
+
+		require.NoError(t, store.Add(ctx, first))
+		advance(time.Second)
+		require.NoError(t, store.Add(ctx, second))
+
+		claimed, err := store.Claim(ctx, 1, lease)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, first.ID, claimed[0].Event.ID)
+
+		claimed, err = store.Claim(ctx, 1, lease)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, second.ID, claimed[0].Event.ID)
+	})
+
+	t.Run("Expired Lease Is Claimed Again", func(t *testing.T) {
+		store, advance := newStore(t)
+		require.NoError(t, store.Add(ctx, newTestEvent(t, "test")))
+
+		_, err := store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+
+		claimed, err := store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+		assert.Empty(t, claimed, "record is still leased")
+
+		advance(lease)
+		claimed, err = store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, 2, claimed[0].Attempts)
+	})
+
+	t.Run("Published Is Not Claimed Again", func(t *testing.T) {
+		store, advance := newStore(t)
+		e := newTestEvent(t, "test")
+		require.NoError(t, store.Add(ctx, e))
+
+		_, err := store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+		require.NoError(t, store.MarkPublished(ctx, e.ID))
+		require.NoError(t, store.MarkPublished(ctx, e.ID), "marking twice must not fail")
+
+		advance(lease)
+		claimed, err := store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+
+		require.NoError(t, store.Add(ctx, e))
+		claimed, err = store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+		assert.Empty(t, claimed, "re-adding a published event must not queue it again")
+	})
+
+	t.Run("Release Delays Retry", func(t *testing.T) {
+		store, advance := newStore(t)
+		e := newTestEvent(t, "test")
+		require.NoError(t, store.Add(ctx, e))
+
+		_, err := store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+		require.NoError(t, store.Release(ctx, e.ID, errors.New("bus unavailable"), 5*time.Second))
+
+		claimed, err := store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+
+		advance(5 * time.Second)
+		claimed, err = store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, "bus unavailable", claimed[0].LastError)
+	})
+
+	t.Run("Dead Is Not Claimed Again", func(t *testing.T) {
+		store, advance := newStore(t)
+		e := newTestEvent(t, "test")
+		require.NoError(t, store.Add(ctx, e))
+
+		_, err := store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+		require.NoError(t, store.MarkDead(ctx, e.ID, errors.New("gave up")))
+
+		advance(lease)
+		claimed, err := store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+	})
+
+	t.Run("Held Waits For Commit", func(t *testing.T) {
+		store, _ := newStore(t)
+		e := newTestEvent(t, "test")
+		require.NoError(t, store.Hold(ctx, e, time.Hour))
+
+		claimed, err := store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+		assert.Empty(t, claimed, "record is still held")
+
+		require.NoError(t, store.Commit(ctx, e.ID))
+		claimed, err = store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, StatusPending, claimed[0].Status)
+	})
+
+	t.Run("Abandoned Hold Is Not Claimed", func(t *testing.T) {
+		store, advance := newStore(t)
+		e := newTestEvent(t, "test")
+		require.NoError(t, store.Hold(ctx, e, time.Hour))
+		require.NoError(t, store.MarkDead(ctx, e.ID, errors.New("sign-up failed")))
+
+		advance(time.Hour)
+		claimed, err := store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+	})
+
+	t.Run("Unsettled Hold Is Claimed Once It Runs Out", func(t *testing.T) {
+		store, advance := newStore(t)
+		e := newTestEvent(t, "test")
+		require.NoError(t, store.Hold(ctx, e, time.Hour))
+
+		advance(time.Hour)
+		require.NoError(t, store.Commit(ctx, e.ID), "a late commit must not fail")
+
+		claimed, err := store.Claim(ctx, 10, lease)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, StatusHeld, claimed[0].Status, "a late commit leaves the check to the drainer")
+	})
+}
+
+func newTestEvent(t *testing.T, username string) events.Event {
+	t.Helper()
+
+	e, err := events.New(events.TypeUserSignedUp, 1, "fake_request_id", events.UserSignedUp{Username: username})
+	require.NoError(t, err)
+	return e
+}
@@ -0,0 +1,80 @@
+package password
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const prefixLength = 5
+
+// BreachList reports how many times a password has appeared in known
+// breaches.
+type BreachList interface {
+	Count(ctx context.Context, password string) (int, error)
+}
+
+// FileBreachList reads a local copy of the Have I Been Pwned range data. The
+// directory holds one file per 5-character SHA-1 prefix, named <PREFIX> or
+// <PREFIX>.txt, with "SUFFIX:COUNT" lines exactly as the range API returns
+// them. Only the prefix file for the password is opened, so the full corpus
+// never needs to fit in memory.
+type FileBreachList struct {
+	dir string
+}
+
+func NewFileBreachList(dir string) *FileBreachList {
+	return &FileBreachList{dir: dir}
+}
+
+func (l *FileBreachList) Count(_ context.Context, password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+	f, err := l.open(prefix)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return findSuffix(f, suffix)
+}
+
+func (l *FileBreachList) open(prefix string) (*os.File, error) {
+	f, err := os.Open(filepath.Join(l.dir, prefix+".txt"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return os.Open(filepath.Join(l.dir, prefix))
+	}
+	return f, err
+}
+
+func findSuffix(r io.Reader, suffix string) (int, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		lineSuffix, count, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(lineSuffix, suffix) {
+			continue
+		}
+
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			return 0, fmt.Errorf("malformed breach entry %q: %w", line, err)
+		}
+		return n, nil
+	}
+	return 0, scanner.Err()
+}
@@ -0,0 +1,69 @@
+package password
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileBreachList_Count(t *testing.T) {
+	dir := t.TempDir()
+	// SHA-1("Password123") = B2E98AD6F6EB8508DD6A14CFA704BAD7F05F6FB1
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "B2E98.txt"), []byte(
+		"0005AD76BD555C1D6D771DE417A4B87E4B4:10\r\n"+
+			"AD6F6EB8508DD6A14CFA704BAD7F05F6FB1:128123\r\n"+
+			"FFFF6B2E0DEB2D1B2D3CBAD5F7C2A6B8C3A:1\r\n",
+	), 0o600))
+	// SHA-1("Summer2024") = 6EA164759ADCCDF0B63C3E6A8A52792691F4C37B lives in a
+	// file without the .txt extension.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "6EA16"), []byte(
+		"4759adccdf0b63c3e6a8a52792691f4c37b:42\n",
+	), 0o600))
+	// SHA-1("Letmein1") = 232BABB0952422462C6AE902BA4E7A7FD1B35CC7
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "232BA.txt"), []byte(
+		"BB0952422462C6AE902BA4E7A7FD1B35CC7:many\n",
+	), 0o600))
+
+	tests := []struct {
+		name     string
+		password string
+		want     int
+		wantErr  bool
+	}{
+		{
+			name:     "Breached",
+			password: "Password123",
+			want:     128123,
+		},
+		{
+			name:     "File Without Extension",
+			password: "Summer2024",
+			want:     42,
+		},
+		{
+			name:     "Malformed Entry",
+			password: "Letmein1",
+			wantErr:  true,
+		},
+		{
+			name:     "Prefix File Missing",
+			password: "correct horse battery staple",
+			want:     0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFileBreachList(dir).Count(context.Background(), tt.password)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
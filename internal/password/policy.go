@@ -0,0 +1,90 @@
+package password
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/whatisusername/toon-tank-user-service/internal/validation"
+)
+
+const (
+	CodeTooShort      = "PasswordTooShort"
+	CodeTooLong       = "PasswordTooLong"
+	CodeMissingUpper  = "PasswordMissingUppercase"
+	CodeMissingLower  = "PasswordMissingLowercase"
+	CodeMissingDigit  = "PasswordMissingDigit"
+	CodeMissingSymbol = "PasswordMissingSymbol"
+	CodeBreached      = "PasswordBreached"
+)
+
+type Policy struct {
+	MinLength     int  `json:"minLength"`
+	MaxLength     int  `json:"maxLength"`
+	RequireUpper  bool `json:"requireUppercase"`
+	RequireLower  bool `json:"requireLowercase"`
+	RequireDigit  bool `json:"requireDigit"`
+	RequireSymbol bool `json:"requireSymbol"`
+}
+
+// DefaultPolicy mirrors the user pool's password settings, so requests are
+// rejected here with structured errors before Cognito sees them.
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLength:    8,
+		MaxLength:    256,
+		RequireUpper: true,
+		RequireLower: true,
+		RequireDigit: true,
+	}
+}
+
+// Check reports every rule password breaks, attributing them to field.
+func (p Policy) Check(field, password string) []validation.Violation {
+	var violations []validation.Violation
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		violations = append(violations, validation.Violation{
+			Field:   field,
+			Code:    CodeTooShort,
+			Message: fmt.Sprintf("must be at least %d characters long", p.MinLength),
+		})
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		violations = append(violations, validation.Violation{
+			Field:   field,
+			Code:    CodeTooLong,
+			Message: fmt.Sprintf("must be at most %d characters long", p.MaxLength),
+		})
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || r == ' ':
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireUpper && !hasUpper {
+		violations = append(violations, validation.Violation{Field: field, Code: CodeMissingUpper, Message: "must contain an uppercase letter"})
+	}
+	if p.RequireLower && !hasLower {
+		violations = append(violations, validation.Violation{Field: field, Code: CodeMissingLower, Message: "must contain a lowercase letter"})
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, validation.Violation{Field: field, Code: CodeMissingDigit, Message: "must contain a digit"})
+	}
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, validation.Violation{Field: field, Code: CodeMissingSymbol, Message: "must contain a symbol"})
+	}
+
+	return violations
+}
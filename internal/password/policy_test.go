@@ -0,0 +1,69 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	policy := Policy{
+		MinLength:     8,
+		MaxLength:     16,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	tests := []struct {
+		name      string
+		password  string
+		wantCodes []string
+	}{
+		{
+			name:      "Valid",
+			password:  "Test1234!",
+			wantCodes: nil,
+		},
+		{
+			name:      "Too Short",
+			password:  "Te1!",
+			wantCodes: []string{CodeTooShort},
+		},
+		{
+			name:      "Too Long",
+			password:  "Test1234!" + strings.Repeat("a", 8),
+			wantCodes: []string{CodeTooLong},
+		},
+		{
+			name:      "Missing Classes",
+			password:  "testtesttest",
+			wantCodes: []string{CodeMissingUpper, CodeMissingDigit, CodeMissingSymbol},
+		},
+		{
+			name:      "Space Counts As Symbol",
+			password:  "Test 1234",
+			wantCodes: nil,
+		},
+		{
+			name:      "Counts Runes Not Bytes",
+			password:  "Ťęšť1!",
+			wantCodes: []string{CodeTooShort},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := policy.Check("password", tt.password)
+
+			var codes []string
+			for _, v := range violations {
+				assert.Equal(t, "password", v.Field)
+				assert.NotEmpty(t, v.Message)
+				codes = append(codes, v.Code)
+			}
+			assert.Equal(t, tt.wantCodes, codes)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package password
+
+import (
+	"context"
+
+	"github.com/whatisusername/toon-tank-user-service/internal/validation"
+)
+
+type Validator struct {
+	policy   Policy
+	breaches BreachList
+}
+
+// NewValidator checks passwords against policy and, when breaches is not
+// nil, against known breached passwords.
+func NewValidator(policy Policy, breaches BreachList) *Validator {
+	return &Validator{
+		policy:   policy,
+		breaches: breaches,
+	}
+}
+
+// Validate returns the violations found for password. The breach list is
+// only consulted for passwords that satisfy the policy.
+func (v *Validator) Validate(ctx context.Context, field, password string) ([]validation.Violation, error) {
+	violations := v.policy.Check(field, password)
+	if len(violations) > 0 || v.breaches == nil {
+		return violations, nil
+	}
+
+	count, err := v.breaches.Count(ctx, password)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		violations = append(violations, validation.Violation{
+			Field:   field,
+			Code:    CodeBreached,
+			Message: "has appeared in a data breach, please choose a different password",
+		})
+	}
+	return violations, nil
+}
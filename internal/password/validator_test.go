@@ -0,0 +1,79 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBreachList map[string]int
+
+func (l fakeBreachList) Count(_ context.Context, password string) (int, error) {
+	if strings.Contains(strings.ToLower(password), "unreachable") {
+		return 0, errors.New("breach list unavailable")
+	}
+	return l[password], nil
+}
+
+func TestValidator_Validate(t *testing.T) {
+	breaches := fakeBreachList{"Password123": 128123}
+
+	tests := []struct {
+		name      string
+		breaches  BreachList
+		password  string
+		wantCodes []string
+		wantErr   bool
+	}{
+		{
+			name:      "Valid",
+			breaches:  breaches,
+			password:  "test123456A",
+			wantCodes: nil,
+		},
+		{
+			name:      "Breached",
+			breaches:  breaches,
+			password:  "Password123",
+			wantCodes: []string{CodeBreached},
+		},
+		{
+			name:      "Policy Checked First",
+			breaches:  breaches,
+			password:  "unreachable",
+			wantCodes: []string{CodeMissingUpper, CodeMissingDigit},
+		},
+		{
+			name:     "Breach List Error",
+			breaches: breaches,
+			password: "Unreachable1",
+			wantErr:  true,
+		},
+		{
+			name:      "No Breach List",
+			breaches:  nil,
+			password:  "Password123",
+			wantCodes: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := NewValidator(DefaultPolicy(), tt.breaches).Validate(context.Background(), "password", tt.password)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var codes []string
+			for _, v := range violations {
+				codes = append(codes, v.Code)
+			}
+			assert.Equal(t, tt.wantCodes, codes)
+		})
+	}
+}
@@ -0,0 +1,374 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	maxBatchGetAttempts = 5
+	batchGetBackoff     = 50 * time.Millisecond
+)
+
+type item struct {
+	PlayerID             string           `dynamodbav:"playerId"`
+	DisplayName          string           `dynamodbav:"displayName"`
+	DisplayNameChangedAt time.Time        `dynamodbav:"displayNameChangedAt"`
+	PreviousDisplayNames []nameChangeItem `dynamodbav:"previousDisplayNames"`
+	Avatar               string           `dynamodbav:"avatar"`
+	TankSkin             string           `dynamodbav:"tankSkin"`
+	Region               string           `dynamodbav:"region"`
+	Level                int              `dynamodbav:"level"`
+	CreatedAt            time.Time        `dynamodbav:"createdAt"`
+	UpdatedAt            time.Time        `dynamodbav:"updatedAt"`
+	Version              int64            `dynamodbav:"version"`
+}
+
+type nameChangeItem struct {
+	Name       string    `dynamodbav:"name"`
+	ReplacedAt time.Time `dynamodbav:"replacedAt"`
+}
+
+type nameItem struct {
+	NameKey    string    `dynamodbav:"nameKey"`
+	Name       string    `dynamodbav:"name"`
+	PlayerID   string    `dynamodbav:"playerId"`
+	ReservedAt time.Time `dynamodbav:"reservedAt"`
+}
+
+// DynamoDBRepository keeps profiles in a table with a string partition key
+// named "playerId". Display names are reserved in a second table with a
+// string partition key named "nameKey", written in the same transaction as
+// the profile so that a name can never be held by two players.
+type DynamoDBRepository struct {
+	client     *dynamodb.Client
+	table      string
+	namesTable string
+	now        func() time.Time
+}
+
+func NewDynamoDBRepository(client *dynamodb.Client, table, namesTable string) *DynamoDBRepository {
+	return &DynamoDBRepository{
+		client:     client,
+		table:      table,
+		namesTable: namesTable,
+		now:        time.Now,
+	}
+}
+
+func (r *DynamoDBRepository) Get(ctx context.Context, playerID string) (*Profile, error) {
+	output, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.table),
+		Key:            r.key(playerID),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if output.Item == nil {
+		return nil, ErrNotFound
+	}
+
+	var i item
+	if err = attributevalue.UnmarshalMap(output.Item, &i); err != nil {
+		return nil, err
+	}
+	return i.profile(), nil
+}
+
+// BatchGet retries the keys DynamoDB leaves unprocessed under throttling a
+// few times before giving up.
+func (r *DynamoDBRepository) BatchGet(ctx context.Context, playerIDs []string) ([]Profile, error) {
+	if len(playerIDs) > MaxBatchSize {
+		return nil, ErrBatchTooLarge
+	}
+	if len(playerIDs) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]map[string]types.AttributeValue, 0, len(playerIDs))
+	seen := make(map[string]bool, len(playerIDs))
+	for _, id := range playerIDs {
+		if !seen[id] {
+			seen[id] = true
+			keys = append(keys, r.key(id))
+		}
+	}
+
+	var profiles []Profile
+	request := map[string]types.KeysAndAttributes{
+		r.table: {Keys: keys, ConsistentRead: aws.Bool(true)},
+	}
+	for attempt := 0; len(request) > 0; attempt++ {
+		if attempt == maxBatchGetAttempts {
+			return nil, errors.New("profiles left unprocessed after retries")
+		}
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(attempt) * batchGetBackoff):
+			}
+		}
+
+		output, err := r.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: request})
+		if err != nil {
+			return nil, err
+		}
+
+		var items []item
+		if err = attributevalue.UnmarshalListOfMaps(output.Responses[r.table], &items); err != nil {
+			return nil, err
+		}
+		for _, i := range items {
+			profiles = append(profiles, *i.profile())
+		}
+		request = output.UnprocessedKeys
+	}
+	return profiles, nil
+}
+
+func (r *DynamoDBRepository) Put(ctx context.Context, p Profile) (*Profile, error) {
+	now, err := attributevalue.Marshal(r.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	condition := "#version = :expected"
+	values := map[string]types.AttributeValue{
+		":avatar":   &types.AttributeValueMemberS{Value: p.Avatar},
+		":tankSkin": &types.AttributeValueMemberS{Value: p.TankSkin},
+		":region":   &types.AttributeValueMemberS{Value: p.Region},
+		":now":      now,
+		":next":     &types.AttributeValueMemberN{Value: strconv.FormatInt(p.Version+1, 10)},
+	}
+	if p.Version == 0 {
+		condition = "attribute_not_exists(playerId)"
+	} else {
+		values[":expected"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(p.Version, 10)}
+	}
+
+	output, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.table),
+		Key:       r.key(p.PlayerID),
+		UpdateExpression: aws.String("SET avatar = :avatar, tankSkin = :tankSkin, #region = :region, " +
+			"createdAt = if_not_exists(createdAt, :now), updatedAt = :now, #version = :next"),
+		ConditionExpression: aws.String(condition),
+		ExpressionAttributeNames: map[string]string{
+			"#region":  "region",
+			"#version": "version",
+		},
+		ExpressionAttributeValues: values,
+		ReturnValues:              types.ReturnValueAllNew,
+	})
+
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return nil, ErrVersionConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var i item
+	if err = attributevalue.UnmarshalMap(output.Attributes, &i); err != nil {
+		return nil, err
+	}
+	return i.profile(), nil
+}
+
+// Rename reads the profile first to check the cooldown and find the name to
+// release, and fails with ErrVersionConflict if it changes before the
+// transaction commits.
+func (r *DynamoDBRepository) Rename(ctx context.Context, playerID, name string, cooldown time.Duration) (*Profile, error) {
+	current, err := r.Get(ctx, playerID)
+	if errors.Is(err, ErrNotFound) {
+		current, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && current.DisplayName == name {
+		return current, nil
+	}
+
+	now := r.now().UTC()
+	if err = checkCooldown(current, now, cooldown); err != nil {
+		return nil, err
+	}
+
+	reservation, err := attributevalue.MarshalMap(nameItem{NameKey: NameKey(name), Name: name, PlayerID: playerID, ReservedAt: now})
+	if err != nil {
+		return nil, err
+	}
+	owner := map[string]types.AttributeValue{":playerId": &types.AttributeValueMemberS{Value: playerID}}
+	items := []types.TransactWriteItem{{
+		Put: &types.Put{
+			TableName:                 aws.String(r.namesTable),
+			Item:                      reservation,
+			ConditionExpression:       aws.String("attribute_not_exists(nameKey) OR playerId = :playerId"),
+			ExpressionAttributeValues: owner,
+		},
+	}}
+
+	update, err := r.renameUpdate(playerID, current, name, now)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, types.TransactWriteItem{Update: update})
+
+	if current != nil && current.DisplayName != "" && NameKey(current.DisplayName) != NameKey(name) {
+		items = append(items, types.TransactWriteItem{
+			Delete: &types.Delete{
+				TableName:                 aws.String(r.namesTable),
+				Key:                       map[string]types.AttributeValue{"nameKey": &types.AttributeValueMemberS{Value: NameKey(current.DisplayName)}},
+				ConditionExpression:       aws.String("attribute_not_exists(nameKey) OR playerId = :playerId"),
+				ExpressionAttributeValues: owner,
+			},
+		})
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		// Reasons are listed in the order of the items: the reservation comes
+		// first, anything else means the profile moved on underneath us.
+		if len(canceled.CancellationReasons) > 0 && aws.ToString(canceled.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+			return nil, ErrNameTaken
+		}
+		return nil, ErrVersionConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Get(ctx, playerID)
+}
+
+func (r *DynamoDBRepository) renameUpdate(playerID string, current *Profile, name string, now time.Time) (*types.Update, error) {
+	nowValue, err := attributevalue.Marshal(now)
+	if err != nil {
+		return nil, err
+	}
+
+	expression := "SET displayName = :name, displayNameChangedAt = :now, updatedAt = :now, " +
+		"createdAt = if_not_exists(createdAt, :now), #version = if_not_exists(#version, :zero) + :one"
+	condition := "attribute_not_exists(playerId)"
+	values := map[string]types.AttributeValue{
+		":name": &types.AttributeValueMemberS{Value: name},
+		":now":  nowValue,
+		":zero": &types.AttributeValueMemberN{Value: "0"},
+		":one":  &types.AttributeValueMemberN{Value: "1"},
+	}
+	if current != nil {
+		condition = "#version = :expected"
+		values[":expected"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(current.Version, 10)}
+	}
+	if current != nil && current.DisplayName != "" {
+		previous, err := attributevalue.Marshal([]nameChangeItem{{Name: current.DisplayName, ReplacedAt: now}})
+		if err != nil {
+			return nil, err
+		}
+		expression += ", previousDisplayNames = list_append(if_not_exists(previousDisplayNames, :empty), :previous)"
+		values[":empty"] = &types.AttributeValueMemberL{Value: []types.AttributeValue{}}
+		values[":previous"] = previous
+	}
+
+	return &types.Update{
+		TableName:                 aws.String(r.table),
+		Key:                       r.key(playerID),
+		UpdateExpression:          aws.String(expression),
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeNames:  map[string]string{"#version": "version"},
+		ExpressionAttributeValues: values,
+	}, nil
+}
+
+func (r *DynamoDBRepository) NameHistory(ctx context.Context, playerID string) ([]NameChange, error) {
+	output, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:            aws.String(r.table),
+		Key:                  r.key(playerID),
+		ProjectionExpression: aws.String("previousDisplayNames"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var i item
+	if err = attributevalue.UnmarshalMap(output.Item, &i); err != nil {
+		return nil, err
+	}
+
+	history := make([]NameChange, len(i.PreviousDisplayNames))
+	for n, c := range i.PreviousDisplayNames {
+		history[n] = NameChange{Name: c.Name, ReplacedAt: c.ReplacedAt}
+	}
+	return history, nil
+}
+
+// Delete releases the display name in the same transaction as it removes the
+// profile, and fails with ErrVersionConflict if the profile changes in between.
+func (r *DynamoDBRepository) Delete(ctx context.Context, playerID string) error {
+	current, err := r.Get(ctx, playerID)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	items := []types.TransactWriteItem{{
+		Delete: &types.Delete{
+			TableName:                 aws.String(r.table),
+			Key:                       r.key(playerID),
+			ConditionExpression:       aws.String("#version = :expected"),
+			ExpressionAttributeNames:  map[string]string{"#version": "version"},
+			ExpressionAttributeValues: map[string]types.AttributeValue{":expected": &types.AttributeValueMemberN{Value: strconv.FormatInt(current.Version, 10)}},
+		},
+	}}
+	if current.DisplayName != "" {
+		items = append(items, types.TransactWriteItem{
+			Delete: &types.Delete{
+				TableName:                 aws.String(r.namesTable),
+				Key:                       map[string]types.AttributeValue{"nameKey": &types.AttributeValueMemberS{Value: NameKey(current.DisplayName)}},
+				ConditionExpression:       aws.String("attribute_not_exists(nameKey) OR playerId = :playerId"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{":playerId": &types.AttributeValueMemberS{Value: playerID}},
+			},
+		})
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		return ErrVersionConflict
+	}
+	return err
+}
+
+func (r *DynamoDBRepository) key(playerID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{"playerId": &types.AttributeValueMemberS{Value: playerID}}
+}
+
+func (i item) profile() *Profile {
+	return &Profile{
+		PlayerID:             i.PlayerID,
+		DisplayName:          i.DisplayName,
+		DisplayNameChangedAt: i.DisplayNameChangedAt,
+		Avatar:               i.Avatar,
+		TankSkin:             i.TankSkin,
+		Region:               i.Region,
+		Level:                i.Level,
+		CreatedAt:            i.CreatedAt,
+		UpdatedAt:            i.UpdatedAt,
+		Version:              i.Version,
+	}
+}
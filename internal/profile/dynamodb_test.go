@@ -0,0 +1,21 @@
+package profile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/whatisusername/toon-tank-user-service/internal/testutil"
+)
+
+func TestDynamoDBRepository(t *testing.T) {
+	endpoint := testutil.LocalStackEndpoint(t)
+	client := testutil.NewDynamoDBClient(t, endpoint)
+	testutil.CreateTable(t, client, "profiles", "playerId", "")
+	testutil.CreateTable(t, client, "display-names", "nameKey", "")
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := NewDynamoDBRepository(client, "profiles", "display-names")
+	repo.now = func() time.Time { return now }
+
+	testRepository(t, repo, func(d time.Duration) { now = now.Add(d) })
+}
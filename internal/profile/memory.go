@@ -0,0 +1,130 @@
+package profile
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type MemoryRepository struct {
+	mu       sync.Mutex
+	profiles map[string]Profile
+	names    map[string]string
+	history  map[string][]NameChange
+	now      func() time.Time
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		profiles: make(map[string]Profile),
+		names:    make(map[string]string),
+		history:  make(map[string][]NameChange),
+		now:      time.Now,
+	}
+}
+
+func (r *MemoryRepository) Get(_ context.Context, playerID string) (*Profile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.profiles[playerID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &p, nil
+}
+
+func (r *MemoryRepository) BatchGet(_ context.Context, playerIDs []string) ([]Profile, error) {
+	if len(playerIDs) > MaxBatchSize {
+		return nil, ErrBatchTooLarge
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var profiles []Profile
+	seen := make(map[string]bool, len(playerIDs))
+	for _, id := range playerIDs {
+		if p, ok := r.profiles[id]; ok && !seen[id] {
+			seen[id] = true
+			profiles = append(profiles, p)
+		}
+	}
+	return profiles, nil
+}
+
+func (r *MemoryRepository) Put(_ context.Context, p Profile) (*Profile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	existing, ok := r.profiles[p.PlayerID]
+	switch {
+	case !ok && p.Version != 0, ok && existing.Version != p.Version:
+		return nil, ErrVersionConflict
+	case ok:
+		p.CreatedAt = existing.CreatedAt
+		p.Level = existing.Level
+		p.DisplayName = existing.DisplayName
+		p.DisplayNameChangedAt = existing.DisplayNameChangedAt
+	default:
+		p = Profile{PlayerID: p.PlayerID, Avatar: p.Avatar, TankSkin: p.TankSkin, Region: p.Region, CreatedAt: now}
+	}
+
+	p.UpdatedAt = now
+	p.Version++
+	r.profiles[p.PlayerID] = p
+	return &p, nil
+}
+
+func (r *MemoryRepository) Rename(_ context.Context, playerID, name string, cooldown time.Duration) (*Profile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	p, ok := r.profiles[playerID]
+	if !ok {
+		p = Profile{PlayerID: playerID, CreatedAt: now}
+	}
+	if p.DisplayName == name {
+		return &p, nil
+	}
+	if err := checkCooldown(&p, now, cooldown); err != nil {
+		return nil, err
+	}
+	if holder, taken := r.names[NameKey(name)]; taken && holder != playerID {
+		return nil, ErrNameTaken
+	}
+
+	if p.DisplayName != "" {
+		delete(r.names, NameKey(p.DisplayName))
+		r.history[playerID] = append(r.history[playerID], NameChange{Name: p.DisplayName, ReplacedAt: now})
+	}
+	r.names[NameKey(name)] = playerID
+
+	p.DisplayName = name
+	p.DisplayNameChangedAt = now
+	p.UpdatedAt = now
+	p.Version++
+	r.profiles[playerID] = p
+	return &p, nil
+}
+
+func (r *MemoryRepository) NameHistory(_ context.Context, playerID string) ([]NameChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]NameChange(nil), r.history[playerID]...), nil
+}
+
+func (r *MemoryRepository) Delete(_ context.Context, playerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if p, ok := r.profiles[playerID]; ok && p.DisplayName != "" {
+		delete(r.names, NameKey(p.DisplayName))
+	}
+	delete(r.profiles, playerID)
+	delete(r.history, playerID)
+	return nil
+}
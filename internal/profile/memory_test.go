@@ -0,0 +1,14 @@
+package profile
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := NewMemoryRepository()
+	repo.now = func() time.Time { return now }
+
+	testRepository(t, repo, func(d time.Duration) { now = now.Add(d) })
+}
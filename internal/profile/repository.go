@@ -0,0 +1,97 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound      = errors.New("profile not found")
+	ErrNameTaken     = errors.New("display name is taken")
+	ErrBatchTooLarge = errors.New("too many profiles requested at once")
+	// ErrVersionConflict is returned when a profile was changed since the
+	// caller read it, or created by someone else in the meantime.
+	ErrVersionConflict = errors.New("profile was modified concurrently")
+)
+
+// Profile is the game-facing data of a player, keyed by the player ID (the
+// Cognito sub). Version starts at 1 and goes up by one with every write.
+// Level is progress earned in game; it is written by the game servers, not
+// through Put. DisplayName is only changed through Rename.
+type Profile struct {
+	PlayerID             string
+	DisplayName          string
+	DisplayNameChangedAt time.Time
+	Avatar               string
+	TankSkin             string
+	Region               string
+	Level                int
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Version              int64
+}
+
+// NameChange is a display name a player used until ReplacedAt, kept so that
+// moderators can trace abusive names after a rename.
+type NameChange struct {
+	Name       string
+	ReplacedAt time.Time
+}
+
+// CooldownError is returned when a player renames again before the cooldown
+// since their last rename has passed.
+type CooldownError struct {
+	Until time.Time
+}
+
+func (e *CooldownError) Error() string {
+	return "display name was changed recently, try again after " + e.Until.UTC().Format(time.RFC3339)
+}
+
+// NameKey is what display names are compared by: two names that only differ
+// in case belong to the same player.
+func NameKey(name string) string {
+	return strings.ToLower(name)
+}
+
+// MaxBatchSize is the most profiles BatchGet looks up at once.
+const MaxBatchSize = 100
+
+// ProfileRepository stores player profiles.
+//
+// BatchGet returns the profiles that exist among up to MaxBatchSize player
+// IDs, in no particular order.
+//
+// Put writes p if the stored profile is still at p.Version, where version 0
+// means the profile must not exist yet. It returns the profile as stored,
+// with the new version and timestamps filled in, or ErrVersionConflict.
+//
+// Rename sets the display name, creating the profile if needed. It reserves
+// the name case-insensitively, releases the previous one and adds it to the
+// history NameHistory returns. It fails with ErrNameTaken if another player
+// holds the name, or a *CooldownError if the player renamed less than cooldown
+// ago. Setting the first name is not subject to the cooldown.
+//
+// Delete removes the profile with its name history and releases its display
+// name. Deleting a profile that does not exist is not an error.
+type ProfileRepository interface {
+	Get(ctx context.Context, playerID string) (*Profile, error)
+	BatchGet(ctx context.Context, playerIDs []string) ([]Profile, error)
+	Put(ctx context.Context, p Profile) (*Profile, error)
+	Rename(ctx context.Context, playerID, name string, cooldown time.Duration) (*Profile, error)
+	NameHistory(ctx context.Context, playerID string) ([]NameChange, error)
+	Delete(ctx context.Context, playerID string) error
+}
+
+// checkCooldown returns a *CooldownError if p may not be renamed at now.
+func checkCooldown(p *Profile, now time.Time, cooldown time.Duration) error {
+	if p == nil || p.DisplayName == "" {
+		return nil
+	}
+	if until := p.DisplayNameChangedAt.Add(cooldown); now.Before(until) {
+		return &CooldownError{Until: until}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package profile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRepository runs the behaviour every ProfileRepository must share.
+// advance moves the repository's clock forward.
+func testRepository(t *testing.T, repo ProfileRepository, advance func(d time.Duration)) {
+	ctx := context.Background()
+
+	t.Run("Not Found", func(t *testing.T) {
+		_, err := repo.Get(ctx, "missing")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Create And Update", func(t *testing.T) {
+		created, err := repo.Put(ctx, Profile{PlayerID: "player-1", DisplayName: "Tanker", Region: "eu"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), created.Version)
+		assert.Empty(t, created.DisplayName, "display names are only set through Rename")
+		assert.False(t, created.CreatedAt.IsZero())
+		assert.True(t, created.CreatedAt.Equal(created.UpdatedAt))
+
+		advance(time.Minute)
+
+		update := *created
+		update.TankSkin = "camo"
+		updated, err := repo.Put(ctx, update)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), updated.Version)
+		assert.Equal(t, "camo", updated.TankSkin)
+		assert.True(t, created.CreatedAt.Equal(updated.CreatedAt), "createdAt changed on update")
+		assert.Equal(t, time.Minute, updated.UpdatedAt.Sub(updated.CreatedAt))
+
+		got, err := repo.Get(ctx, "player-1")
+		require.NoError(t, err)
+		assert.Equal(t, updated.Version, got.Version)
+		assert.Equal(t, "camo", got.TankSkin)
+		assert.Equal(t, "eu", got.Region)
+	})
+
+	t.Run("Stale Version", func(t *testing.T) {
+		created, err := repo.Put(ctx, Profile{PlayerID: "player-2"})
+		require.NoError(t, err)
+		_, err = repo.Put(ctx, *created)
+		require.NoError(t, err)
+
+		_, err = repo.Put(ctx, *created)
+		assert.ErrorIs(t, err, ErrVersionConflict)
+	})
+
+	t.Run("Create Twice", func(t *testing.T) {
+		_, err := repo.Put(ctx, Profile{PlayerID: "player-3"})
+		require.NoError(t, err)
+
+		_, err = repo.Put(ctx, Profile{PlayerID: "player-3"})
+		assert.ErrorIs(t, err, ErrVersionConflict)
+	})
+
+	t.Run("Level Is Not Written", func(t *testing.T) {
+		created, err := repo.Put(ctx, Profile{PlayerID: "player-5", Level: 99})
+		require.NoError(t, err)
+		assert.Zero(t, created.Level)
+	})
+
+	t.Run("Batch Get", func(t *testing.T) {
+		for _, id := range []string{"batch-1", "batch-2"} {
+			_, err := repo.Rename(ctx, id, id, time.Hour)
+			require.NoError(t, err)
+		}
+
+		got, err := repo.BatchGet(ctx, []string{"batch-1", "missing", "batch-2", "batch-1"})
+		require.NoError(t, err)
+		names := make([]string, len(got))
+		for i, p := range got {
+			names[i] = p.DisplayName
+		}
+		assert.ElementsMatch(t, []string{"batch-1", "batch-2"}, names)
+
+		got, err = repo.BatchGet(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+
+		_, err = repo.BatchGet(ctx, make([]string, MaxBatchSize+1))
+		assert.ErrorIs(t, err, ErrBatchTooLarge)
+	})
+
+	t.Run("Rename", func(t *testing.T) {
+		named, err := repo.Rename(ctx, "renamer", "Commander", time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, "Commander", named.DisplayName)
+		assert.Equal(t, int64(1), named.Version)
+
+		_, err = repo.Rename(ctx, "other", "commander", time.Hour)
+		assert.ErrorIs(t, err, ErrNameTaken, "names are unique regardless of case")
+
+		var cooldownErr *CooldownError
+		_, err = repo.Rename(ctx, "renamer", "Captain", time.Hour)
+		require.ErrorAs(t, err, &cooldownErr)
+		assert.True(t, named.DisplayNameChangedAt.Add(time.Hour).Equal(cooldownErr.Until))
+
+		same, err := repo.Rename(ctx, "renamer", "Commander", time.Hour)
+		require.NoError(t, err, "keeping the same name is not a rename")
+		assert.Equal(t, named.Version, same.Version)
+
+		advance(time.Hour)
+
+		renamed, err := repo.Rename(ctx, "renamer", "Captain", time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, "Captain", renamed.DisplayName)
+		assert.Equal(t, int64(2), renamed.Version)
+
+		// The old name is released for others once it has been replaced.
+		_, err = repo.Rename(ctx, "other", "commander", time.Hour)
+		require.NoError(t, err)
+
+		history, err := repo.NameHistory(ctx, "renamer")
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, "Commander", history[0].Name)
+		assert.True(t, renamed.DisplayNameChangedAt.Equal(history[0].ReplacedAt))
+	})
+
+	t.Run("Rename Keeps Profile", func(t *testing.T) {
+		created, err := repo.Put(ctx, Profile{PlayerID: "keeper", Avatar: "avatar.png"})
+		require.NoError(t, err)
+
+		renamed, err := repo.Rename(ctx, "keeper", "Keeper", time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, "avatar.png", renamed.Avatar)
+		assert.Equal(t, created.Version+1, renamed.Version)
+
+		updated, err := repo.Put(ctx, Profile{PlayerID: "keeper", Avatar: "other.png", Version: renamed.Version})
+		require.NoError(t, err)
+		assert.Equal(t, "Keeper", updated.DisplayName, "Put keeps the display name")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		_, err := repo.Put(ctx, Profile{PlayerID: "leaver", Avatar: "avatar.png"})
+		require.NoError(t, err)
+		_, err = repo.Rename(ctx, "leaver", "Leaver", time.Hour)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Delete(ctx, "leaver"))
+		_, err = repo.Get(ctx, "leaver")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		_, err = repo.Rename(ctx, "stayer", "leaver", time.Hour)
+		require.NoError(t, err, "the name is released")
+
+		require.NoError(t, repo.Delete(ctx, "leaver"), "deleting twice is allowed")
+	})
+
+	t.Run("Update Missing", func(t *testing.T) {
+		_, err := repo.Put(ctx, Profile{PlayerID: "player-4", Version: 3})
+		assert.ErrorIs(t, err, ErrVersionConflict)
+	})
+}
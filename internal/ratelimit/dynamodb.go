@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const maxConditionalRetries = 3
+
+// DynamoDBLimiter stores one token bucket per key so that all Lambda instances
+// share the same budget. The table needs a string partition key named "key"
+// and should have TTL enabled on "expiresAt".
+type DynamoDBLimiter struct {
+	client *dynamodb.Client
+	table  string
+	now    func() time.Time
+}
+
+func NewDynamoDBLimiter(client *dynamodb.Client, table string) *DynamoDBLimiter {
+	return &DynamoDBLimiter{
+		client: client,
+		table:  table,
+		now:    time.Now,
+	}
+}
+
+func (l *DynamoDBLimiter) Allow(ctx context.Context, key string, rule Rule) (Decision, error) {
+	for i := 0; i < maxConditionalRetries; i++ {
+		decision, err := l.tryAllow(ctx, key, rule)
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			continue
+		}
+		return decision, err
+	}
+	return Decision{}, fmt.Errorf("rate limit bucket %s is under contention", key)
+}
+
+func (l *DynamoDBLimiter) tryAllow(ctx context.Context, key string, rule Rule) (Decision, error) {
+	output, err := l.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(l.table),
+		Key:            map[string]types.AttributeValue{"key": &types.AttributeValueMemberS{Value: key}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return Decision{}, err
+	}
+
+	now := l.now()
+	tokens, updated := float64(rule.Limit), now
+	var previous string
+	if output.Item != nil {
+		if tokens, err = numberAttribute(output.Item, "tokens"); err != nil {
+			return Decision{}, err
+		}
+		updatedAt, err := numberAttribute(output.Item, "updatedAt")
+		if err != nil {
+			return Decision{}, err
+		}
+		updated = time.UnixMilli(int64(updatedAt))
+		previous = output.Item["updatedAt"].(*types.AttributeValueMemberN).Value
+	}
+
+	tokens, decision := take(rule, tokens, updated, now)
+	if !decision.Allowed {
+		return decision, nil
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(l.table),
+		Item: map[string]types.AttributeValue{
+			"key":       &types.AttributeValueMemberS{Value: key},
+			"tokens":    &types.AttributeValueMemberN{Value: strconv.FormatFloat(tokens, 'f', -1, 64)},
+			"updatedAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.UnixMilli(), 10)},
+			"expiresAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(rule.Interval).Unix(), 10)},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(#key)"),
+		ExpressionAttributeNames: map[string]string{
+			"#key": "key",
+		},
+	}
+	if previous != "" {
+		// Only overwrite the bucket we read, so concurrent requests cannot
+		// both spend the same token.
+		input.ConditionExpression = aws.String("#updatedAt = :previous")
+		input.ExpressionAttributeNames = map[string]string{"#updatedAt": "updatedAt"}
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":previous": &types.AttributeValueMemberN{Value: previous},
+		}
+	}
+
+	if _, err = l.client.PutItem(ctx, input); err != nil {
+		return Decision{}, err
+	}
+
+	return decision, nil
+}
+
+func numberAttribute(item map[string]types.AttributeValue, name string) (float64, error) {
+	av, ok := item[name].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, fmt.Errorf("attribute %s is not a number", name)
+	}
+	return strconv.ParseFloat(av.Value, 64)
+}
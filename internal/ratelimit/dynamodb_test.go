@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/whatisusername/toon-tank-user-service/internal/testutil"
+)
+
+func TestDynamoDBLimiter_Allow(t *testing.T) {
+	endpoint := testutil.LocalStackEndpoint(t)
+	client := testutil.NewDynamoDBClient(t, endpoint)
+	testutil.CreateTable(t, client, "rate-limits", "key", "")
+
+	rule := Rule{Limit: 2, Interval: time.Minute}
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		key     string
+		want    Decision
+	}{
+		{
+			name:    "First Request",
+			elapsed: 0,
+			key:     "user:test",
+			want:    Decision{Allowed: true},
+		},
+		{
+			name:    "Within Burst",
+			elapsed: time.Millisecond,
+			key:     "user:test",
+			want:    Decision{Allowed: true},
+		},
+		{
+			name:    "Burst Exhausted",
+			elapsed: time.Millisecond,
+			key:     "user:test",
+			want:    Decision{Allowed: false, RetryAfter: 29998 * time.Millisecond},
+		},
+		{
+			name:    "Other Key",
+			elapsed: 0,
+			key:     "user:other",
+			want:    Decision{Allowed: true},
+		},
+		{
+			name:    "Refilled",
+			elapsed: 30 * time.Second,
+			key:     "user:test",
+			want:    Decision{Allowed: true},
+		},
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewDynamoDBLimiter(client, "rate-limits")
+	l.now = func() time.Time { return now }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.elapsed)
+
+			got, err := l.Allow(context.Background(), tt.key, rule)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got, "Allow() returned unexpected result")
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Rule describes a token bucket that holds up to Limit tokens and refills
+// completely over Interval.
+type Rule struct {
+	Limit    int
+	Interval time.Duration
+}
+
+func (r Rule) ratePerSecond() float64 {
+	return float64(r.Limit) / r.Interval.Seconds()
+}
+
+type Decision struct {
+	Allowed    bool
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, rule Rule) (Decision, error)
+}
+
+// take refills a bucket that last held tokens at updated and tries to take one
+// token from it at now. It returns the tokens left and the decision.
+func take(rule Rule, tokens float64, updated, now time.Time) (float64, Decision) {
+	elapsed := now.Sub(updated).Seconds()
+	if elapsed > 0 {
+		tokens = math.Min(float64(rule.Limit), tokens+elapsed*rule.ratePerSecond())
+	}
+
+	if tokens < 1 {
+		wait := (1 - tokens) / rule.ratePerSecond()
+		return tokens, Decision{Allowed: false, RetryAfter: time.Duration(math.Round(wait*1000)) * time.Millisecond}
+	}
+
+	return tokens - 1, Decision{Allowed: true}
+}
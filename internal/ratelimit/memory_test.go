@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLimiter_Allow(t *testing.T) {
+	rule := Rule{Limit: 2, Interval: time.Minute}
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		key     string
+		want    Decision
+	}{
+		{
+			name:    "First Request",
+			elapsed: 0,
+			key:     "ip:1.2.3.4",
+			want:    Decision{Allowed: true},
+		},
+		{
+			name:    "Within Burst",
+			elapsed: 0,
+			key:     "ip:1.2.3.4",
+			want:    Decision{Allowed: true},
+		},
+		{
+			name:    "Burst Exhausted",
+			elapsed: 0,
+			key:     "ip:1.2.3.4",
+			want:    Decision{Allowed: false, RetryAfter: 30 * time.Second},
+		},
+		{
+			name:    "Other Key",
+			elapsed: 0,
+			key:     "ip:5.6.7.8",
+			want:    Decision{Allowed: true},
+		},
+		{
+			name:    "Partially Refilled",
+			elapsed: 10 * time.Second,
+			key:     "ip:1.2.3.4",
+			want:    Decision{Allowed: false, RetryAfter: 20 * time.Second},
+		},
+		{
+			name:    "Refilled",
+			elapsed: 20 * time.Second,
+			key:     "ip:1.2.3.4",
+			want:    Decision{Allowed: true},
+		},
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewMemoryLimiter()
+	l.now = func() time.Time { return now }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.elapsed)
+
+			got, err := l.Allow(context.Background(), tt.key, rule)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got, "Allow() returned unexpected result")
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		exporter string
+		wantErr  bool
+	}{
+		{
+			name:     "No-op",
+			exporter: ExporterNone,
+			wantErr:  false,
+		},
+		{
+			name:     "Default",
+			exporter: "",
+			wantErr:  false,
+		},
+		{
+			name:     "Stdout",
+			exporter: ExporterStdout,
+			wantErr:  false,
+		},
+		{
+			name:     "OTLP",
+			exporter: ExporterOTLP,
+			wantErr:  false,
+		},
+		{
+			name:     "Unsupported Exporter",
+			exporter: "zipkin",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := otel.GetTracerProvider()
+			t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+			got, err := NewTracerProvider(context.Background(), tt.exporter)
+
+			if tt.wantErr {
+				assert.Error(t, err, "expected an error but got none")
+				assert.Nil(t, got)
+				return
+			}
+
+			require.NoError(t, err, "unexpected error: %v", err)
+			assert.Same(t, got, otel.GetTracerProvider(), "NewTracerProvider() did not install the global provider")
+			assert.NoError(t, got.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestEndSpan(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantStatus    codes.Code
+		wantErrorType string
+	}{
+		{
+			name:          "OK",
+			err:           nil,
+			wantStatus:    codes.Unset,
+			wantErrorType: "",
+		},
+		{
+			name:          "AWS Error",
+			err:           &smithy.GenericAPIError{Code: "UsernameExistsException", Message: "User already exists"},
+			wantStatus:    codes.Error,
+			wantErrorType: "UsernameExistsException",
+		},
+		{
+			name:          "Other Error",
+			err:           errors.New("timeout"),
+			wantStatus:    codes.Error,
+			wantErrorType: "*errors.errorString",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			_, span := tp.Tracer("test").Start(context.Background(), "test")
+			EndSpan(span, tt.err)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			assert.Equal(t, tt.wantStatus, spans[0].Status().Code)
+
+			var gotErrorType string
+			for _, attr := range spans[0].Attributes() {
+				if attr.Key == ErrorTypeKey {
+					gotErrorType = attr.Value.AsString()
+				}
+			}
+			assert.Equal(t, tt.wantErrorType, gotErrorType)
+		})
+	}
+}
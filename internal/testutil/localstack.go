@@ -0,0 +1,76 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/localstack"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+)
+
+const localStackImage = "localstack/localstack:4.0.3"
+
+// LocalStackEndpoint starts a LocalStack container for the duration of the
+// test and returns its edge endpoint.
+func LocalStackEndpoint(t *testing.T) string {
+	t.Helper()
+	ctx := context.Background()
+
+	container, err := localstack.Run(ctx, localStackImage)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, container.Terminate(ctx), "failed to terminate container")
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	mappedPort, err := container.MappedPort(ctx, "4566/tcp")
+	require.NoError(t, err)
+
+	return fmt.Sprintf("http://%s:%s", host, mappedPort.Port())
+}
+
+func NewDynamoDBClient(t *testing.T, endpoint string) *dynamodb.Client {
+	t.Helper()
+
+	client, err := caws.NewDynamoDBClient(context.Background(), func(o *dynamodb.Options) {
+		o.Region = "us-east-1"
+		o.Credentials = aws.AnonymousCredentials{}
+		o.BaseEndpoint = &endpoint
+	})
+	require.NoError(t, err)
+
+	return client
+}
+
+// CreateTable creates an on-demand table keyed by the given string attributes.
+// rangeKey may be empty for tables with a simple primary key.
+func CreateTable(t *testing.T, client *dynamodb.Client, name, hashKey, rangeKey string) {
+	t.Helper()
+
+	attrs := []types.AttributeDefinition{
+		{AttributeName: aws.String(hashKey), AttributeType: types.ScalarAttributeTypeS},
+	}
+	keys := []types.KeySchemaElement{
+		{AttributeName: aws.String(hashKey), KeyType: types.KeyTypeHash},
+	}
+	if rangeKey != "" {
+		attrs = append(attrs, types.AttributeDefinition{AttributeName: aws.String(rangeKey), AttributeType: types.ScalarAttributeTypeS})
+		keys = append(keys, types.KeySchemaElement{AttributeName: aws.String(rangeKey), KeyType: types.KeyTypeRange})
+	}
+
+	_, err := client.CreateTable(context.Background(), &dynamodb.CreateTableInput{
+		TableName:            aws.String(name),
+		AttributeDefinitions: attrs,
+		KeySchema:            keys,
+		BillingMode:          types.BillingModePayPerRequest,
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,96 @@
+package testutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/require"
+	caws "github.com/whatisusername/toon-tank-user-service/internal/aws"
+)
+
+func NewEventBridgeClient(t *testing.T, endpoint string) *eventbridge.Client {
+	t.Helper()
+
+	client, err := caws.NewEventBridgeClient(context.Background(), func(o *eventbridge.Options) {
+		o.Region = "us-east-1"
+		o.Credentials = aws.AnonymousCredentials{}
+		o.BaseEndpoint = &endpoint
+	})
+	require.NoError(t, err)
+
+	return client
+}
+
+func NewSNSClient(t *testing.T, endpoint string) *sns.Client {
+	t.Helper()
+
+	client, err := caws.NewSNSClient(context.Background(), func(o *sns.Options) {
+		o.Region = "us-east-1"
+		o.Credentials = aws.AnonymousCredentials{}
+		o.BaseEndpoint = &endpoint
+	})
+	require.NoError(t, err)
+
+	return client
+}
+
+func NewSQSClient(t *testing.T, endpoint string) *sqs.Client {
+	t.Helper()
+
+	client, err := caws.NewSQSClient(context.Background(), func(o *sqs.Options) {
+		o.Region = "us-east-1"
+		o.Credentials = aws.AnonymousCredentials{}
+		o.BaseEndpoint = &endpoint
+	})
+	require.NoError(t, err)
+
+	return client
+}
+
+// CreateQueue creates a standard queue and returns its URL and ARN.
+func CreateQueue(t *testing.T, client *sqs.Client, name string) (string, string) {
+	t.Helper()
+	ctx := context.Background()
+
+	created, err := client.CreateQueue(ctx, &sqs.CreateQueueInput{QueueName: aws.String(name)})
+	require.NoError(t, err)
+
+	attrs, err := client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       created.QueueUrl,
+		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameQueueArn},
+	})
+	require.NoError(t, err)
+
+	return aws.ToString(created.QueueUrl), attrs.Attributes[string(sqstypes.QueueAttributeNameQueueArn)]
+}
+
+// ReceiveMessages polls queueURL until want messages have arrived or the
+// deadline passes, and returns their bodies.
+func ReceiveMessages(t *testing.T, client *sqs.Client, queueURL string, want int) []string {
+	t.Helper()
+	ctx := context.Background()
+
+	var bodies []string
+	deadline := time.Now().Add(10 * time.Second)
+	for len(bodies) < want && time.Now().Before(deadline) {
+		output, err := client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(queueURL),
+			MaxNumberOfMessages: 10,
+			WaitTimeSeconds:     1,
+		})
+		require.NoError(t, err)
+
+		for _, m := range output.Messages {
+			bodies = append(bodies, aws.ToString(m.Body))
+		}
+	}
+	require.Len(t, bodies, want, "timed out waiting for messages")
+
+	return bodies
+}
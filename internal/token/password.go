@@ -0,0 +1,108 @@
+package token
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrUnsupportedHash is returned for password hashes in a format no verifier
+// understands.
+var ErrUnsupportedHash = errors.New("unsupported password hash format")
+
+// PasswordVerifier checks a password against a stored hash. A wrong password
+// is reported as false with a nil error; errors mean the hash itself could not
+// be used.
+type PasswordVerifier interface {
+	Verify(encodedHash, password string) (bool, error)
+}
+
+// BcryptVerifier verifies "$2a$", "$2b$" and "$2y$" hashes.
+type BcryptVerifier struct{}
+
+func (BcryptVerifier) Verify(encodedHash, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Limits on the cost a stored hash may ask for, so a bad hash can't exhaust
+// the memory of the trigger verifying it (128 MB by default) or keep it busy
+// past its timeout. argon2MaxMemory is in KiB.
+const (
+	argon2MaxMemory     = 64 * 1024
+	argon2MaxIterations = 10
+)
+
+// Argon2idVerifier verifies hashes in the PHC string format, e.g.
+// "$argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>" with unpadded base64 salt and
+// hash.
+type Argon2idVerifier struct{}
+
+func (Argon2idVerifier) Verify(encodedHash, password string) (bool, error) {
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return false, ErrUnsupportedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return false, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return false, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+
+	var memory, iterations uint32
+	var parallelism uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &parallelism); err != nil {
+		return false, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+	switch {
+	case iterations < 1:
+		return false, errors.New("invalid argon2id parameters: t must be at least 1")
+	case iterations > argon2MaxIterations:
+		return false, fmt.Errorf("invalid argon2id parameters: t must be at most %d", argon2MaxIterations)
+	case parallelism < 1:
+		return false, errors.New("invalid argon2id parameters: p must be at least 1")
+	case memory > argon2MaxMemory:
+		return false, fmt.Errorf("invalid argon2id parameters: m must be at most %d", argon2MaxMemory)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, fmt.Errorf("invalid argon2id hash: %w", err)
+	}
+	if len(salt) == 0 || len(want) == 0 {
+		return false, errors.New("invalid argon2id hash: salt and hash must not be empty")
+	}
+
+	got := argon2.IDKey([]byte(password), salt, iterations, memory, parallelism, uint32(len(want)))
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
+
+// VerifyPassword picks the verifier for encodedHash by its prefix.
+func VerifyPassword(encodedHash, password string) (bool, error) {
+	switch {
+	case strings.HasPrefix(encodedHash, "$2a$"), strings.HasPrefix(encodedHash, "$2b$"), strings.HasPrefix(encodedHash, "$2y$"):
+		return BcryptVerifier{}.Verify(encodedHash, password)
+	case strings.HasPrefix(encodedHash, "$argon2id$"):
+		return Argon2idVerifier{}.Verify(encodedHash, password)
+	default:
+		return false, ErrUnsupportedHash
+	}
+}
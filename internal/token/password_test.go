@@ -0,0 +1,132 @@
+package token
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestVerifyPassword(t *testing.T) {
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("test123456A"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	salt := []byte("fake_salt_16byte")
+	key := argon2.IDKey([]byte("test123456A"), salt, 1, 64*1024, 2, 32)
+	argon2Hash := fmt.Sprintf("$argon2id$v=%d$m=65536,t=1,p=2$%s$%s",
+		argon2.Version, base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		want     bool
+		wantErr  bool
+	}{
+		{
+			name:     "Bcrypt Match",
+			hash:     string(bcryptHash),
+			password: "test123456A",
+			want:     true,
+		},
+		{
+			name:     "Bcrypt Mismatch",
+			hash:     string(bcryptHash),
+			password: "test123456B",
+			want:     false,
+		},
+		{
+			name:     "Bcrypt 2y Prefix",
+			hash:     "$2y$" + string(bcryptHash)[4:],
+			password: "test123456A",
+			want:     true,
+		},
+		{
+			name:     "Argon2id Match",
+			hash:     argon2Hash,
+			password: "test123456A",
+			want:     true,
+		},
+		{
+			name:     "Argon2id Mismatch",
+			hash:     argon2Hash,
+			password: "test123456B",
+			want:     false,
+		},
+		{
+			name:     "Argon2id Bad Parameters",
+			hash:     "$argon2id$v=19$m=lots,t=1,p=2$c2FsdA$aGFzaA",
+			password: "test123456A",
+			wantErr:  true,
+		},
+		{
+			name:     "Argon2id Empty Salt",
+			hash:     "$argon2id$v=19$m=65536,t=1,p=2$$aGFzaA",
+			password: "test123456A",
+			wantErr:  true,
+		},
+		{
+			name:     "Argon2id Empty Hash",
+			hash:     "$argon2id$v=19$m=65536,t=1,p=2$c2FsdA$",
+			password: "test123456A",
+			wantErr:  true,
+		},
+		{
+			name:     "Argon2id Zero Iterations",
+			hash:     "$argon2id$v=19$m=65536,t=0,p=2$c2FsdA$aGFzaA",
+			password: "test123456A",
+			wantErr:  true,
+		},
+		{
+			name:     "Argon2id Zero Parallelism",
+			hash:     "$argon2id$v=19$m=65536,t=1,p=0$c2FsdA$aGFzaA",
+			password: "test123456A",
+			wantErr:  true,
+		},
+		{
+			name:     "Argon2id Too Much Memory",
+			hash:     "$argon2id$v=19$m=4194304,t=1,p=2$c2FsdA$aGFzaA",
+			password: "test123456A",
+			wantErr:  true,
+		},
+		{
+			name:     "Argon2id Just Over Memory Limit",
+			hash:     "$argon2id$v=19$m=65537,t=1,p=2$c2FsdA$aGFzaA",
+			password: "test123456A",
+			wantErr:  true,
+		},
+		{
+			name:     "Argon2id Too Many Iterations",
+			hash:     "$argon2id$v=19$m=65536,t=11,p=2$c2FsdA$aGFzaA",
+			password: "test123456A",
+			wantErr:  true,
+		},
+		{
+			name:     "Argon2id Wrong Version",
+			hash:     "$argon2id$v=16$m=65536,t=1,p=2$c2FsdA$aGFzaA",
+			password: "test123456A",
+			wantErr:  true,
+		},
+		{
+			name:     "Unsupported Format",
+			hash:     "5f4dcc3b5aa765d61d8327deb882cf99",
+			password: "password",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyPassword(tt.hash, tt.password)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
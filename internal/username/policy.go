@@ -0,0 +1,142 @@
+package username
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/whatisusername/toon-tank-user-service/internal/validation"
+)
+
+const (
+	CodeTooShort     = "UsernameTooShort"
+	CodeTooLong      = "UsernameTooLong"
+	CodeInvalidChars = "UsernameInvalidCharacters"
+	CodeReserved     = "UsernameReserved"
+	CodeProfane      = "UsernameProfane"
+	CodeConfusable   = "UsernameConfusable"
+)
+
+// DefaultReserved are names that could be mistaken for staff or the system.
+var DefaultReserved = []string{
+	"admin", "administrator", "mod", "moderator", "support", "staff", "help",
+	"helpdesk", "system", "root", "security", "official", "toontank", "gm",
+	"gamemaster", "null", "undefined",
+}
+
+type Policy struct {
+	MinLength int
+	MaxLength int
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLength: 3,
+		MaxLength: 20,
+	}
+}
+
+type Validator struct {
+	policy    Policy
+	reserved  map[string]struct{}
+	profanity []string
+}
+
+// NewValidator matches reserved names exactly and profanity as substrings,
+// both after case folding, confusable folding and leetspeak normalisation.
+func NewValidator(policy Policy, reserved, profanity []string) *Validator {
+	v := &Validator{
+		policy:   policy,
+		reserved: make(map[string]struct{}, len(reserved)),
+	}
+	for _, r := range reserved {
+		v.reserved[deleet(r)] = struct{}{}
+	}
+	for _, p := range profanity {
+		if p = deleet(p); p != "" {
+			v.profanity = append(v.profanity, p)
+		}
+	}
+	return v
+}
+
+// Validate returns every rule username breaks, attributing them to field.
+func (v *Validator) Validate(field, username string) []validation.Violation {
+	var violations []validation.Violation
+
+	length := utf8.RuneCountInString(username)
+	if length < v.policy.MinLength {
+		violations = append(violations, validation.Violation{
+			Field:   field,
+			Code:    CodeTooShort,
+			Message: fmt.Sprintf("must be at least %d characters long", v.policy.MinLength),
+		})
+	}
+	if v.policy.MaxLength > 0 && length > v.policy.MaxLength {
+		violations = append(violations, validation.Violation{
+			Field:   field,
+			Code:    CodeTooLong,
+			Message: fmt.Sprintf("must be at most %d characters long", v.policy.MaxLength),
+		})
+	}
+
+	for _, r := range username {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			violations = append(violations, validation.Violation{Field: field, Code: CodeInvalidChars, Message: "may only contain letters and digits"})
+			break
+		}
+	}
+
+	// A non-ASCII name whose skeleton is plain ASCII is only there to look
+	// like another name.
+	if !isASCII(username) && isASCII(Skeleton(username)) {
+		violations = append(violations, validation.Violation{Field: field, Code: CodeConfusable, Message: "contains characters that imitate other letters"})
+	}
+
+	normalized := deleet(username)
+	if _, ok := v.reserved[normalized]; ok {
+		violations = append(violations, validation.Violation{Field: field, Code: CodeReserved, Message: "is reserved"})
+	}
+	for _, word := range v.profanity {
+		if strings.Contains(normalized, word) {
+			violations = append(violations, validation.Violation{Field: field, Code: CodeProfane, Message: "contains inappropriate language"})
+			break
+		}
+	}
+
+	return violations
+}
+
+// LoadWordList reads one word per line, ignoring blank lines and "#"
+// comments.
+func LoadWordList(r io.Reader) ([]string, error) {
+	var words []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return words, nil
+}
+
+func LoadWordListFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadWordList(f)
+}
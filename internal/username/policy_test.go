@@ -0,0 +1,96 @@
+package username
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidator_Validate(t *testing.T) {
+	validator := NewValidator(DefaultPolicy(), DefaultReserved, []string{"darn", "heck"})
+
+	tests := []struct {
+		name      string
+		username  string
+		wantCodes []string
+	}{
+		{
+			name:      "Valid",
+			username:  "TankCommander",
+			wantCodes: nil,
+		},
+		{
+			name:      "Valid Non-ASCII",
+			username:  "タンク好き",
+			wantCodes: nil,
+		},
+		{
+			name:      "Too Short",
+			username:  "ab",
+			wantCodes: []string{CodeTooShort},
+		},
+		{
+			name:      "Too Long",
+			username:  strings.Repeat("a", 21),
+			wantCodes: []string{CodeTooLong},
+		},
+		{
+			name:      "Invalid Characters",
+			username:  "tank_commander",
+			wantCodes: []string{CodeInvalidChars},
+		},
+		{
+			name:      "Reserved",
+			username:  "Admin",
+			wantCodes: []string{CodeReserved},
+		},
+		{
+			name:      "Reserved Leetspeak",
+			username:  "M0d3r4t0r",
+			wantCodes: []string{CodeReserved},
+		},
+		{
+			name:      "Reserved Confusable",
+			username:  "аdmin",
+			wantCodes: []string{CodeConfusable, CodeReserved},
+		},
+		{
+			name:      "Confusable",
+			username:  "TankСommander",
+			wantCodes: []string{CodeConfusable},
+		},
+		{
+			name:      "Profane",
+			username:  "DarnTank",
+			wantCodes: []string{CodeProfane},
+		},
+		{
+			name:      "Profane Leetspeak",
+			username:  "TankH3ck",
+			wantCodes: []string{CodeProfane},
+		},
+		{
+			name:      "Reserved Not Substring",
+			username:  "Supporter",
+			wantCodes: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var codes []string
+			for _, v := range validator.Validate("username", tt.username) {
+				assert.Equal(t, "username", v.Field)
+				codes = append(codes, v.Code)
+			}
+			assert.Equal(t, tt.wantCodes, codes)
+		})
+	}
+}
+
+func TestLoadWordList(t *testing.T) {
+	words, err := LoadWordList(strings.NewReader("# words\ndarn\n\n  heck  \n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"darn", "heck"}, words)
+}
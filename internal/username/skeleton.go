@@ -0,0 +1,67 @@
+package username
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// confusables maps characters from other scripts that render like a Latin
+// letter to that letter. It covers the Cyrillic and Greek lookalikes that
+// matter for impersonation; NFKC already folds fullwidth and other
+// compatibility forms.
+var confusables = map[rune]rune{
+	'а': 'a', 'в': 'b', 'е': 'e', 'ё': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o',
+	'р': 'p', 'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'і': 'i', 'ї': 'i', 'ј': 'j',
+	'ѕ': 's', 'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w', 'ɡ': 'g', 'ı': 'i',
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o',
+	'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x',
+}
+
+// leet maps digits and symbols commonly substituted for letters.
+var leet = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b', '9': 'g',
+	'@': 'a', '$': 's', '!': 'i', '|': 'l', '+': 't',
+}
+
+// Skeleton reduces s to the lowercase Latin string it looks like, so names
+// that only differ by case, compatibility forms or cross-script lookalikes
+// compare equal.
+func Skeleton(s string) string {
+	s = norm.NFKC.String(s)
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if c, ok := confusables[r]; ok {
+			r = c
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// deleet additionally undoes leetspeak and drops separators, for matching
+// against word lists rather than for display.
+func deleet(s string) string {
+	var b strings.Builder
+	for _, r := range Skeleton(s) {
+		if c, ok := leet[r]; ok {
+			r = c
+		}
+		if r == '_' || r == '-' || r == '.' || unicode.IsSpace(r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
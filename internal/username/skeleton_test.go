@@ -0,0 +1,52 @@
+package username
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "ASCII",
+			input: "Player1",
+			want:  "player1",
+		},
+		{
+			name:  "Cyrillic Lookalikes",
+			input: "аdmіn",
+			want:  "admin",
+		},
+		{
+			name:  "Greek Lookalikes",
+			input: "Ροοt",
+			want:  "poot",
+		},
+		{
+			name:  "Fullwidth",
+			input: "ＡＤＭＩＮ",
+			want:  "admin",
+		},
+		{
+			name:  "Other Scripts Kept",
+			input: "プレイヤー",
+			want:  "プレイヤー",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Skeleton(tt.input))
+		})
+	}
+}
+
+func Test_deleet(t *testing.T) {
+	assert.Equal(t, "admin", deleet("4dm1n"))
+	assert.Equal(t, "moderator", deleet("M0d_3r-4t0r"))
+	assert.Equal(t, "support", deleet("$upp0r7"))
+}
@@ -0,0 +1,22 @@
+package validation
+
+import "strings"
+
+// Violation describes one reason a field was rejected. Code is stable and
+// meant for clients to branch on; Message is for humans.
+type Violation struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Violations lets a set of violations travel as an error.
+type Violations []Violation
+
+func (v Violations) Error() string {
+	messages := make([]string, len(v))
+	for i, violation := range v {
+		messages[i] = violation.Field + ": " + violation.Message
+	}
+	return strings.Join(messages, "; ")
+}
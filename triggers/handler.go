@@ -0,0 +1,97 @@
+package triggers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/whatisusername/toon-tank-user-service/internal/email"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/legacy"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+	"github.com/whatisusername/toon-tank-user-service/internal/username"
+)
+
+const (
+	triggerPreSignUp        = "PreSignUp_"
+	triggerPostConfirmation = "PostConfirmation_"
+	triggerTokenGeneration  = "TokenGeneration_"
+	triggerUserMigration    = "UserMigration_"
+)
+
+// Handler answers the Cognito user pool triggers. A single function is
+// attached to every trigger and HandleRequest tells them apart by the
+// triggerSource of the event.
+type Handler struct {
+	usernames *username.Validator
+	emails    *email.Validator
+	events    cevents.EventPublisher
+
+	legacyUsers legacy.LegacyUserStore
+}
+
+func NewHandler(opts ...Option) *Handler {
+	h := &Handler{
+		usernames: username.NewValidator(username.DefaultPolicy(), username.DefaultReserved, nil),
+		emails:    email.NewValidator(false, nil),
+		events:    cevents.NoopPublisher{},
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// HandleRequest decodes event into the type for its trigger and returns the
+// event with the response filled in, which is what Cognito expects back.
+// Trigger sources without a handler are returned unchanged.
+func (h *Handler) HandleRequest(ctx context.Context, event json.RawMessage) (interface{}, error) {
+	var header events.CognitoEventUserPoolsHeader
+	if err := json.Unmarshal(event, &header); err != nil {
+		return nil, fmt.Errorf("failed to decode trigger event: %w", err)
+	}
+
+	logger := slog.Default().With("triggerSource", header.TriggerSource, "userPoolId", header.UserPoolID)
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		logger = logger.With("requestId", lc.AwsRequestID)
+	}
+	ctx = logging.WithLogger(ctx, logger)
+
+	switch {
+	case strings.HasPrefix(header.TriggerSource, triggerPreSignUp):
+		return decodeAndHandle(ctx, event, h.preSignUp)
+	case strings.HasPrefix(header.TriggerSource, triggerPostConfirmation):
+		return decodeAndHandle(ctx, event, h.postConfirmation)
+	case strings.HasPrefix(header.TriggerSource, triggerTokenGeneration) && header.Version == "1":
+		return decodeAndHandle(ctx, event, h.preTokenGeneration)
+	case strings.HasPrefix(header.TriggerSource, triggerTokenGeneration):
+		return decodeAndHandle(ctx, event, h.preTokenGenerationV2)
+	case strings.HasPrefix(header.TriggerSource, triggerUserMigration):
+		return decodeAndHandle(ctx, event, h.migrateUser)
+	default:
+		logger.Warn("Ignoring unhandled trigger")
+		return event, nil
+	}
+}
+
+func decodeAndHandle[T any](ctx context.Context, raw json.RawMessage, handle func(context.Context, T) (T, error)) (interface{}, error) {
+	var event T
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode trigger event: %w", err)
+	}
+	return handle(ctx, event)
+}
+
+// correlationID ties events raised by a trigger to the Lambda invocation.
+func correlationID(ctx context.Context) string {
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		return lc.AwsRequestID
+	}
+	return ""
+}
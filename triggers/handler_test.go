@@ -0,0 +1,64 @@
+package triggers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_HandleRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "Unhandled Trigger",
+			event: `{"version":"1","triggerSource":"CustomMessage_SignUp","userName":"test","request":{},"response":{}}`,
+			want:  `{"version":"1","triggerSource":"CustomMessage_SignUp","userName":"test","request":{},"response":{}}`,
+		},
+		{
+			name:    "Not A Cognito Event",
+			event:   `[]`,
+			wantErr: true,
+		},
+		{
+			name:    "Malformed Trigger Event",
+			event:   `{"version":"1","triggerSource":"PreSignUp_SignUp","request":{"userAttributes":[]}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewHandler().HandleRequest(context.Background(), json.RawMessage(tt.event))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			data, err := json.Marshal(got)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+// handle runs event through HandleRequest and decodes the response into out.
+func handle(t *testing.T, h *Handler, event string, out interface{}) error {
+	t.Helper()
+
+	got, err := h.HandleRequest(context.Background(), json.RawMessage(event))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(got)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, out))
+	return nil
+}
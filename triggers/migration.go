@@ -0,0 +1,78 @@
+package triggers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/legacy"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+	"github.com/whatisusername/toon-tank-user-service/internal/token"
+)
+
+const (
+	triggerMigrationAuthentication = "UserMigration_Authentication"
+	triggerMigrationForgotPassword = "UserMigration_ForgotPassword"
+)
+
+// Cognito answers sign-in with the same error whatever the trigger returns, so
+// these only show up in the logs.
+var (
+	errLegacyUserNotFound   = errors.New("user not found")
+	errLegacyWrongPassword  = errors.New("incorrect username or password")
+	errMigrationUnavailable = errors.New("user migration is not configured")
+)
+
+// migrateUser lets players from the prototype sign in with their old
+// password. Cognito calls it when the username is not in the pool; returning
+// the user's attributes makes Cognito create the user, confirmed and with the
+// password they just typed, without sending them a welcome message. Players
+// who start with a password reset are migrated without a password and set one
+// through the reset.
+func (h *Handler) migrateUser(ctx context.Context, event events.CognitoEventUserPoolsMigrateUser) (events.CognitoEventUserPoolsMigrateUser, error) {
+	logger := logging.FromContext(ctx).With("username", event.UserName)
+
+	if h.legacyUsers == nil {
+		return event, errMigrationUnavailable
+	}
+
+	user, err := h.legacyUsers.FindUser(ctx, event.UserName)
+	if errors.Is(err, legacy.ErrUserNotFound) {
+		return event, errLegacyUserNotFound
+	}
+	if err != nil {
+		logger.Error("Failed to look up legacy user", "error", err)
+		return event, err
+	}
+
+	switch event.TriggerSource {
+	case triggerMigrationAuthentication:
+		ok, err := token.VerifyPassword(user.PasswordHash, event.Password)
+		if err != nil {
+			logger.Error("Failed to verify legacy password", "error", err)
+			return event, err
+		}
+		if !ok {
+			return event, errLegacyWrongPassword
+		}
+		event.FinalUserStatus = "CONFIRMED"
+	case triggerMigrationForgotPassword:
+		event.FinalUserStatus = "RESET_REQUIRED"
+	default:
+		return event, nil
+	}
+
+	event.UserAttributes = map[string]string{}
+	if user.Email != "" {
+		// Cognito only sends reset codes to verified addresses, and entering
+		// the code proves the player reads that inbox.
+		verified := user.EmailVerified || event.TriggerSource == triggerMigrationForgotPassword
+		event.UserAttributes["email"] = user.Email
+		event.UserAttributes["email_verified"] = strconv.FormatBool(verified)
+	}
+	event.MessageAction = "SUPPRESS"
+
+	logger.Info("Migrated legacy user", "triggerSource", event.TriggerSource)
+	return event, nil
+}
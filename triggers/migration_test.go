@@ -0,0 +1,113 @@
+package triggers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/whatisusername/toon-tank-user-service/internal/legacy"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type unavailableStore struct{}
+
+func (unavailableStore) FindUser(context.Context, string) (*legacy.User, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestHandler_migrateUser(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("test123456A"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	store, err := legacy.LoadFileStore(strings.NewReader(fmt.Sprintf(`
+{"username":"test","email":"test@example.com","emailVerified":true,"passwordHash":%q}
+{"username":"unverified","email":"unverified@example.com","passwordHash":%q}
+{"username":"broken","email":"broken@example.com","passwordHash":"md5:5f4dcc3b5aa765d61d8327deb882cf99"}
+`, hash, hash)))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		store      legacy.LegacyUserStore
+		event      string
+		wantErr    error
+		wantStatus string
+		wantAttrs  map[string]string
+	}{
+		{
+			name:       "Authentication",
+			store:      store,
+			event:      `{"version":"1","triggerSource":"UserMigration_Authentication","userName":"Test","request":{"password":"test123456A"},"response":{}}`,
+			wantStatus: "CONFIRMED",
+			wantAttrs:  map[string]string{"email": "test@example.com", "email_verified": "true"},
+		},
+		{
+			name:       "Unverified Email",
+			store:      store,
+			event:      `{"version":"1","triggerSource":"UserMigration_Authentication","userName":"unverified","request":{"password":"test123456A"},"response":{}}`,
+			wantStatus: "CONFIRMED",
+			wantAttrs:  map[string]string{"email": "unverified@example.com", "email_verified": "false"},
+		},
+		{
+			name:    "Wrong Password",
+			store:   store,
+			event:   `{"version":"1","triggerSource":"UserMigration_Authentication","userName":"test","request":{"password":"test123456B"},"response":{}}`,
+			wantErr: errLegacyWrongPassword,
+		},
+		{
+			name:    "Unknown User",
+			store:   store,
+			event:   `{"version":"1","triggerSource":"UserMigration_Authentication","userName":"missing","request":{"password":"test123456A"},"response":{}}`,
+			wantErr: errLegacyUserNotFound,
+		},
+		{
+			name:    "Unsupported Hash",
+			store:   store,
+			event:   `{"version":"1","triggerSource":"UserMigration_Authentication","userName":"broken","request":{"password":"password"},"response":{}}`,
+			wantErr: errors.New("unsupported password hash format"),
+		},
+		{
+			name:       "Forgot Password",
+			store:      store,
+			event:      `{"version":"1","triggerSource":"UserMigration_ForgotPassword","userName":"unverified","request":{},"response":{}}`,
+			wantStatus: "RESET_REQUIRED",
+			wantAttrs:  map[string]string{"email": "unverified@example.com", "email_verified": "true"},
+		},
+		{
+			name:    "Store Unavailable",
+			store:   unavailableStore{},
+			event:   `{"version":"1","triggerSource":"UserMigration_Authentication","userName":"test","request":{"password":"test123456A"},"response":{}}`,
+			wantErr: errors.New("connection refused"),
+		},
+		{
+			name:    "Not Configured",
+			event:   `{"version":"1","triggerSource":"UserMigration_Authentication","userName":"test","request":{"password":"test123456A"},"response":{}}`,
+			wantErr: errMigrationUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.store != nil {
+				opts = append(opts, WithLegacyUserStore(tt.store))
+			}
+
+			var got events.CognitoEventUserPoolsMigrateUser
+			err := handle(t, NewHandler(opts...), tt.event, &got)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, got.FinalUserStatus)
+			assert.Equal(t, "SUPPRESS", got.MessageAction)
+			assert.Equal(t, tt.wantAttrs, got.UserAttributes)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package triggers
+
+import (
+	"github.com/whatisusername/toon-tank-user-service/internal/email"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/legacy"
+	"github.com/whatisusername/toon-tank-user-service/internal/username"
+)
+
+type Option func(*Handler)
+
+func WithUsernameValidator(v *username.Validator) Option {
+	return func(h *Handler) {
+		h.usernames = v
+	}
+}
+
+func WithEmailValidator(v *email.Validator) Option {
+	return func(h *Handler) {
+		h.emails = v
+	}
+}
+
+func WithEventPublisher(p cevents.EventPublisher) Option {
+	return func(h *Handler) {
+		h.events = p
+	}
+}
+
+func WithLegacyUserStore(store legacy.LegacyUserStore) Option {
+	return func(h *Handler) {
+		h.legacyUsers = store
+	}
+}
@@ -0,0 +1,38 @@
+package triggers
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+)
+
+const triggerPostConfirmationSignUp = "PostConfirmation_ConfirmSignUp"
+
+// postConfirmation announces newly confirmed players so other services can set
+// up what a player needs, such as a profile. The user is already confirmed by
+// the time this runs, so a failure to publish is logged rather than failing
+// the confirmation.
+func (h *Handler) postConfirmation(ctx context.Context, event events.CognitoEventUserPoolsPostConfirmation) (events.CognitoEventUserPoolsPostConfirmation, error) {
+	if event.TriggerSource != triggerPostConfirmationSignUp {
+		return event, nil
+	}
+
+	logger := logging.FromContext(ctx)
+
+	e, err := cevents.New(cevents.TypeUserConfirmed, 1, correlationID(ctx), cevents.UserConfirmed{
+		Username: event.UserName,
+		PlayerID: event.Request.UserAttributes["sub"],
+		Email:    event.Request.UserAttributes["email"],
+	})
+	if err != nil {
+		logger.Error("Failed to build event", "type", cevents.TypeUserConfirmed, "error", err)
+		return event, nil
+	}
+
+	if err = h.events.Publish(ctx, e); err != nil {
+		logger.Error("Failed to publish event", "type", e.Type, "eventId", e.ID, "error", err)
+	}
+	return event, nil
+}
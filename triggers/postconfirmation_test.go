@@ -0,0 +1,67 @@
+package triggers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cevents "github.com/whatisusername/toon-tank-user-service/internal/events"
+)
+
+func TestHandler_postConfirmation(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    string
+		wantData string
+	}{
+		{
+			name:     "Confirm Sign-up",
+			event:    `{"version":"1","triggerSource":"PostConfirmation_ConfirmSignUp","userName":"test","request":{"userAttributes":{"sub":"fake_sub","email":"test@example.com"}},"response":{}}`,
+			wantData: `{"username":"test","playerId":"fake_sub","email":"test@example.com"}`,
+		},
+		{
+			name:  "Confirm Forgot Password",
+			event: `{"version":"1","triggerSource":"PostConfirmation_ConfirmForgotPassword","userName":"test","request":{"userAttributes":{"sub":"fake_sub"}},"response":{}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := cevents.NewMemoryPublisher()
+			h := NewHandler(WithEventPublisher(publisher))
+			ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "fake_aws_request_id"})
+
+			resp, err := h.HandleRequest(ctx, []byte(tt.event))
+			require.NoError(t, err)
+			got, ok := resp.(events.CognitoEventUserPoolsPostConfirmation)
+			require.True(t, ok, "unexpected response type %T", resp)
+			assert.Equal(t, "test", got.UserName)
+
+			published := publisher.Events()
+			if tt.wantData == "" {
+				assert.Empty(t, published)
+				return
+			}
+			require.Len(t, published, 1)
+			assert.Equal(t, cevents.TypeUserConfirmed, published[0].Type)
+			assert.Equal(t, "fake_aws_request_id", published[0].CorrelationID)
+			assert.JSONEq(t, tt.wantData, string(published[0].Data))
+		})
+	}
+}
+
+func TestHandler_postConfirmationPublishFailure(t *testing.T) {
+	h := NewHandler(WithEventPublisher(failingPublisher{}))
+
+	var got events.CognitoEventUserPoolsPostConfirmation
+	err := handle(t, h, `{"version":"1","triggerSource":"PostConfirmation_ConfirmSignUp","userName":"test","request":{"userAttributes":{"sub":"fake_sub"}},"response":{}}`, &got)
+	assert.NoError(t, err, "a publish failure must not fail the confirmation")
+}
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(context.Context, ...cevents.Event) error {
+	return assert.AnError
+}
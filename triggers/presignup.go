@@ -0,0 +1,41 @@
+package triggers
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/whatisusername/toon-tank-user-service/internal/guest"
+	"github.com/whatisusername/toon-tank-user-service/internal/logging"
+	"github.com/whatisusername/toon-tank-user-service/internal/validation"
+)
+
+const (
+	triggerPreSignUpExternalProvider = "PreSignUp_ExternalProvider"
+	triggerPreSignUpAdminCreateUser  = "PreSignUp_AdminCreateUser"
+)
+
+// preSignUp applies the same username and email rules as the API to sign-ups
+// that reach Cognito directly, e.g. through the hosted UI. Returning the
+// violations as the error makes Cognito reject the sign-up with their
+// messages.
+func (h *Handler) preSignUp(ctx context.Context, event events.CognitoEventUserPoolsPreSignup) (events.CognitoEventUserPoolsPreSignup, error) {
+	var violations validation.Violations
+
+	// Federated users get a username generated by Cognito, and guests one
+	// generated by the API, which the policy is not meant for.
+	generated := event.TriggerSource == triggerPreSignUpExternalProvider ||
+		event.TriggerSource == triggerPreSignUpAdminCreateUser && guest.IsUsername(event.UserName)
+	if !generated {
+		violations = append(violations, h.usernames.Validate("username", event.UserName)...)
+	}
+	if address, ok := event.Request.UserAttributes["email"]; ok {
+		_, emailViolations := h.emails.Validate("email", address)
+		violations = append(violations, emailViolations...)
+	}
+
+	if len(violations) > 0 {
+		logging.FromContext(ctx).Info("Rejected sign-up", "username", event.UserName, "violations", len(violations))
+		return event, violations
+	}
+	return event, nil
+}
@@ -0,0 +1,70 @@
+package triggers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/whatisusername/toon-tank-user-service/internal/email"
+	"github.com/whatisusername/toon-tank-user-service/internal/username"
+	"github.com/whatisusername/toon-tank-user-service/internal/validation"
+)
+
+func TestHandler_preSignUp(t *testing.T) {
+	h := NewHandler(WithEmailValidator(email.NewValidator(false, email.NewBlocklist("mailinator.com"))))
+
+	tests := []struct {
+		name      string
+		event     string
+		wantCodes []string
+	}{
+		{
+			name:  "OK",
+			event: `{"version":"1","triggerSource":"PreSignUp_SignUp","userName":"test","request":{"userAttributes":{"email":"test@example.com"}},"response":{}}`,
+		},
+		{
+			name:      "Reserved Username",
+			event:     `{"version":"1","triggerSource":"PreSignUp_SignUp","userName":"admin","request":{"userAttributes":{"email":"test@example.com"}},"response":{}}`,
+			wantCodes: []string{username.CodeReserved},
+		},
+		{
+			name:      "Disposable Email",
+			event:     `{"version":"1","triggerSource":"PreSignUp_AdminCreateUser","userName":"test","request":{"userAttributes":{"email":"test@mailinator.com"}},"response":{}}`,
+			wantCodes: []string{email.CodeDomainBlocked},
+		},
+		{
+			name:  "External Provider Username",
+			event: `{"version":"1","triggerSource":"PreSignUp_ExternalProvider","userName":"google_1234567890","request":{"userAttributes":{"email":"test@example.com"}},"response":{}}`,
+		},
+		{
+			name:  "Guest Username",
+			event: `{"version":"1","triggerSource":"PreSignUp_AdminCreateUser","userName":"guest0123456789abcdef0123456789abcdef","request":{"userAttributes":{}},"response":{}}`,
+		},
+		{
+			name:      "Guest Username Signing Up",
+			event:     `{"version":"1","triggerSource":"PreSignUp_SignUp","userName":"guest0123456789abcdef0123456789abcdef","request":{"userAttributes":{"email":"test@example.com"}},"response":{}}`,
+			wantCodes: []string{username.CodeTooLong},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got events.CognitoEventUserPoolsPreSignup
+			err := handle(t, h, tt.event, &got)
+			if len(tt.wantCodes) == 0 {
+				require.NoError(t, err)
+				assert.False(t, got.Response.AutoConfirmUser)
+				return
+			}
+
+			var violations validation.Violations
+			require.True(t, errors.As(err, &violations), "expected violations, got %v", err)
+			codes := make([]string, len(violations))
+			for i, v := range violations {
+				codes[i] = v.Code
+			}
+			assert.Equal(t, tt.wantCodes, codes)
+		})
+	}
+}
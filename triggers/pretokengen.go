@@ -0,0 +1,48 @@
+package triggers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// PlayerIDClaim carries the player's ID in ID and access tokens. The player
+// ID is the Cognito sub, which unlike the username never changes.
+const PlayerIDClaim = "toontank:player_id"
+
+var errMissingSub = errors.New("user has no sub attribute")
+
+// preTokenGeneration handles version 1 of the trigger, which can only change
+// the ID token.
+func (h *Handler) preTokenGeneration(_ context.Context, event events.CognitoEventUserPoolsPreTokenGen) (events.CognitoEventUserPoolsPreTokenGen, error) {
+	claims, err := playerClaims(event.Request.UserAttributes)
+	if err != nil {
+		return event, err
+	}
+
+	event.Response.ClaimsOverrideDetails.ClaimsToAddOrOverride = claims
+	return event, nil
+}
+
+// preTokenGenerationV2 handles the later versions of the trigger, which can
+// change the access token as well. The API reads claims from access tokens.
+func (h *Handler) preTokenGenerationV2(_ context.Context, event events.CognitoEventUserPoolsPreTokenGenV2) (events.CognitoEventUserPoolsPreTokenGenV2, error) {
+	claims, err := playerClaims(event.Request.UserAttributes)
+	if err != nil {
+		return event, err
+	}
+
+	details := &event.Response.ClaimsAndScopeOverrideDetails
+	details.IDTokenGeneration.ClaimsToAddOrOverride = claims
+	details.AccessTokenGeneration.ClaimsToAddOrOverride = claims
+	return event, nil
+}
+
+func playerClaims(attributes map[string]string) (map[string]string, error) {
+	sub := attributes["sub"]
+	if sub == "" {
+		return nil, errMissingSub
+	}
+	return map[string]string{PlayerIDClaim: sub}, nil
+}
@@ -0,0 +1,49 @@
+package triggers
+
+import (
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_preTokenGeneration(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   string
+		wantErr bool
+	}{
+		{
+			name:  "OK",
+			event: `{"version":"1","triggerSource":"TokenGeneration_Authentication","userName":"test","request":{"userAttributes":{"sub":"fake_sub"},"groupConfiguration":{}},"response":{}}`,
+		},
+		{
+			name:    "Missing Sub",
+			event:   `{"version":"1","triggerSource":"TokenGeneration_RefreshTokens","userName":"test","request":{"userAttributes":{}},"response":{}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got events.CognitoEventUserPoolsPreTokenGen
+			err := handle(t, NewHandler(), tt.event, &got)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errMissingSub)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{PlayerIDClaim: "fake_sub"}, got.Response.ClaimsOverrideDetails.ClaimsToAddOrOverride)
+		})
+	}
+}
+
+func TestHandler_preTokenGenerationV2(t *testing.T) {
+	var got events.CognitoEventUserPoolsPreTokenGenV2
+	err := handle(t, NewHandler(), `{"version":"2","triggerSource":"TokenGeneration_Authentication","userName":"test","request":{"userAttributes":{"sub":"fake_sub"},"scopes":["openid"]},"response":{}}`, &got)
+	require.NoError(t, err)
+
+	want := map[string]string{PlayerIDClaim: "fake_sub"}
+	assert.Equal(t, want, got.Response.ClaimsAndScopeOverrideDetails.IDTokenGeneration.ClaimsToAddOrOverride)
+	assert.Equal(t, want, got.Response.ClaimsAndScopeOverrideDetails.AccessTokenGeneration.ClaimsToAddOrOverride)
+}